@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/dimasma0305/ctfify/function/template"
 	"github.com/dimasma0305/ctfify/function/template/challenge"
 	"github.com/dimasma0305/ctfify/function/template/other"
 	"github.com/dimasma0305/ctfify/function/template/solver"
@@ -19,6 +20,9 @@ var addFlag struct {
 	TemplateSolver    string
 	TemplateChallenge string
 	TemplateOther     string
+	GZChallenge       string
+	GZType            string
+	Author            string
 }
 
 type info struct {
@@ -95,7 +99,21 @@ var addCmd = &cobra.Command{
 it can be a --template like pwn template of writeup template
 that i specialy crafted`,
 	Run: func(cmd *cobra.Command, args []string) {
-		if addFlag.TemplateSolver != "" {
+		if addFlag.GZChallenge != "" {
+			if !cmd.Flags().Changed("name") {
+				log.Fatal("--name is required with --gz-challenge")
+			}
+			if addFlag.Author == "" {
+				log.Fatal("--author is required with --gz-challenge")
+			}
+			if err := challenge.GZChallenge(addFlag.Destination, addFlag.GZChallenge, challenge.GZChallengeInfo{
+				Name:   addFlag.Name,
+				Author: addFlag.Author,
+				Type:   addFlag.GZType,
+			}); err != nil {
+				log.Fatal(err)
+			}
+		} else if addFlag.TemplateSolver != "" {
 			switch addFlag.TemplateSolver {
 			case solverTemplateList["writeup"].name:
 				other.Writeup(addFlag.Destination, addFlag)
@@ -149,6 +167,24 @@ func completerBuilder(tmplList map[string]info) func(cmd *cobra.Command, args []
 	}
 }
 
+// manifestCompleter completes the names of the templates of kind listed in
+// templates/manifest.yaml
+func manifestCompleter(kind string) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		templates, err := template.List(kind, "")
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		matches := make([]string, 0)
+		for _, t := range templates {
+			if strings.HasPrefix(t.Name, toComplete) {
+				matches = append(matches, t.Name+"\t"+t.Description)
+			}
+		}
+		return matches, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(addCmd)
 	addCmd.Flags().StringVarP(&addFlag.Name, "name", "n", "{.Name}", "Name")
@@ -156,6 +192,9 @@ func init() {
 	addCmd.Flags().StringVar(&addFlag.TemplateSolver, "solver", "", "solver template")
 	addCmd.Flags().StringVar(&addFlag.TemplateChallenge, "challenge", "", "challenge template")
 	addCmd.Flags().StringVar(&addFlag.TemplateOther, "other", "", "other template")
+	addCmd.Flags().StringVar(&addFlag.GZChallenge, "gz-challenge", "", "gzcli challenge scaffold, see ctfify templates list --type gz-challenge")
+	addCmd.Flags().StringVar(&addFlag.GZType, "gz-type", "StaticAttachment", "challenge type of the --gz-challenge scaffold (StaticAttachment, DynamicContainer)")
+	addCmd.Flags().StringVar(&addFlag.Author, "author", "", "challenge author, required by --gz-challenge")
 	if err := addCmd.RegisterFlagCompletionFunc("solver", completerBuilder(solverTemplateList)); err != nil {
 		log.Fatal(err)
 	}
@@ -165,4 +204,7 @@ func init() {
 	if err := addCmd.RegisterFlagCompletionFunc("other", completerBuilder(otherTemplateList)); err != nil {
 		log.Fatal(err)
 	}
+	if err := addCmd.RegisterFlagCompletionFunc("gz-challenge", manifestCompleter(challenge.GZVariantType)); err != nil {
+		log.Fatal(err)
+	}
 }
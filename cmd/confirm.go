@@ -0,0 +1,16 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// confirm asks a yes/no question on stdin and defaults to no
+func confirm(format string, elem ...any) bool {
+	fmt.Printf(format+" [y/N]: ", elem...)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
 
 	"github.com/dimasma0305/ctfify/function/gzcli"
 	"github.com/dimasma0305/ctfify/function/log"
-	"github.com/dimasma0305/ctfify/function/template/other"
+	"github.com/dimasma0305/ctfify/function/scraper/ctfd"
 	"github.com/spf13/cobra"
 )
 
@@ -18,8 +22,31 @@ type tcommandFlags struct {
 	scriptFlag       string
 	createTeamsFlag  string
 	createTeamsEmail string
+	registerTeams    string
+	registerGame     string
+	registerDivision string
+	registerInvite   string
+	registerEmail    bool
 	deleteUsersFlag  bool
+	excludeAdmins    bool
+	onlyUnverified   bool
+	yes              bool
 	updateGameFlag   bool
+	canaryFlag       bool
+	syncWorkers      int
+	lintFlag         bool
+	testChallenges   bool
+	importCTFdFlag   string
+	ctfdUsername     string
+	ctfdPassword     string
+	ctfdAuthor       string
+	exportArchive    string
+	anonymize        bool
+	includeFlags     bool
+	reconcileFlag    bool
+	testContainer    string
+	stopContainer    string
+	solveFlag        bool
 }
 
 var commandFlags tcommandFlags
@@ -32,14 +59,29 @@ var gzcliCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		switch {
 		case commandFlags.initFlag:
-			other.CTFTemplate(".", map[string]string{})
+			runInit()
 			return
 
 		case commandFlags.syncFlag:
 			gz := gzcli.MustInit()
 			gz.UpdateGame = commandFlags.updateGameFlag
+			gz.Canary = commandFlags.canaryFlag
+			gz.SyncWorkers = commandFlags.syncWorkers
 			gz.MustSync()
 
+		case commandFlags.lintFlag:
+			issues, err := gzcli.Lint()
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, issue := range issues {
+				log.Error("%s", issue)
+			}
+			if len(issues) > 0 {
+				log.Fatal(fmt.Errorf("%d problems found", len(issues)))
+			}
+			log.Info("All challenges are valid")
+
 		case commandFlags.ctftimeFlag:
 			generateCTFTimeFeed(gzcli.MustInit())
 
@@ -52,8 +94,64 @@ var gzcliCmd = &cobra.Command{
 		case commandFlags.createTeamsEmail != "":
 			handleTeamCreation(commandFlags.createTeamsEmail, true)
 
+		case commandFlags.importCTFdFlag != "":
+			if err := gzcli.ImportCTFd(commandFlags.importCTFdFlag, &ctfd.Creds{
+				Username: commandFlags.ctfdUsername,
+				Password: commandFlags.ctfdPassword,
+			}, commandFlags.ctfdAuthor, "."); err != nil {
+				log.Fatal(err)
+			}
+
+		case commandFlags.testChallenges:
+			testChallenges()
+
+		case commandFlags.registerTeams != "":
+			registerTeams()
+
 		case commandFlags.deleteUsersFlag:
-			gzcli.MustInit().MustDeleteAllUser()
+			deleteUsers()
+
+		case commandFlags.exportArchive != "":
+			archive, err := gzcli.MustInit().ExportArchive(gzcli.ArchiveOptions{
+				Dir:          commandFlags.exportArchive,
+				Anonymize:    commandFlags.anonymize,
+				IncludeFlags: commandFlags.includeFlags,
+			})
+			if err != nil {
+				log.Fatal(fmt.Errorf("archive export failed: %w", err))
+			}
+			log.Info("Exported %d challenges and %d teams to %s", len(archive.Challenges), len(archive.Scoreboard), commandFlags.exportArchive)
+
+		case commandFlags.testContainer != "":
+			runTestContainer()
+
+		case commandFlags.stopContainer != "":
+			if err := gzcli.MustInit().StopTestContainer(commandFlags.stopContainer); err != nil {
+				log.Fatal(err)
+			}
+			log.Info("Stopped test container of %s", commandFlags.stopContainer)
+
+		case commandFlags.reconcileFlag:
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+
+			// Logs, git and script output go to stderr so stdout only
+			// holds the JSON summary
+			stdout := os.Stdout
+			os.Stdout = os.Stderr
+			summary, err := gzcli.MustInit().Reconcile(ctx)
+			os.Stdout = stdout
+			if err != nil {
+				log.Fatal(fmt.Errorf("reconcile failed: %w", err))
+			}
+			enc := json.NewEncoder(stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(summary); err != nil {
+				log.Fatal(fmt.Errorf("JSON encoding failed: %w", err))
+			}
+			if summary.Failed > 0 {
+				os.Exit(1)
+			}
 
 		default:
 			cmd.Help()
@@ -63,16 +161,111 @@ var gzcliCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(gzcliCmd)
+	gzcliCmd.PersistentFlags().StringVar(&gameTitle, "game-title", os.Getenv("CTFIFY_GAME"), "Work on this game of conf.yaml, the event or one of the events (same as CTFIFY_GAME)")
+	gzcliCmd.PersistentFlags().StringVar(&emailTemplate, "email-template", "", "HTML template of credential emails (default .gzctf/email-templates/credentials.html, then the built in one)")
 	flags := gzcliCmd.Flags()
 
 	flags.BoolVar(&commandFlags.initFlag, "init", false, "Initialize new CTF structure")
 	flags.BoolVar(&commandFlags.syncFlag, "sync", false, "Synchronize CTF data")
-	flags.BoolVar(&commandFlags.ctftimeFlag, "ctftime-scoreboard", false, "Generate CTFTime scoreboard feed")
+	flags.BoolVar(&commandFlags.lintFlag, "lint", false, "Validate every challenge.yml and report problems with line numbers")
+	flags.BoolVar(&commandFlags.testChallenges, "test-challenges", false, "Run every solver/ against the locally deployed challenge and check it prints a flag")
+	flags.BoolVar(&commandFlags.ctftimeFlag, "ctftime-scoreboard", false, "Generate CTFTime scoreboard feed with per-team task solves")
 	flags.StringVar(&commandFlags.scriptFlag, "run-script", "", "Execute custom script")
 	flags.StringVar(&commandFlags.createTeamsFlag, "create-teams", "", "Batch create teams")
 	flags.StringVar(&commandFlags.createTeamsEmail, "create-teams-and-send-email", "", "Create teams and send emails")
-	flags.BoolVar(&commandFlags.deleteUsersFlag, "delete-all-user", false, "Remove all users")
+	flags.StringVar(&commandFlags.registerTeams, "register-teams", "", "Create teams from a CSV and register them to a game")
+	flags.StringVar(&commandFlags.registerGame, "game", "", "Game title used by --register-teams (default the event of conf.yaml)")
+	flags.StringVar(&commandFlags.registerDivision, "division", "", "Division used by --register-teams instead of the CSV division column")
+	flags.StringVar(&commandFlags.registerInvite, "invite", "", "Game invite code used by --register-teams")
+	flags.BoolVar(&commandFlags.registerEmail, "send-email", false, "Mail the credentials of the teams --register-teams creates")
+	flags.BoolVar(&commandFlags.deleteUsersFlag, "delete-all-user", false, "Remove all users, after exporting them to the backup directory")
+	flags.BoolVar(&commandFlags.excludeAdmins, "exclude-admins", false, "Keep admin accounts when used with --delete-all-user")
+	flags.BoolVar(&commandFlags.onlyUnverified, "only-unverified", false, "Only delete users without a confirmed email when used with --delete-all-user")
+	flags.BoolVarP(&commandFlags.yes, "yes", "y", false, "Skip confirmation of --delete-all-user")
+	flags.StringVar(&commandFlags.exportArchive, "export-archive", "", "Write challenges, attachments, final scoreboard and solve statistics into this directory as JSON and markdown")
+	flags.BoolVar(&commandFlags.anonymize, "anonymize", false, "Replace team names with their rank and drop player names in --export-archive")
+	flags.BoolVar(&commandFlags.includeFlags, "include-flags", false, "Include static flags in --export-archive")
+	flags.StringVar(&commandFlags.testContainer, "test-container", "", "Start the platform test container of a challenge and print its entry")
+	flags.BoolVar(&commandFlags.solveFlag, "solve", false, "Run the solver/ of the challenge against the --test-container, then stop it")
+	flags.StringVar(&commandFlags.stopContainer, "stop-test-container", "", "Stop the platform test container of a challenge")
+	flags.BoolVar(&commandFlags.reconcileFlag, "reconcile", false, "Pull, sync and start, restart or stop the changed challenges once, then print a JSON summary on stdout and everything else on stderr")
 	flags.BoolVar(&commandFlags.updateGameFlag, "update-game", false, "Update the game")
+	flags.StringVar(&commandFlags.importCTFdFlag, "import-ctfd", "", "Import challenges from a CTFd url into the current directory")
+	flags.StringVar(&commandFlags.ctfdUsername, "ctfd-username", "", "CTFd username used by --import-ctfd")
+	flags.StringVar(&commandFlags.ctfdPassword, "ctfd-password", "", "CTFd password used by --import-ctfd")
+	flags.StringVar(&commandFlags.ctfdAuthor, "author", "", "Author of the challenges imported by --import-ctfd, which CTFd does not record")
+	flags.IntVar(&commandFlags.syncWorkers, "sync-workers", 0, "Number of challenges synced concurrently (default 8)")
+	addInitFlags(flags)
+	flags.BoolVar(&commandFlags.canaryFlag, "canary", false, "Deploy changed challenges to the canary game before the live game")
+}
+
+func testChallenges() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	results, err := gzcli.TestChallenges(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHALLENGE\tPASSED\tTIME\tFLAG/ERROR")
+	for _, result := range results {
+		detail := result.Flag
+		if !result.Passed {
+			failed++
+			detail = result.Error
+		}
+		fmt.Fprintf(w, "%s\t%t\t%dms\t%s\n", result.Challenge, result.Passed, result.DurationMs, detail)
+	}
+	w.Flush()
+	if failed > 0 {
+		log.Fatal(fmt.Errorf("%d of %d challenges failed their solver", failed, len(results)))
+	}
+}
+
+func runTestContainer() {
+	gz := gzcli.MustInit()
+	if !commandFlags.solveFlag {
+		info, err := gz.TestContainer(commandFlags.testContainer)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Test container of %s is %s at %s until %s", commandFlags.testContainer, info.Status, info.Entry, info.ExpectStopAt.Local().Format(time.Kitchen))
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	info, result, err := gz.SolveTestContainer(ctx, commandFlags.testContainer)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !result.Passed {
+		log.Fatal(fmt.Errorf("solver of %s failed against %s: %s", result.Challenge, info.Entry, result.Error))
+	}
+	log.Info("Solved %s against %s in %dms: %s", result.Challenge, info.Entry, result.DurationMs, result.Flag)
+}
+
+func deleteUsers() {
+	gz := gzcli.MustInit()
+	plan, err := gz.PlanUserDeletion(gzcli.UserFilter{
+		ExcludeAdmins:  commandFlags.excludeAdmins,
+		OnlyUnverified: commandFlags.onlyUnverified,
+	})
+	if err != nil {
+		log.Fatal(fmt.Errorf("listing users failed: %w", err))
+	}
+	if len(plan.Users) == 0 && len(plan.Teams) == 0 {
+		log.Info("No users match, nothing to delete")
+		return
+	}
+	if !commandFlags.yes && !confirm("Delete %d users and %d teams?", len(plan.Users), len(plan.Teams)) {
+		return
+	}
+	if _, err := gz.DeleteUsers(plan); err != nil {
+		log.Fatal(fmt.Errorf("user deletion failed: %w", err))
+	}
 }
 
 func generateCTFTimeFeed(gz *gzcli.GZ) {
@@ -84,6 +277,33 @@ func generateCTFTimeFeed(gz *gzcli.GZ) {
 	}
 }
 
+func registerTeams() {
+	registrations, err := gzcli.MustInit().RegisterTeams(gzcli.RegisterTeamsForm{
+		CSV:        commandFlags.registerTeams,
+		Game:       commandFlags.registerGame,
+		Division:   commandFlags.registerDivision,
+		InviteCode: commandFlags.registerInvite,
+		SendEmail:  commandFlags.registerEmail,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	failed := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TEAM\tUSER\tDIVISION\tSTATUS\tERROR")
+	for _, registration := range registrations {
+		if registration.Status == gzcli.RegistrationFailed {
+			failed++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", registration.Team, registration.Username, registration.Division, registration.Status, registration.Error)
+	}
+	w.Flush()
+	if failed > 0 {
+		log.Fatal(fmt.Errorf("%d of %d teams failed to register", failed, len(registrations)))
+	}
+}
+
 func handleTeamCreation(url string, sendEmail bool) {
 	if err := gzcli.MustInit().CreateTeams(url, sendEmail); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var annotateFlags struct {
+	list  bool
+	since time.Duration
+}
+
+// annotateCmd records operator notes on the event timeline
+var annotateCmd = &cobra.Command{
+	Use:   "annotate <note>",
+	Short: "Record a timestamped operator note, or list them with --list",
+	Long: `Record a timestamped note such as "power outage at venue" in the oplog
+(.gzcli/oplog.jsonl), next to the other recorded operator actions, so gaps
+in scoreboard history or failed deploys can be explained afterwards.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if annotateFlags.list {
+			var since time.Time
+			if annotateFlags.since > 0 {
+				since = time.Now().Add(-annotateFlags.since)
+			}
+			annotations, err := gzcli.Annotations(since)
+			if err != nil {
+				log.Fatal(err)
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TIME\tOPERATOR\tNOTE")
+			for _, op := range annotations {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", op.Time.Format(time.RFC3339), op.Operator, op.Details["note"])
+			}
+			w.Flush()
+			return
+		}
+
+		note := strings.TrimSpace(strings.Join(args, " "))
+		if note == "" {
+			log.Fatal("A note is required, for example: gzcli annotate \"power outage at venue\"")
+		}
+		if err := gzcli.Annotate(note); err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Annotated: %s", note)
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(annotateCmd)
+	annotateCmd.Flags().BoolVar(&annotateFlags.list, "list", false, "List recorded notes instead of adding one")
+	annotateCmd.Flags().DurationVar(&annotateFlags.since, "since", 0, "Only list notes from this long ago, such as 24h")
+}
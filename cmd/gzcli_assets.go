@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var assetsFlags struct {
+	yes bool
+}
+
+// assetsCmd manages the files stored on the platform
+var assetsCmd = &cobra.Command{
+	Use:   "assets",
+	Short: "Manage the files stored on the platform",
+	Long: `Manage the files stored on the platform through /api/assets, such as images
+linked from challenge descriptions. Files are compared by hash, so uploading
+a file the platform already has returns the existing asset.`,
+}
+
+var assetsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the assets of the platform",
+	Run: func(cmd *cobra.Command, args []string) {
+		assets, err := gzcli.MustInit().ListAssets()
+		if err != nil {
+			log.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "HASH\tNAME\tURL")
+		for _, asset := range assets {
+			fmt.Fprintf(w, "%s\t%s\t/assets/%s/%s\n", asset.Hash, asset.Name, asset.Hash, asset.Name)
+		}
+		w.Flush()
+	},
+}
+
+var assetsUploadCmd = &cobra.Command{
+	Use:   "upload <file>...",
+	Short: "Upload files the platform does not have yet",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		assets, uploaded, err := gzcli.MustInit().UploadAssets(args)
+		for i, asset := range assets {
+			status := "exists"
+			if uploaded[i] {
+				status = "uploaded"
+			}
+			log.Info("%s %s: /assets/%s/%s", args[i], status, asset.Hash, asset.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var assetsDeleteCmd = &cobra.Command{
+	Use:   "delete <hash>...",
+	Short: "Delete assets from the platform",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !assetsFlags.yes && !confirm("Delete %d assets? Challenges linking them break", len(args)) {
+			log.Info("Aborted")
+			return
+		}
+		if err := gzcli.MustInit().DeleteAssets(args); err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Deleted %d assets", len(args))
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(assetsCmd)
+	assetsCmd.AddCommand(assetsListCmd, assetsUploadCmd, assetsDeleteCmd)
+
+	assetsDeleteCmd.Flags().BoolVarP(&assetsFlags.yes, "yes", "y", false, "Skip confirmation")
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+// backupCmd snapshots the gzcli cache, including issued team credentials
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the gzcli cache into a timestamped archive",
+	Run: func(cmd *cobra.Command, args []string) {
+		archive, err := gzcli.Backup()
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Backup written to %s", archive)
+	},
+}
+
+var backupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List available backups",
+	Run: func(cmd *cobra.Command, args []string) {
+		backups, err := gzcli.ListBackups()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, backup := range backups {
+			fmt.Println(backup)
+		}
+	},
+}
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore the gzcli cache from a backup archive",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := gzcli.RestoreBackup(args[0]); err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Restored cache from %s", args[0])
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(backupCmd)
+	backupCmd.AddCommand(backupListCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+}
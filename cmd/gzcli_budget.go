@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var budgetFlags struct {
+	players int
+}
+
+// budgetCmd reports attachment sizes against the per-category budgets
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Report attachment sizes against category budgets",
+	Run: func(cmd *cobra.Command, args []string) {
+		reports, err := gzcli.AttachmentBudgetReport()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CATEGORY\tCHALLENGE\tSIZE\tBUDGET\tBANDWIDTH\tSTATUS")
+
+		var total int64
+		overBudget := 0
+		for _, r := range reports {
+			budget, status := "-", "ok"
+			if r.Budget > 0 {
+				budget = gzcli.FormatSize(r.Budget)
+			}
+			if r.OverBudget {
+				status = "over budget"
+				overBudget++
+			}
+			bandwidth := r.Size * int64(budgetFlags.players)
+			total += bandwidth
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Category, r.Challenge,
+				gzcli.FormatSize(r.Size), budget, gzcli.FormatSize(bandwidth), status)
+		}
+		w.Flush()
+
+		log.Info("Estimated bandwidth for %d players: %s", budgetFlags.players, gzcli.FormatSize(total))
+		if overBudget > 0 {
+			log.Fatal(fmt.Errorf("%d attachments exceed their category budget", overBudget))
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(budgetCmd)
+	budgetCmd.Flags().IntVar(&budgetFlags.players, "players", 1, "Expected number of players downloading each attachment")
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var buildFlags struct {
+	only    []string
+	workers int
+	unpin   bool
+}
+
+// buildCmd builds and pushes the images of the DynamicContainer challenges
+var buildCmd = &cobra.Command{
+	Use:   "build",
+	Short: "Build and push the images of the DynamicContainer challenges",
+	Long: `Build the Dockerfile in src/ of every DynamicContainer challenge, push it to
+the registry of RegistryConfig in .gzctf/appsettings.json, logging in with its
+credentials, and pin the pushed digest as the containerImage of the synced
+challenge. Pins are kept in image-digests.yaml, which is meant to be
+committed, and later syncs keep deploying the pinned digest until the next
+build or --unpin. Without a RegistryConfig the image is pushed to the
+registry its containerImage names.`,
+	Example: `  gzcli build
+  gzcli build --only "Baby Web" --only "Pwn Me"
+  gzcli build --unpin --only "Baby Web"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if buildFlags.unpin {
+			images, err := gzcli.UnpinImages(buildFlags.only)
+			if err != nil {
+				log.Fatal(err)
+			}
+			for _, image := range images {
+				log.Info("Unpinned %s, the next sync deploys it as written in challenge.yml", image)
+			}
+			if len(images) == 0 {
+				log.Info("No pinned images")
+			}
+			return
+		}
+
+		results, err := gzcli.MustInit().BuildImages(buildFlags.only, buildFlags.workers)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(results) == 0 {
+			log.Info("No DynamicContainer challenges to build")
+			return
+		}
+
+		failed := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CATEGORY\tCHALLENGE\tIMAGE\tRESULT")
+		for _, result := range results {
+			status := result.Digest
+			if result.Error != "" {
+				status = "failed"
+				failed++
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", result.Category, result.Name, result.Image, status)
+		}
+		w.Flush()
+		if failed > 0 {
+			log.Fatal(fmt.Errorf("%d of %d images failed to build", failed, len(results)))
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(buildCmd)
+	flags := buildCmd.Flags()
+
+	flags.StringArrayVar(&buildFlags.only, "only", nil, "Only build or unpin the named challenge, can be repeated")
+	flags.BoolVar(&buildFlags.unpin, "unpin", false, "Drop the pinned digests instead of building")
+	flags.IntVar(&buildFlags.workers, "workers", 4, "Number of images built in parallel")
+}
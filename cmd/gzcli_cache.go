@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var cacheFlags struct {
+	all bool
+	yes bool
+}
+
+// cacheCmd inspects and invalidates the .gzcli cache
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect and invalidate the .gzcli cache",
+	Long: `Inspect and invalidate the .gzcli cache. State mirrored from the platform,
+like the game and challenge ids, expires after a day and is ignored once
+conf.yaml points to another url, so a stale entry is never used against the
+wrong GZCTF instance. Local data like generated team credentials never
+expires.`,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the cache entries with their age and state",
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := gzcli.ListCache()
+		if err != nil {
+			log.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "KEY\tSIZE\tAGE\tEXPIRES\tSTATE")
+		for _, entry := range entries {
+			age, expires := "-", "never"
+			if !entry.Created.IsZero() {
+				age = time.Since(entry.Created).Round(time.Second).String()
+			}
+			if !entry.Expires.IsZero() {
+				expires = entry.Expires.Local().Format("Jan 2 15:04")
+			}
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", entry.Key, entry.Size, age, expires, entry.State)
+		}
+		w.Flush()
+	},
+}
+
+var cacheShowCmd = &cobra.Command{
+	Use:   "show <key>",
+	Short: "Print the metadata and content of a cache entry",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		entry, data, err := gzcli.ShowCache(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("# key: %s\n# state: %s\n", entry.Key, entry.State)
+		if entry.URL != "" {
+			fmt.Printf("# url: %s\n", entry.URL)
+		}
+		if !entry.Created.IsZero() {
+			fmt.Printf("# created: %s\n", entry.Created.Local().Format(time.RFC3339))
+		}
+		if !entry.Expires.IsZero() {
+			fmt.Printf("# expires: %s\n", entry.Expires.Local().Format(time.RFC3339))
+		}
+		os.Stdout.Write(data)
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear [key...]",
+	Short: "Remove cache entries",
+	Long: `Remove the given cache entries. Without keys it removes every entry
+mirrored from the platform along with expired and stale ones, so the next
+sync asks the platform again. --all also removes local data like generated
+team credentials. Logs of .gzcli are never removed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if cacheFlags.all && len(args) > 0 {
+			log.Fatal(fmt.Errorf("--all cannot be used with keys"))
+		}
+		if cacheFlags.all && !cacheFlags.yes && !confirm("Remove every cache entry, including team credentials?") {
+			return
+		}
+		removed, err := gzcli.ClearCache(args, cacheFlags.all)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, key := range removed {
+			log.InfoH2("Removed %s", key)
+		}
+		log.Info("Removed %d cache entries", len(removed))
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheListCmd, cacheShowCmd, cacheClearCmd)
+
+	flags := cacheClearCmd.Flags()
+	flags.BoolVar(&cacheFlags.all, "all", false, "Also remove local data like team credentials")
+	flags.BoolVarP(&cacheFlags.yes, "yes", "y", false, "Skip confirmation of --all")
+}
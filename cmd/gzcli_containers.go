@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var containersTTLFlags struct {
+	max time.Duration
+}
+
+// containersCmd groups commands about running challenge containers
+var containersCmd = &cobra.Command{
+	Use:   "containers",
+	Short: "Inspect running challenge containers",
+}
+
+var containersTTLCmd = &cobra.Command{
+	Use:   "ttl",
+	Short: "List running containers living longer than --max",
+	Run: func(cmd *cobra.Command, args []string) {
+		report, err := gzcli.MustInit().ContainerTTLReport(containersTTLFlags.max)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(report) == 0 {
+			log.Info("No container lives longer than %s", containersTTLFlags.max)
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHALLENGE\tTEAM\tSTARTED\tSTOPS\tLIFETIME")
+		for _, c := range report {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.Challenge, c.Team,
+				c.StartedAt.Format(time.RFC3339), c.StopAt.Format(time.RFC3339), c.Lifetime.Round(time.Minute))
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(containersCmd)
+	containersCmd.AddCommand(containersTTLCmd)
+	containersTTLCmd.Flags().DurationVar(&containersTTLFlags.max, "max", 2*time.Hour, "Longest acceptable container lifetime")
+}
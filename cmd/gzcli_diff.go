@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var diffFlags struct {
+	since string
+}
+
+// diffCmd prints which challenges changed since a git ref as JSON
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show challenges changed since a git ref",
+	Run: func(cmd *cobra.Command, args []string) {
+		diffs, err := gzcli.DiffSince(diffFlags.since)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diffs); err != nil {
+			log.Fatal(fmt.Errorf("JSON encoding failed: %w", err))
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVar(&diffFlags.since, "since", "HEAD", "Git ref to compare the working tree against")
+}
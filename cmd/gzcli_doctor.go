@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var doctorFlags struct {
+	apiLatency bool
+	rounds     int
+}
+
+// doctorCmd checks the config and connectivity to the GZCTF instance
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check conf.yaml and connectivity to the GZCTF instance",
+	Long: `Check conf.yaml and connectivity to the GZCTF instance.
+With --api-latency the read calls of a sync are repeated and the latency of
+each endpoint is shown, next to the latencies recorded by the last sync, to
+tell whether the platform or gzcli is the bottleneck.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		log.Info("Checking gzcli setup")
+		if err := gzcli.Doctor(); err != nil {
+			log.Fatal(err)
+		}
+		log.Info("All checks passed")
+
+		if !doctorFlags.apiLatency {
+			return
+		}
+		if stats, err := gzcli.LastSyncLatency(); err == nil {
+			log.Info("API latency of the last sync")
+			printLatency(stats)
+		}
+		log.Info("Measuring API latency over %d rounds", doctorFlags.rounds)
+		stats, err := gzcli.MeasureAPILatency(doctorFlags.rounds)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printLatency(stats)
+	},
+}
+
+func printLatency(stats []gzapi.LatencyStats) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ENDPOINT\tCALLS\tERRORS\tMEAN\tP50\tP95\tMAX")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\n", s.Endpoint, s.Count, s.Errors,
+			s.Mean().Round(time.Millisecond), s.Quantile(0.5), s.Quantile(0.95), s.Max.Round(time.Millisecond))
+	}
+	w.Flush()
+}
+
+func init() {
+	gzcliCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorFlags.apiLatency, "api-latency", false, "Show per-endpoint API latency of the last sync and of a live measurement")
+	doctorCmd.Flags().IntVar(&doctorFlags.rounds, "rounds", 5, "Rounds of read calls measured by --api-latency")
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var emailsReportFlags struct {
+	email string
+	csv   bool
+}
+
+// emailsCmd groups commands about the team credential emails
+var emailsCmd = &cobra.Command{
+	Use:   "emails",
+	Short: "Inspect team credential emails",
+}
+
+var emailsReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Show when each credential email was sent and how the SMTP server answered",
+	Run: func(cmd *cobra.Command, args []string) {
+		records, err := gzcli.ReadEmailLog(emailsReportFlags.email)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if emailsReportFlags.csv {
+			w := csv.NewWriter(os.Stdout)
+			w.Write([]string{"Time", "Email", "Username", "TeamName", "Sent", "Response"})
+			for _, r := range records {
+				w.Write([]string{r.Time.Format(time.RFC3339), r.Email, r.Username, r.TeamName, strconv.FormatBool(r.Sent), r.Response})
+			}
+			w.Flush()
+			if err := w.Error(); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TIME\tEMAIL\tTEAM\tSENT\tRESPONSE")
+		for _, r := range records {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", r.Time.Format(time.RFC3339), r.Email, r.TeamName, r.Sent, r.Response)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(emailsCmd)
+	emailsCmd.AddCommand(emailsReportCmd)
+	emailsReportCmd.Flags().StringVar(&emailsReportFlags.email, "email", "", "Only show emails sent to this address")
+	emailsReportCmd.Flags().BoolVar(&emailsReportFlags.csv, "csv", false, "Export the report as CSV")
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var gameSetFlags struct {
+	writeupDeadline string
+	writeupNote     string
+}
+
+// gameCmd groups commands that manage the current game
+var gameCmd = &cobra.Command{
+	Use:   "game",
+	Short: "Manage the current game",
+}
+
+var gameSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Update selected fields of the current game",
+	Run: func(cmd *cobra.Command, args []string) {
+		var form gzcli.GameWriteupForm
+
+		if cmd.Flags().Changed("writeup-deadline") {
+			deadline, err := time.Parse(time.RFC3339, gameSetFlags.writeupDeadline)
+			if err != nil {
+				log.Fatal(fmt.Errorf("invalid --writeup-deadline, expected RFC3339: %w", err))
+			}
+			form.Deadline = &deadline
+		}
+		if cmd.Flags().Changed("writeup-note") {
+			form.Note = &gameSetFlags.writeupNote
+		}
+
+		if form.Deadline == nil && form.Note == nil {
+			cmd.Help()
+			return
+		}
+		gzcli.MustInit().MustSetGameWriteup(form)
+	},
+}
+
+var gameSetPosterCmd = &cobra.Command{
+	Use:   "set-poster <file>",
+	Short: "Upload a file as the poster of the current game",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, err := gzcli.MustInit().SetGamePoster(args[0]); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(gameCmd)
+	gameCmd.AddCommand(gameSetCmd, gameSetPosterCmd)
+	flags := gameSetCmd.Flags()
+
+	flags.StringVar(&gameSetFlags.writeupDeadline, "writeup-deadline", "", "Writeup deadline (RFC3339)")
+	flags.StringVar(&gameSetFlags.writeupNote, "writeup-note", "", "Writeup note shown to players")
+}
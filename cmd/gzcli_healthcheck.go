@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var healthcheckFlags struct {
+	watch bool
+}
+
+// healthcheckCmd probes challenges that define a healthcheck block
+var healthcheckCmd = &cobra.Command{
+	Use:   "healthcheck",
+	Short: "Probe challenges with a healthcheck block",
+	Long: `Probe every challenge that defines a healthcheck block in challenge.yml.
+With --watch the probes repeat on their interval and the restart script of a
+challenge runs after its number of consecutive failures. Results are appended
+to .gzcli/health.jsonl.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if healthcheckFlags.watch {
+			if err := gzcli.WatchHealth(ctx); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+
+		results, err := gzcli.RunHealthchecks(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		unhealthy := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHALLENGE\tHEALTHY\tTIME\tERROR")
+		for _, result := range results {
+			if !result.Healthy {
+				unhealthy++
+			}
+			fmt.Fprintf(w, "%s\t%t\t%dms\t%s\n", result.Challenge, result.Healthy, result.DurationMs, result.Error)
+		}
+		w.Flush()
+		if unhealthy > 0 {
+			log.Fatal(fmt.Errorf("%d of %d challenges are unhealthy", unhealthy, len(results)))
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(healthcheckCmd)
+	healthcheckCmd.Flags().BoolVar(&healthcheckFlags.watch, "watch", false, "Keep probing and restart challenges that keep failing")
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var releaseHintsFlags struct {
+	watch    bool
+	interval time.Duration
+}
+
+// releaseHintsCmd publishes the timed hints of challenge.yml once they are due
+var releaseHintsCmd = &cobra.Command{
+	Use:   "release-hints",
+	Short: "Publish the timed hints that are due",
+	Long: `Publish the hints of challenge.yml written as {text, releaseAfter} once
+releaseAfter has passed since the game start. Sync only publishes the hints
+that are due at the time it runs, so run this from cron or keep it running
+with --watch during the event. Released hints are announced like synced ones
+and recorded in the oplog.`,
+	Example: `  hints:
+    - Look at the cookies
+    - text: The secret key is short
+      releaseAfter: 6h
+
+  gzcli release-hints --watch`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := gzcli.MustInit().ReleaseHints(ctx, releaseHintsFlags.interval, releaseHintsFlags.watch); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(releaseHintsCmd)
+	flags := releaseHintsCmd.Flags()
+
+	flags.BoolVar(&releaseHintsFlags.watch, "watch", false, "Keep releasing hints every --interval until interrupted")
+	flags.DurationVar(&releaseHintsFlags.interval, "interval", time.Minute, "Check interval used by --watch")
+}
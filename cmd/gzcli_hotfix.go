@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var hotfixFlags gzcli.Hotfix
+
+// hotfixCmd ships a fix to a single challenge during an event
+var hotfixCmd = &cobra.Command{
+	Use:   "hotfix",
+	Short: "Pull, sync one challenge, wait until it is ready and announce the fix",
+	Long: `Ship a fix to a single challenge during an event: pull the repository,
+sync only that challenge, run its restart script if it has one, wait until it
+is enabled and its healthcheck passes, post a notice and webhook announcement
+and record the hotfix in the oplog.`,
+	Example: `  gzcli hotfix --challenge "baby heap" -m "fixed off-by-one"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := gzcli.MustInit().Hotfix(ctx, hotfixFlags); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(hotfixCmd)
+	flags := hotfixCmd.Flags()
+
+	flags.StringVarP(&hotfixFlags.Challenge, "challenge", "c", "", "Name of the fixed challenge")
+	flags.StringVarP(&hotfixFlags.Message, "message", "m", "", "What was fixed, used in the announcement and the oplog")
+	flags.BoolVar(&hotfixFlags.NoPull, "no-pull", false, "Do not git pull before syncing")
+	flags.DurationVar(&hotfixFlags.Timeout, "timeout", 2*time.Minute, "How long to wait for the challenge to become ready")
+	hotfixCmd.MarkFlagRequired("challenge")
+	hotfixCmd.MarkFlagRequired("message")
+}
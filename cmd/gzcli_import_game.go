@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var importGameFlags struct {
+	game string
+	dir  string
+}
+
+// importGameCmd materializes an existing GZCTF game into challenge.yml files
+var importGameCmd = &cobra.Command{
+	Use:   "import-game",
+	Short: "Import challenges from an existing game",
+	Long:  `Pull all challenges from an existing gz::ctf game and write them as challenge.yml folders`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if importGameFlags.game == "" {
+			log.Fatal("--game is required")
+		}
+		gzcli.MustInit().MustImportGame(importGameFlags.game, importGameFlags.dir)
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(importGameCmd)
+	flags := importGameCmd.Flags()
+
+	flags.StringVar(&importGameFlags.game, "game", "", "Title of the game to import")
+	flags.StringVar(&importGameFlags.dir, "dir", ".", "Destination directory")
+}
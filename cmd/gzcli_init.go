@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/dimasma0305/ctfify/function/template/other"
+	"github.com/spf13/pflag"
+)
+
+var initFlags struct {
+	info    *other.CTFInfo
+	noInput bool
+}
+
+// addInitFlags registers the answers of the init wizard as flags so
+// --init can run without a terminal
+func addInitFlags(flags *pflag.FlagSet) {
+	info := other.DefaultCTFInfo()
+	initFlags.info = info
+
+	flags.BoolVar(&initFlags.noInput, "no-input", false, "Do not prompt during --init, use flags and defaults only")
+	flags.StringVar(&info.Url, "url", info.Url, "GZCTF URL used by --init")
+	flags.StringVar(&info.PublicEntry, "public-entry", info.PublicEntry, "Public entry for challenge containers used by --init")
+	flags.StringVar(&info.DiscordWebhook, "discord-webhook", info.DiscordWebhook, "Discord webhook used by --init")
+	flags.StringVar(&info.Title, "title", info.Title, "Event title used by --init")
+	flags.StringVar(&info.Start, "start", info.Start, "Event start time (RFC3339) used by --init")
+	flags.StringVar(&info.End, "end", info.End, "Event end time (RFC3339) used by --init")
+	flags.StringSliceVar(&info.Categories, "categories", info.Categories, "Category folders created by --init")
+	flags.StringVar(&info.ContainerProvider, "container-provider", info.ContainerProvider, "Container provider used by --init (Docker, Kubernetes)")
+	flags.BoolVar(&info.EnableEmail, "smtp", info.EnableEmail, "Configure SMTP for credential emails during --init")
+	flags.StringVar(&info.SenderAddress, "smtp-sender", info.SenderAddress, "Sender address used by --init")
+	flags.StringVar(&info.SmtpHost, "smtp-host", info.SmtpHost, "SMTP host used by --init")
+	flags.IntVar(&info.SmtpPort, "smtp-port", info.SmtpPort, "SMTP port used by --init")
+	flags.StringVar(&info.SmtpUsername, "smtp-username", info.SmtpUsername, "SMTP username used by --init")
+	flags.StringVar(&info.SmtpPassword, "smtp-password", info.SmtpPassword, "SMTP password used by --init")
+}
+
+// runInit asks the remaining init questions on a terminal, with the flag
+// values as defaults, and generates the event folder
+func runInit() {
+	info := initFlags.info
+	if !initFlags.noInput && isTerminal(os.Stdin) {
+		info.Ask(os.Stdin, os.Stdout)
+	}
+	if err := other.GenerateCTF(".", info); err != nil {
+		log.Fatal(err)
+	}
+	log.Info("Initialized %s", info.Title)
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
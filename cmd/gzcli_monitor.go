@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+// monitorCmd streams live platform events of the current game
+var monitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: "Stream live game events and submissions",
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if err := gzcli.MustInit().Monitor(ctx, gzcli.LogHubMessage); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(monitorCmd)
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var platformSetFlags struct {
+	title                     string
+	slogan                    string
+	footerInfo                string
+	registration              string
+	activeOnRegister          bool
+	captcha                   bool
+	emailConfirmation         bool
+	emailDomains              string
+	defaultLifetime           int
+	extensionDuration         int
+	renewalWindow             int
+	autoDestroyOnLimitReached bool
+	at                        string
+}
+
+// platformCmd groups commands that manage the global platform config
+var platformCmd = &cobra.Command{
+	Use:   "platform",
+	Short: "Manage the global config of the GZCTF instance",
+}
+
+var platformShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the global config of the platform",
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := gzcli.MustInit().Platform()
+		if err != nil {
+			log.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "title\t%s\n", config.GlobalConfig.Title)
+		fmt.Fprintf(w, "slogan\t%s\n", config.GlobalConfig.Slogan)
+		fmt.Fprintf(w, "registration open\t%t\n", config.AccountPolicy.AllowRegister)
+		fmt.Fprintf(w, "active on register\t%t\n", config.AccountPolicy.ActiveOnRegister)
+		fmt.Fprintf(w, "captcha\t%t\n", config.AccountPolicy.UseCaptcha)
+		fmt.Fprintf(w, "email confirmation\t%t\n", config.AccountPolicy.EmailConfirmationRequired)
+		fmt.Fprintf(w, "email domains\t%s\n", config.AccountPolicy.EmailDomainList)
+		fmt.Fprintf(w, "container lifetime\t%dm\n", config.ContainerPolicy.DefaultLifetime)
+		fmt.Fprintf(w, "container extension\t%dm\n", config.ContainerPolicy.ExtensionDuration)
+		fmt.Fprintf(w, "container renewal window\t%dm\n", config.ContainerPolicy.RenewalWindow)
+		fmt.Fprintf(w, "auto destroy on limit\t%t\n", config.ContainerPolicy.AutoDestroyOnLimitReached)
+		w.Flush()
+	},
+}
+
+var platformSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Change selected fields of the platform config",
+	Long: `Change selected fields of the platform config. Only the flags given are
+changed. With --at the change waits until that time, so closing registration
+when the event starts can be scheduled:
+
+  gzcli platform set --registration closed --at 2025-01-01T00:00:00Z`,
+	Run: func(cmd *cobra.Command, args []string) {
+		changed := cmd.Flags().Changed
+		var settings gzcli.PlatformSettings
+		if changed("title") {
+			settings.Title = &platformSetFlags.title
+		}
+		if changed("slogan") {
+			settings.Slogan = &platformSetFlags.slogan
+		}
+		if changed("footer") {
+			settings.FooterInfo = &platformSetFlags.footerInfo
+		}
+		if changed("registration") {
+			var open bool
+			switch platformSetFlags.registration {
+			case "open":
+				open = true
+			case "closed":
+			default:
+				log.Fatal(fmt.Errorf("invalid --registration %q, expected open or closed", platformSetFlags.registration))
+			}
+			settings.AllowRegister = &open
+		}
+		if changed("active-on-register") {
+			settings.ActiveOnRegister = &platformSetFlags.activeOnRegister
+		}
+		if changed("captcha") {
+			settings.UseCaptcha = &platformSetFlags.captcha
+		}
+		if changed("email-confirmation") {
+			settings.EmailConfirmationRequired = &platformSetFlags.emailConfirmation
+		}
+		if changed("email-domains") {
+			settings.EmailDomainList = &platformSetFlags.emailDomains
+		}
+		if changed("container-lifetime") {
+			settings.DefaultLifetime = &platformSetFlags.defaultLifetime
+		}
+		if changed("container-extension") {
+			settings.ExtensionDuration = &platformSetFlags.extensionDuration
+		}
+		if changed("container-renewal-window") {
+			settings.RenewalWindow = &platformSetFlags.renewalWindow
+		}
+		if changed("auto-destroy") {
+			settings.AutoDestroyOnLimitReached = &platformSetFlags.autoDestroyOnLimitReached
+		}
+
+		if changed("at") {
+			at, err := time.Parse(time.RFC3339, platformSetFlags.at)
+			if err != nil {
+				log.Fatal(fmt.Errorf("invalid --at, expected RFC3339: %w", err))
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			log.Info("Waiting until %s to change the platform config", at.Format(time.RFC3339))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Until(at)):
+			}
+		}
+		// Log in after waiting so a scheduled change never uses a stale session
+		if err := gzcli.MustInit().SetPlatform(settings); err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Platform config updated")
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(platformCmd)
+	platformCmd.AddCommand(platformShowCmd)
+	platformCmd.AddCommand(platformSetCmd)
+	flags := platformSetCmd.Flags()
+
+	flags.StringVar(&platformSetFlags.title, "title", "", "Site title")
+	flags.StringVar(&platformSetFlags.slogan, "slogan", "", "Site slogan")
+	flags.StringVar(&platformSetFlags.footerInfo, "footer", "", "Site footer")
+	flags.StringVar(&platformSetFlags.registration, "registration", "", "Open or close registration (open, closed)")
+	flags.BoolVar(&platformSetFlags.activeOnRegister, "active-on-register", false, "Activate accounts on registration")
+	flags.BoolVar(&platformSetFlags.captcha, "captcha", false, "Require a captcha")
+	flags.BoolVar(&platformSetFlags.emailConfirmation, "email-confirmation", false, "Require email confirmation")
+	flags.StringVar(&platformSetFlags.emailDomains, "email-domains", "", "Comma separated email domains allowed to register")
+	flags.IntVar(&platformSetFlags.defaultLifetime, "container-lifetime", 0, "Default container lifetime in minutes")
+	flags.IntVar(&platformSetFlags.extensionDuration, "container-extension", 0, "Container extension in minutes")
+	flags.IntVar(&platformSetFlags.renewalWindow, "container-renewal-window", 0, "Container renewal window in minutes")
+	flags.BoolVar(&platformSetFlags.autoDestroyOnLimitReached, "auto-destroy", false, "Destroy the oldest container when the limit is reached")
+	flags.StringVar(&platformSetFlags.at, "at", "", "Apply the change at this time (RFC3339)")
+}
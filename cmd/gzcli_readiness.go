@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var readinessFlags struct {
+	json bool
+}
+
+// readinessCmd prints the go/no-go checklist of the event
+var readinessCmd = &cobra.Command{
+	Use:   "readiness",
+	Short: "Pass/fail checklist to run before the event opens",
+	Long: `Check that the event is ready to open: challenges lint clean and synced,
+healthchecks green, poster uploaded, no team pending approval, SMTP and the
+scoreboard reachable. Exits non-zero when any check fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		// With --json, logs and the final verdict go to stderr so stdout only
+		// holds the JSON document
+		stdout := os.Stdout
+		if readinessFlags.json {
+			os.Stdout = os.Stderr
+		}
+		report, err := gzcli.MustInit().Readiness(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if readinessFlags.json {
+			enc := json.NewEncoder(stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(report); err != nil {
+				log.Fatal(fmt.Errorf("JSON encoding failed: %w", err))
+			}
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "CHECK\tRESULT\tDETAIL")
+			for _, check := range report.Checks {
+				result := "PASS"
+				if !check.Passed {
+					result = "FAIL"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", check.Name, result, check.Detail)
+			}
+			w.Flush()
+		}
+
+		if !report.Ready {
+			log.Fatal(fmt.Errorf("%s is not ready", report.Event))
+		}
+		log.Info("%s is ready", report.Event)
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(readinessCmd)
+	readinessCmd.Flags().BoolVar(&readinessFlags.json, "json", false, "Print the checklist as JSON on stdout and logs on stderr")
+}
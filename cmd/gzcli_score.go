@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var scoreAdjustFlags struct {
+	adjustment gzcli.ScoreAdjustment
+	yes        bool
+}
+
+// scoreCmd groups manual score correction commands
+var scoreCmd = &cobra.Command{
+	Use:   "score",
+	Short: "Manual score corrections",
+}
+
+var scoreAdjustCmd = &cobra.Command{
+	Use:   "adjust",
+	Short: "Change a team's participation status to correct the scoreboard",
+	Long: `Change a team's participation status in the current game.
+Suspended removes the team and its solves from the scoreboard, Accepted restores it.
+Every adjustment is recorded in the oplog.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		adjustment := scoreAdjustFlags.adjustment
+		if adjustment.Team == "" || adjustment.Reason == "" {
+			log.Fatal("--team and --reason are required")
+		}
+		if !scoreAdjustFlags.yes && !confirm("Set %s to %s (%s)?", adjustment.Team, adjustment.Status, adjustment.Reason) {
+			log.Info("Aborted")
+			return
+		}
+		if err := gzcli.MustInit().AdjustScore(adjustment); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(scoreCmd)
+	scoreCmd.AddCommand(scoreAdjustCmd)
+	flags := scoreAdjustCmd.Flags()
+
+	flags.StringVar(&scoreAdjustFlags.adjustment.Team, "team", "", "Team name")
+	flags.StringVar(&scoreAdjustFlags.adjustment.Status, "status", "Suspended", "New participation status (Accepted, Suspended, Rejected, Pending)")
+	flags.StringVar(&scoreAdjustFlags.adjustment.Reason, "reason", "", "Reason recorded in the oplog")
+	flags.BoolVarP(&scoreAdjustFlags.yes, "yes", "y", false, "Skip confirmation")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var scoreboardFlags struct {
+	record   bool
+	interval time.Duration
+	replay   bool
+	csv      bool
+}
+
+// scoreboardCmd records and replays scoreboard history of the current game
+var scoreboardCmd = &cobra.Command{
+	Use:   "scoreboard",
+	Short: "Record scoreboard snapshots and export the score progression",
+	Long: `Record scoreboard snapshots of the current game into .gzcli/scoreboard.jsonl
+with --record, or export the recorded history with --replay as a per-team
+score progression in JSON or, with --csv, one row per team and snapshot.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		switch {
+		case scoreboardFlags.record:
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			if err := gzcli.MustInit().RecordScoreboard(ctx, scoreboardFlags.interval); err != nil {
+				log.Fatal(err)
+			}
+		case scoreboardFlags.replay:
+			history, err := gzcli.ReadScoreboardHistory()
+			if err != nil {
+				log.Fatal(err)
+			}
+			if len(history) == 0 {
+				log.Fatal("No scoreboard history recorded, run gzcli scoreboard --record first")
+			}
+			if scoreboardFlags.csv {
+				if err := gzcli.WriteScoreboardCSV(os.Stdout, history); err != nil {
+					log.Fatal(err)
+				}
+				return
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(gzcli.ScoreboardProgression(history)); err != nil {
+				log.Fatal(fmt.Errorf("JSON encoding failed: %w", err))
+			}
+		default:
+			cmd.Help()
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(scoreboardCmd)
+	flags := scoreboardCmd.Flags()
+
+	flags.BoolVar(&scoreboardFlags.record, "record", false, "Poll the scoreboard and record snapshots until interrupted")
+	flags.DurationVar(&scoreboardFlags.interval, "interval", time.Minute, "Polling interval used by --record")
+	flags.BoolVar(&scoreboardFlags.replay, "replay", false, "Export the recorded scoreboard history")
+	flags.BoolVar(&scoreboardFlags.csv, "csv", false, "Export --replay as CSV instead of JSON")
+	scoreboardCmd.MarkFlagsMutuallyExclusive("record", "replay")
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+// secretCmd manages the encrypted secrets that `!secret name` values of
+// challenge.yml and conf.yaml resolve from
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage the encrypted secrets of .gzctf/secrets.enc",
+	Long: `Manage the secrets that "!secret name" values of challenge.yml and
+conf.yaml resolve from at sync time. They are stored AES-256-GCM encrypted in
+.gzctf/secrets.enc with a key derived by scrypt from ` + gzcli.SecretsKeyEnv + `. A name
+missing from the file falls back to the CTFIFY_SECRET_<NAME> environment
+variable.`,
+	Example: `  export ` + gzcli.SecretsKeyEnv + `=...
+  gzcli secret set flag_prod 'CTF{...}'
+  echo 'flags: [!secret flag_prod]' >> challenge.yml`,
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set <name> [value]",
+	Short: "Store a secret, reading the value from stdin when it is not given",
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var value string
+		if len(args) == 2 {
+			value = args[1]
+		} else {
+			line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+			if err != nil && line == "" {
+				log.Fatal(fmt.Errorf("read secret from stdin: %w", err))
+			}
+			value = strings.TrimRight(line, "\r\n")
+		}
+		if err := gzcli.SetSecret(args[0], value); err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Stored secret %s", args[0])
+	},
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print the value a secret resolves to",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := gzcli.GetSecret(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(value)
+	},
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the names of the stored secrets",
+	Run: func(cmd *cobra.Command, args []string) {
+		names, err := gzcli.ListSecrets()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+	},
+}
+
+var secretRmCmd = &cobra.Command{
+	Use:   "rm <name>",
+	Short: "Remove a stored secret",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := gzcli.DeleteSecret(args[0]); err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Removed secret %s", args[0])
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(secretCmd)
+	secretCmd.AddCommand(secretSetCmd, secretGetCmd, secretListCmd, secretRmCmd)
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var statsFlags struct {
+	csv      bool
+	json     bool
+	live     bool
+	interval time.Duration
+}
+
+// statsCmd reports solve counts, first bloods and the score distribution
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show per-challenge solves, first bloods and the score distribution",
+	Long: `Show the current score, solve count, solve rate and first blood of every
+challenge of the current game and how the team scores are distributed. Use
+--csv or --json for machine readable output, or --live to refresh the table
+every --interval during the event.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		gz := gzcli.MustInit()
+		if statsFlags.live {
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+			defer stop()
+			if err := gz.WatchStats(ctx, statsFlags.interval, func(stats *gzcli.Stats) {
+				// Clear the terminal before every refresh
+				fmt.Print("\033[H\033[2J")
+				printStats(stats)
+			}); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+
+		stats, err := gz.Stats()
+		if err != nil {
+			log.Fatal(err)
+		}
+		switch {
+		case statsFlags.csv:
+			if err := gzcli.WriteStatsCSV(os.Stdout, stats); err != nil {
+				log.Fatal(err)
+			}
+		case statsFlags.json:
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(stats); err != nil {
+				log.Fatal(fmt.Errorf("JSON encoding failed: %w", err))
+			}
+		default:
+			printStats(stats)
+		}
+	},
+}
+
+func printStats(stats *gzcli.Stats) {
+	fmt.Printf("%s, %d teams, %s\n\n", stats.Game, stats.Teams, stats.Time.Format(time.Kitchen))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tCHALLENGE\tSCORE\tSOLVES\tRATE\tFIRST BLOOD")
+	for _, challenge := range stats.Challenges {
+		firstBlood := "-"
+		if challenge.FirstBloodTime != nil {
+			firstBlood = fmt.Sprintf("%s (%s)", challenge.FirstBlood, challenge.FirstBloodTime.Local().Format("Jan 2 15:04"))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.0f%%\t%s\n", challenge.Category, challenge.Name, challenge.Score, challenge.Solves, challenge.SolveRate*100, firstBlood)
+	}
+	w.Flush()
+
+	if len(stats.Distribution) == 0 {
+		return
+	}
+	most := 0
+	for _, bucket := range stats.Distribution {
+		most = max(most, bucket.Teams)
+	}
+	fmt.Println("\nScore distribution")
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, bucket := range stats.Distribution {
+		bar := ""
+		if most > 0 {
+			bar = strings.Repeat("#", bucket.Teams*40/most)
+		}
+		fmt.Fprintf(w, "%d-%d\t%d\t%s\n", bucket.Min, bucket.Max, bucket.Teams, bar)
+	}
+	w.Flush()
+}
+
+func init() {
+	gzcliCmd.AddCommand(statsCmd)
+	flags := statsCmd.Flags()
+
+	flags.BoolVar(&statsFlags.csv, "csv", false, "Print one CSV row per challenge")
+	flags.BoolVar(&statsFlags.json, "json", false, "Print the statistics as JSON")
+	flags.BoolVar(&statsFlags.live, "live", false, "Refresh the table every --interval until interrupted")
+	flags.DurationVar(&statsFlags.interval, "interval", 30*time.Second, "Refresh interval used by --live")
+	statsCmd.MarkFlagsMutuallyExclusive("csv", "json", "live")
+}
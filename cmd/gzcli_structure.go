@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var structureFlags struct {
+	check bool
+	force bool
+}
+
+// structureCmd copies the .structure files into every challenge directory
+var structureCmd = &cobra.Command{
+	Use:   "structure",
+	Short: "Copy the .structure files into every challenge directory",
+	Long: `Copy the files of .structure into every challenge directory that lacks them.
+Files in .structure/<category>, such as .structure/web, are only copied to
+that category and override the common files. Files ending in .tmpl are
+rendered with {{.name}}, {{.slug}}, {{.category}}, {{.author}}, {{.port}}
+and {{.host}} and written without the suffix.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		results, err := gzcli.Structure(structureFlags.check, structureFlags.force)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if !structureFlags.check {
+			for _, result := range results {
+				if len(result.Created) > 0 {
+					log.Info("%s: wrote %s", result.Dir, strings.Join(result.Created, ", "))
+				}
+			}
+			return
+		}
+
+		incomplete := 0
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CHALLENGE\tDIR\tMISSING")
+		for _, result := range results {
+			if len(result.Missing) == 0 {
+				continue
+			}
+			incomplete++
+			fmt.Fprintf(w, "%s\t%s\t%s\n", result.Challenge, result.Dir, strings.Join(result.Missing, ", "))
+		}
+		w.Flush()
+		if incomplete > 0 {
+			log.Fatal(fmt.Errorf("%d of %d challenges are missing structure files", incomplete, len(results)))
+		}
+		log.Info("All %d challenges have the structure files", len(results))
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(structureCmd)
+	structureCmd.Flags().BoolVar(&structureFlags.check, "check", false, "Report missing structure files without writing anything")
+	structureCmd.Flags().BoolVar(&structureFlags.force, "force", false, "Overwrite files that already exist")
+}
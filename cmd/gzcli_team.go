@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var teamFlags struct {
+	realName  string
+	email     string
+	team      string
+	sendEmail bool
+	output    string
+	yes       bool
+	force     bool
+}
+
+// teamCmd manages the teams generated by --create-teams
+var teamCmd = &cobra.Command{
+	Use:   "team",
+	Short: "Manage generated teams",
+}
+
+var teamListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List generated teams",
+	Run: func(cmd *cobra.Command, args []string) {
+		teamsCreds, err := gzcli.ListTeamCreds()
+		if err != nil {
+			log.Fatal(err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "EMAIL\tUSERNAME\tTEAM\tCREATED\tEMAIL SENT")
+		for _, creds := range teamsCreds {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%t\n", creds.Email, creds.Username, creds.TeamName, creds.IsTeamCreated, creds.IsEmailAlreadySent)
+		}
+		w.Flush()
+	},
+}
+
+var teamCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a single team and its captain",
+	Run: func(cmd *cobra.Command, args []string) {
+		if teamFlags.realName == "" || teamFlags.email == "" || teamFlags.team == "" {
+			log.Fatal("--name, --email and --team are required")
+		}
+		creds, err := gzcli.MustInit().CreateTeam(teamFlags.realName, teamFlags.email, teamFlags.team, teamFlags.sendEmail)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Created team %s for %s", creds.TeamName, creds.Username)
+	},
+}
+
+var teamDeleteCmd = &cobra.Command{
+	Use:   "delete <email>",
+	Short: "Delete a generated team and its captain",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !teamFlags.yes && !confirm("Delete the team of %s?", args[0]) {
+			log.Info("Aborted")
+			return
+		}
+		if err := gzcli.MustInit().DeleteTeam(args[0]); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var teamResetPasswordCmd = &cobra.Command{
+	Use:   "reset-password <email>",
+	Short: "Reset the password of a generated team captain",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		creds, err := gzcli.MustInit().ResetTeamPassword(args[0], teamFlags.sendEmail)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Info("New password for %s: %s", creds.Username, creds.Password)
+	},
+}
+
+var teamResendEmailCmd = &cobra.Command{
+	Use:   "resend-email <email>",
+	Short: "Send the credentials email of a generated team again",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := gzcli.ResendTeamEmail(args[0]); err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Sent credentials to %s", args[0])
+	},
+}
+
+var teamRotatePasswordsCmd = &cobra.Command{
+	Use:   "rotate-passwords",
+	Short: "Reset the password of every generated team captain",
+	Long: `Reset the password of every generated team captain, for example after
+the credentials leaked or before a second round. The new passwords replace
+the stored credentials and are mailed again with --send-email.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !teamFlags.yes && !confirm("Reset the password of every generated team?") {
+			log.Info("Aborted")
+			return
+		}
+		rotated, err := gzcli.MustInit().RotateTeamPasswords(teamFlags.sendEmail)
+		log.Info("Rotated %d passwords", len(rotated))
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var teamPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete the stored credentials of the generated teams",
+	Long: `Delete the stored credentials of the generated teams once the event has
+ended, from the cache and from every archive in .gzcli-backups, or
+.gzcli-backups/<profile> with --profile. The teams and users stay on the
+platform. Set credsRetention in conf.yaml to have them expire on their own that long after the event end.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !teamFlags.yes && !confirm("Delete the stored team credentials?") {
+			log.Info("Aborted")
+			return
+		}
+		if err := gzcli.PurgeTeamCreds(teamFlags.force); err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Purged the team credentials")
+	},
+}
+
+var teamExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the credentials of generated teams as CSV",
+	Run: func(cmd *cobra.Command, args []string) {
+		out := os.Stdout
+		if teamFlags.output != "" {
+			f, err := os.OpenFile(teamFlags.output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+			if err != nil {
+				log.Fatal(err)
+			}
+			defer f.Close()
+			out = f
+		}
+		if err := gzcli.ExportTeamCreds(out); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(teamCmd)
+	teamCmd.AddCommand(teamListCmd, teamCreateCmd, teamDeleteCmd, teamResetPasswordCmd, teamResendEmailCmd, teamRotatePasswordsCmd, teamPurgeCmd, teamExportCmd)
+
+	teamCreateCmd.Flags().StringVar(&teamFlags.realName, "name", "", "Real name of the captain")
+	teamCreateCmd.Flags().StringVar(&teamFlags.email, "email", "", "Email of the captain")
+	teamCreateCmd.Flags().StringVar(&teamFlags.team, "team", "", "Team name")
+	teamCreateCmd.Flags().BoolVar(&teamFlags.sendEmail, "send-email", false, "Email the credentials")
+	teamDeleteCmd.Flags().BoolVarP(&teamFlags.yes, "yes", "y", false, "Skip confirmation")
+	teamResetPasswordCmd.Flags().BoolVar(&teamFlags.sendEmail, "send-email", false, "Email the new credentials")
+	teamRotatePasswordsCmd.Flags().BoolVar(&teamFlags.sendEmail, "send-email", false, "Email the new credentials")
+	teamRotatePasswordsCmd.Flags().BoolVarP(&teamFlags.yes, "yes", "y", false, "Skip confirmation")
+	teamPurgeCmd.Flags().BoolVar(&teamFlags.force, "force", false, "Purge before the event has ended")
+	teamPurgeCmd.Flags().BoolVarP(&teamFlags.yes, "yes", "y", false, "Skip confirmation")
+	teamExportCmd.Flags().StringVarP(&teamFlags.output, "output", "o", "", "Write the CSV to this file instead of stdout")
+}
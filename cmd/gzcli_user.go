@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/spf13/cobra"
+)
+
+var userFlags struct {
+	role           string
+	search         string
+	onlyUnverified bool
+	excludeAdmins  bool
+	yes            bool
+}
+
+// userCmd manages the accounts of the platform through the admin API
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage platform accounts",
+}
+
+func userFilter() gzcli.UserFilter {
+	return gzcli.UserFilter{
+		ExcludeAdmins:  userFlags.excludeAdmins,
+		OnlyUnverified: userFlags.onlyUnverified,
+		Role:           userFlags.role,
+		Search:         userFlags.search,
+	}
+}
+
+func printUsers(users []*gzapi.User) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USERNAME\tEMAIL\tROLE\tVERIFIED\tID")
+	for _, user := range users {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", user.UserName, user.Email, user.Role, user.EmailConfirmed, user.Id)
+	}
+	w.Flush()
+}
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List accounts",
+	Run: func(cmd *cobra.Command, args []string) {
+		users, err := gzcli.MustInit().ListUsers(userFilter())
+		if err != nil {
+			log.Fatal(err)
+		}
+		printUsers(users)
+	},
+}
+
+var userSearchCmd = &cobra.Command{
+	Use:   "search <username|email>",
+	Short: "Search accounts by username, email or real name",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		filter := userFilter()
+		filter.Search = args[0]
+		users, err := gzcli.MustInit().ListUsers(filter)
+		if err != nil {
+			log.Fatal(err)
+		}
+		printUsers(users)
+	},
+}
+
+// setRoleCmd returns a command that gives an account a fixed role
+func setRoleCmd(use, short, role string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <username|email>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			user, err := gzcli.MustInit().SetUserRole(args[0], role)
+			if err != nil {
+				log.Fatal(err)
+			}
+			log.Info("%s is now %s", user.UserName, user.Role)
+		},
+	}
+}
+
+var userRoleCmd = &cobra.Command{
+	Use:   "role <username|email> <Admin|Monitor|User|Banned>",
+	Short: "Change the role of an account",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		user, err := gzcli.MustInit().SetUserRole(args[0], args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Info("%s is now %s", user.UserName, user.Role)
+	},
+}
+
+var userActivateCmd = &cobra.Command{
+	Use:   "activate <username|email>",
+	Short: "Confirm the email of an account",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		user, err := gzcli.MustInit().ActivateUser(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Info("Activated %s", user.UserName)
+	},
+}
+
+var userDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete the accounts matching the filters, after exporting them to the backup directory",
+	Run: func(cmd *cobra.Command, args []string) {
+		gz := gzcli.MustInit()
+		plan, err := gz.PlanUserDeletion(userFilter())
+		if err != nil {
+			log.Fatal(fmt.Errorf("listing users failed: %w", err))
+		}
+		if len(plan.Users) == 0 && len(plan.Teams) == 0 {
+			log.Info("No users match, nothing to delete")
+			return
+		}
+		printUsers(plan.Users)
+		if !userFlags.yes && !confirm("Delete %d users and %d teams?", len(plan.Users), len(plan.Teams)) {
+			return
+		}
+		if _, err := gz.DeleteUsers(plan); err != nil {
+			log.Fatal(fmt.Errorf("user deletion failed: %w", err))
+		}
+	},
+}
+
+func init() {
+	gzcliCmd.AddCommand(userCmd)
+	userCmd.AddCommand(
+		userListCmd,
+		userSearchCmd,
+		userRoleCmd,
+		setRoleCmd("ban", "Ban an account", gzapi.RoleBanned),
+		setRoleCmd("unban", "Give a banned account the User role again", gzapi.RoleUser),
+		userActivateCmd,
+		userDeleteCmd,
+	)
+
+	for _, cmd := range []*cobra.Command{userListCmd, userSearchCmd, userDeleteCmd} {
+		cmd.Flags().StringVar(&userFlags.role, "role", "", "Only accounts with this role (Admin, Monitor, User, Banned)")
+		cmd.Flags().BoolVar(&userFlags.onlyUnverified, "unverified", false, "Only accounts without a confirmed email")
+	}
+	userListCmd.Flags().StringVar(&userFlags.search, "search", "", "Only accounts whose username, email or real name contain this")
+	userDeleteCmd.Flags().StringVar(&userFlags.search, "search", "", "Only accounts whose username, email or real name contain this")
+	userDeleteCmd.Flags().BoolVar(&userFlags.excludeAdmins, "exclude-admins", false, "Keep admin accounts")
+	userDeleteCmd.Flags().BoolVarP(&userFlags.yes, "yes", "y", false, "Skip confirmation")
+}
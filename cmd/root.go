@@ -6,15 +6,44 @@ package cmd
 import (
 	"os"
 
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
 	"github.com/spf13/cobra"
 )
 
+// version is set at build time by make release
+var version = "dev"
+
+var (
+	noEmoji       bool
+	messages      string
+	profile       string
+	gameTitle     string
+	emailTemplate string
+)
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "ctfify",
-	Short: "Tools for downloading CTF challenges from various platforms.",
+	Use:     "ctfify",
+	Version: version,
+	Short:   "Tools for downloading CTF challenges from various platforms.",
 	Long: `ctfify is a command-line tool designed to simplify the process of downloading and managing Capture The Flag (CTF) challenges.
 With ctfify, you can easily search for CTF challenges by name, category, or tag, and download them directly to your local machine with just a few commands.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if noEmoji {
+			log.SetPlain(true)
+		}
+		if messages != "" {
+			if err := log.LoadCatalog(messages); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if err := gzcli.SetProfile(profile); err != nil {
+			log.Fatal(err)
+		}
+		gzcli.SetGameTitle(gameTitle)
+		gzcli.SetEmailTemplate(emailTemplate)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -28,4 +57,7 @@ func Execute() {
 
 func init() {
 	// rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", os.Getenv("CTFIFY_PROFILE"), "Use .gzctf/profiles/<name>.yaml on top of conf.yaml, with its own cache and backups (same as CTFIFY_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&noEmoji, "no-emoji", false, "Plain output without colors or markers (same as CTFIFY_PLAIN=1)")
+	rootCmd.PersistentFlags().StringVar(&messages, "messages", "", "YAML file of id: format pairs rewording the sync, team, email, monitor and download messages of function/log/catalog.go (same as CTFIFY_MESSAGES)")
 }
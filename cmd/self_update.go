@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/dimasma0305/ctfify/function/gzcli"
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/dimasma0305/ctfify/function/selfupdate"
+	"github.com/spf13/cobra"
+)
+
+var selfUpdateFlags struct {
+	channel  string
+	check    bool
+	force    bool
+	noBackup bool
+}
+
+// selfUpdateCmd replaces the running binary with the latest GitHub release
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update ctfify to the latest release",
+	Long: `Download the ctfify binary of this platform from the latest GitHub release,
+check it against the checksums.txt of the release and replace the running
+executable with it. The checksum only guards the integrity of the download,
+it is published with the binary and does not prove who built it. Releases
+older than the running version are never installed, --force reinstalls the
+same one. The beta channel includes pre-releases. When run in a CTF
+directory the gzcli cache is backed up first, like gzcli backup does.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		release, err := selfupdate.Latest(selfUpdateFlags.channel)
+		if err != nil {
+			log.Fatal(err)
+		}
+		switch {
+		case release.TagName == version && !selfUpdateFlags.force:
+			log.Info("ctfify %s is up to date", version)
+			return
+		case release.TagName != version && !release.Newer(version):
+			log.Info("ctfify %s is newer than the latest %s release %s", version, selfUpdateFlags.channel, release.TagName)
+			return
+		}
+		if selfUpdateFlags.check {
+			log.Info("ctfify %s is available, running %s", release.TagName, version)
+			return
+		}
+		if !selfUpdateFlags.noBackup && gzcli.HasCache() {
+			archive, err := gzcli.Backup()
+			if err != nil {
+				log.Fatal(fmt.Errorf("backup before update failed, use --no-backup to skip it: %w", err))
+			}
+			log.Info("Backed up the cache to %s", archive)
+		}
+		log.Info("Updating ctfify %s to %s", version, release.TagName)
+		if err := selfupdate.Apply(release); err != nil {
+			log.Fatal(fmt.Errorf("update failed: %w", err))
+		}
+		log.Info("Updated to %s", release.TagName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+	selfUpdateCmd.Flags().StringVar(&selfUpdateFlags.channel, "channel", selfupdate.ChannelStable, "Release channel, stable or beta")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateFlags.check, "check", false, "Only report whether a newer release exists")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateFlags.force, "force", false, "Reinstall even when already on the latest release")
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateFlags.noBackup, "no-backup", false, "Skip the backup of the gzcli cache before updating")
+}
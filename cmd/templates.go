@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/dimasma0305/ctfify/function/template"
+	"github.com/dimasma0305/ctfify/function/template/challenge"
+	"github.com/spf13/cobra"
+)
+
+var templatesFlag struct {
+	Type   string
+	Search string
+}
+
+// templatesCmd groups commands for discovering the templates usable with add
+var templatesCmd = &cobra.Command{
+	Use:   "templates",
+	Short: "Discover the templates available to add",
+}
+
+var templatesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List templates with their descriptions and variables",
+	Run: func(cmd *cobra.Command, args []string) {
+		switch templatesFlag.Type {
+		case "", "solver", "challenge", "other", challenge.GZVariantType:
+		default:
+			log.Fatal(fmt.Errorf("unknown template type %q, expected solver, challenge, other or gz-challenge", templatesFlag.Type))
+		}
+
+		templates, err := template.List(templatesFlag.Type, templatesFlag.Search)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tNAME\tDESCRIPTION\tVARIABLES")
+		for _, info := range templates {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", info.Type, info.Name, info.Description, strings.Join(info.Variables, ", "))
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+	templatesCmd.AddCommand(templatesListCmd)
+	templatesListCmd.Flags().StringVar(&templatesFlag.Type, "type", "", "Only list templates of this type (solver, challenge, other or gz-challenge)")
+	templatesListCmd.Flags().StringVar(&templatesFlag.Search, "search", "", "Only list templates whose name or description contains this text")
+}
@@ -0,0 +1,75 @@
+package gzcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// webhookClient bounds a webhook call, so an endpoint that hangs cannot stall
+// the sync worker or hotfix that announces through it
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+type AnnounceConfig struct {
+	Hints   bool   `yaml:"hints"`
+	Webhook string `yaml:"webhook"`
+}
+
+// addedHints returns the hints in after that were not present in before
+func addedHints(before, after []string) []string {
+	var added []string
+	for _, hint := range after {
+		if !isExistInArray(hint, before) {
+			added = append(added, hint)
+		}
+	}
+	return added
+}
+
+// announceHints tells players about hints added to an existing challenge
+func announceHints(config *Config, game gzapi.GameHandle, challengeName string, hints []string) {
+	if config.Announce == nil || len(hints) == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("New hint for %s:\n- %s", challengeName, strings.Join(hints, "\n- "))
+	if config.Announce.Hints {
+		log.InfoH2("Announce new hint for %s", challengeName)
+		if _, err := game.CreateNotice(gzapi.NoticeForm{Content: message}); err != nil {
+			log.ErrorH2("Failed to create notice for %s: %v", challengeName, err)
+		}
+	}
+	if config.Announce.Webhook != "" {
+		if err := sendWebhook(config.Announce.Webhook, message); err != nil {
+			log.ErrorH2("Failed to send webhook for %s: %v", challengeName, err)
+		}
+	}
+}
+
+// sendWebhook posts message in a payload understood by Discord and Slack webhooks
+func sendWebhook(url string, message string) error {
+	body, err := json.Marshal(map[string]string{
+		"content": message,
+		"text":    message,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %d status", resp.StatusCode)
+	}
+	return nil
+}
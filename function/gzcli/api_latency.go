@@ -0,0 +1,84 @@
+package gzcli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+const (
+	apiLatencyCacheKey   = "api_latency"
+	defaultSlowCall      = 2 * time.Second
+	defaultLatencyRounds = 5
+)
+
+// setSlowCallLog logs every API call slower than slowApiCall of conf.yaml
+func setSlowCallLog(config *Config) error {
+	threshold := defaultSlowCall
+	if config.SlowApiCall != "" {
+		d, err := time.ParseDuration(config.SlowApiCall)
+		if err != nil {
+			return fmt.Errorf("invalid slowApiCall: %w", err)
+		}
+		threshold = d
+	}
+	gzapi.SetSlowCallLog(threshold, func(endpoint string, status int, took time.Duration) {
+		log.ErrorH2("Slow API call %s took %s (status %d)", endpoint, took.Round(time.Millisecond), status)
+	})
+	return nil
+}
+
+// saveAPILatency keeps the latency histograms of this run so doctor
+// --api-latency can show where the last sync spent its time
+func saveAPILatency() error {
+	stats := gzapi.Latency()
+	if len(stats) == 0 {
+		return nil
+	}
+	return setCache(apiLatencyCacheKey, stats)
+}
+
+// LastSyncLatency returns the latency histograms saved by the last sync
+func LastSyncLatency() ([]gzapi.LatencyStats, error) {
+	var stats []gzapi.LatencyStats
+	if err := GetCache(apiLatencyCacheKey, &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// MeasureAPILatency logs in and repeats the read calls a sync makes rounds
+// times, returning the latency histogram of each endpoint
+func MeasureAPILatency(rounds int) ([]gzapi.LatencyStats, error) {
+	if rounds <= 0 {
+		rounds = defaultLatencyRounds
+	}
+	config, err := GetConfig(&gzapi.GZAPI{})
+	if err != nil {
+		return nil, fmt.Errorf("config error: %w", err)
+	}
+	if err := setSlowCallLog(config); err != nil {
+		return nil, err
+	}
+
+	gzapi.ResetLatency()
+	api, err := gzapi.Init(config.Url, &config.Creds, config.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("cannot log in to %s: %w", config.Url, err)
+	}
+	for i := 0; i < rounds; i++ {
+		game, err := api.GetGameByTitle(config.Event.Title)
+		if err != nil {
+			return nil, fmt.Errorf("game %q: %w", config.Event.Title, err)
+		}
+		if _, err := game.GetChallenges(); err != nil {
+			return nil, err
+		}
+		if _, err := game.GetScoreboard(); err != nil {
+			return nil, err
+		}
+	}
+	return gzapi.Latency(), nil
+}
@@ -0,0 +1,276 @@
+package gzcli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// ArchiveOptions controls what ExportArchive publishes
+type ArchiveOptions struct {
+	Dir string
+	// Anonymize replaces team names with their rank and drops player names
+	Anonymize bool
+	// IncludeFlags adds the static flags of every challenge
+	IncludeFlags bool
+}
+
+// Archive is the post event bundle written to archive.json
+type Archive struct {
+	Title      string             `json:"title"`
+	Summary    string             `json:"summary,omitempty"`
+	Start      time.Time          `json:"start"`
+	End        time.Time          `json:"end"`
+	Exported   time.Time          `json:"exported"`
+	Challenges []ArchiveChallenge `json:"challenges"`
+	Scoreboard []ArchiveStanding  `json:"scoreboard"`
+}
+
+// ArchiveChallenge is one challenge with its final score and solve statistics
+type ArchiveChallenge struct {
+	Name        string        `json:"name"`
+	Category    string        `json:"category"`
+	Author      string        `json:"author,omitempty"`
+	Description string        `json:"description"`
+	Type        string        `json:"type"`
+	Value       int           `json:"value"`
+	FinalScore  int           `json:"finalScore"`
+	Solves      int           `json:"solves"`
+	FirstBlood  *ArchiveSolve `json:"firstBlood,omitempty"`
+	Hints       []string      `json:"hints,omitempty"`
+	Flags       []string      `json:"flags,omitempty"`
+	Attachment  string        `json:"attachment,omitempty"`
+}
+
+// ArchiveStanding is the final standing of one team
+type ArchiveStanding struct {
+	Rank   int            `json:"rank"`
+	Team   string         `json:"team"`
+	Score  int            `json:"score"`
+	Solves []ArchiveSolve `json:"solves"`
+}
+
+// ArchiveSolve is one solve of a challenge by a team
+type ArchiveSolve struct {
+	Challenge string    `json:"challenge,omitempty"`
+	Team      string    `json:"team,omitempty"`
+	Player    string    `json:"player,omitempty"`
+	Score     int       `json:"score"`
+	Time      time.Time `json:"time"`
+}
+
+// ExportArchive writes archive.json, a README.md and one markdown file per
+// challenge with its attachment into opts.Dir, for publishing after the event
+func (gz *GZ) ExportArchive(opts ArchiveOptions) (*Archive, error) {
+	game, err := gz.currentGame()
+	if err != nil {
+		return nil, err
+	}
+	challenges, err := game.GetChallenges()
+	if err != nil {
+		return nil, err
+	}
+	scoreboard, err := game.GetScoreboard()
+	if err != nil {
+		return nil, fmt.Errorf("scoreboard error: %w", err)
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{
+		Title:    game.Title,
+		Summary:  game.Summary,
+		Start:    game.Start.Time,
+		End:      game.End.Time,
+		Exported: time.Now(),
+	}
+
+	finalScores := map[int]int{}
+	for _, items := range scoreboard.Challenges {
+		for _, item := range items {
+			finalScores[item.Id] = item.Score
+		}
+	}
+	names := map[int]string{}
+	for _, challenge := range challenges {
+		names[challenge.Id] = challenge.Title
+	}
+
+	solves := map[int][]ArchiveSolve{}
+	for _, item := range scoreboard.Items {
+		standing := ArchiveStanding{Rank: item.Rank, Team: item.Name, Score: item.Score}
+		if opts.Anonymize {
+			standing.Team = fmt.Sprintf("Team %d", item.Rank)
+		}
+		for _, solve := range item.SolvedChallenges {
+			s := ArchiveSolve{Challenge: names[solve.Id], Player: solve.UserName, Score: solve.Score, Time: solve.Time.Time}
+			if opts.Anonymize {
+				s.Player = ""
+			}
+			standing.Solves = append(standing.Solves, s)
+			s.Challenge, s.Team = "", standing.Team
+			solves[solve.Id] = append(solves[solve.Id], s)
+		}
+		sort.Slice(standing.Solves, func(i, j int) bool { return standing.Solves[i].Time.Before(standing.Solves[j].Time) })
+		archive.Scoreboard = append(archive.Scoreboard, standing)
+	}
+	sort.Slice(archive.Scoreboard, func(i, j int) bool { return archive.Scoreboard[i].Rank < archive.Scoreboard[j].Rank })
+
+	for _, challenge := range challenges {
+		conf := challengeToYaml(challenge)
+		entry := ArchiveChallenge{
+			Name:        conf.Name,
+			Category:    conf.Category,
+			Author:      conf.Author,
+			Description: conf.Description,
+			Type:        challenge.Type,
+			Value:       challenge.OriginalScore,
+			FinalScore:  finalScores[challenge.Id],
+			Solves:      len(solves[challenge.Id]),
+			Hints:       challenge.Hints,
+		}
+		if opts.IncludeFlags {
+			for _, flag := range challenge.Flags {
+				entry.Flags = append(entry.Flags, flag.Flag)
+			}
+		}
+		for _, solve := range solves[challenge.Id] {
+			if entry.FirstBlood == nil || solve.Time.Before(entry.FirstBlood.Time) {
+				first := solve
+				entry.FirstBlood = &first
+			}
+		}
+		if entry.Attachment, err = archiveAttachment(opts.Dir, entry, challenge.Attachment); err != nil {
+			return nil, fmt.Errorf("attachment of %s: %w", entry.Name, err)
+		}
+		archive.Challenges = append(archive.Challenges, entry)
+	}
+	sort.Slice(archive.Challenges, func(i, j int) bool {
+		a, b := archive.Challenges[i], archive.Challenges[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Name < b.Name
+	})
+
+	if err := writeArchive(opts.Dir, archive); err != nil {
+		return nil, err
+	}
+	return archive, nil
+}
+
+// archiveChallengePath is the directory of a challenge inside the archive
+func archiveChallengePath(challenge ArchiveChallenge) string {
+	return path.Join("challenges", importDirName(challenge.Category), importDirName(challenge.Name))
+}
+
+// archiveAttachment downloads a local attachment next to the challenge page
+// and returns its path in the archive, or the url of a remote attachment
+func archiveAttachment(dir string, challenge ArchiveChallenge, attachment *gzapi.Attachment) (string, error) {
+	if attachment == nil {
+		return "", nil
+	}
+	switch attachment.Type {
+	case "Remote":
+		return attachment.Url, nil
+	case "Local":
+		rel := path.Join(archiveChallengePath(challenge), path.Base(attachment.Url))
+		dst := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return "", err
+		}
+		log.InfoH2("Download attachment of %s", challenge.Name)
+		return rel, attachment.Download(dst)
+	}
+	return "", nil
+}
+
+func writeArchive(dir string, archive *Archive) error {
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "archive.json"), data, 0644); err != nil {
+		return err
+	}
+
+	var readme strings.Builder
+	fmt.Fprintf(&readme, "# %s\n\n", archive.Title)
+	if archive.Summary != "" {
+		fmt.Fprintf(&readme, "%s\n\n", archive.Summary)
+	}
+	fmt.Fprintf(&readme, "%s to %s\n\n", archive.Start.UTC().Format(time.RFC1123), archive.End.UTC().Format(time.RFC1123))
+
+	readme.WriteString("## Scoreboard\n\n| Rank | Team | Score | Solves |\n| ---: | --- | ---: | ---: |\n")
+	for _, standing := range archive.Scoreboard {
+		fmt.Fprintf(&readme, "| %d | %s | %d | %d |\n", standing.Rank, markdownCell(standing.Team), standing.Score, len(standing.Solves))
+	}
+
+	readme.WriteString("\n## Challenges\n\n| Category | Challenge | Author | Score | Solves | First blood |\n| --- | --- | --- | ---: | ---: | --- |\n")
+	for _, challenge := range archive.Challenges {
+		firstBlood := "-"
+		if challenge.FirstBlood != nil {
+			firstBlood = markdownCell(challenge.FirstBlood.Team)
+		}
+		page := archiveChallengePath(challenge) + "/README.md"
+		fmt.Fprintf(&readme, "| %s | [%s](%s) | %s | %d | %d | %s |\n", challenge.Category, markdownCell(challenge.Name), page, markdownCell(challenge.Author), challenge.FinalScore, challenge.Solves, firstBlood)
+
+		if err := writeArchiveChallenge(dir, challenge); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filepath.Join(dir, "README.md"), []byte(readme.String()), 0644)
+}
+
+func writeArchiveChallenge(dir string, challenge ArchiveChallenge) error {
+	var page strings.Builder
+	fmt.Fprintf(&page, "# %s\n\n", challenge.Name)
+	fmt.Fprintf(&page, "- Category: %s\n", challenge.Category)
+	if challenge.Author != "" {
+		fmt.Fprintf(&page, "- Author: %s\n", challenge.Author)
+	}
+	fmt.Fprintf(&page, "- Final score: %d (initial %d)\n- Solves: %d\n", challenge.FinalScore, challenge.Value, challenge.Solves)
+	if challenge.FirstBlood != nil {
+		fmt.Fprintf(&page, "- First blood: %s at %s\n", challenge.FirstBlood.Team, challenge.FirstBlood.Time.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&page, "\n%s\n", challenge.Description)
+	if challenge.Attachment != "" {
+		attachment := challenge.Attachment
+		if !strings.HasPrefix(attachment, "http") {
+			attachment = path.Base(attachment)
+		}
+		fmt.Fprintf(&page, "\n## Attachment\n\n[%s](%s)\n", path.Base(attachment), attachment)
+	}
+	if len(challenge.Hints) > 0 {
+		page.WriteString("\n## Hints\n\n")
+		for _, hint := range challenge.Hints {
+			fmt.Fprintf(&page, "- %s\n", hint)
+		}
+	}
+	if len(challenge.Flags) > 0 {
+		page.WriteString("\n## Flag\n\n")
+		for _, flag := range challenge.Flags {
+			fmt.Fprintf(&page, "`%s`\n", flag)
+		}
+	}
+
+	file := filepath.Join(dir, filepath.FromSlash(archiveChallengePath(challenge)), "README.md")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, []byte(page.String()), 0644)
+}
+
+// markdownCell escapes text for a markdown table cell
+func markdownCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
@@ -2,47 +2,96 @@ package gzcli
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
 )
 
-func createAssetsIfNotExistOrDifferent(file string, client *gzapi.GZAPI) (*gzapi.FileInfo, error) {
-	assets, err := client.GetAssets()
+// assetListCacheTTL lets concurrent challenge syncs share one asset listing
+const assetListCacheTTL = 30 * time.Second
+
+func createAssetsIfNotExistOrDifferent(config *Config, file string, client *gzapi.GZAPI) (*gzapi.FileInfo, error) {
+	assets, err := client.Cached(assetListCacheTTL).GetAssets()
 	if err != nil {
 		return nil, err
 	}
+	asset, _, err := uploadAssetIfMissing(config, file, assets, client)
+	return asset, err
+}
 
+// uploadAssetIfMissing uploads file unless an asset of assets already has its
+// hash, reporting whether it was uploaded
+func uploadAssetIfMissing(config *Config, file string, assets []gzapi.FileInfo, client *gzapi.GZAPI) (*gzapi.FileInfo, bool, error) {
 	hash, err := GetFileHashHex(file)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	for _, asset := range assets {
 		if asset.Hash == hash {
-			return &asset, nil
+			return &asset, false, nil
 		}
 	}
 
-	asset, err := client.CreateAssets(file)
-	if err != nil {
-		return nil, err
+	if err := checkUploadLimit(config, file); err != nil {
+		return nil, false, err
 	}
+	asset, err := uploadAssetVerified(file, hash, client)
+	return asset, err == nil, err
+}
 
-	if len(asset) == 0 {
-		return nil, fmt.Errorf("error creating asset")
+const maxUploadAttempts = 3
+
+// uploadAssetVerified uploads file and checks that the hash computed by the
+// platform matches the local one, retrying truncated uploads
+func uploadAssetVerified(file string, hash string, client *gzapi.GZAPI) (*gzapi.FileInfo, error) {
+	var lastErr error
+	for attempt := 1; attempt <= maxUploadAttempts; attempt++ {
+		asset, err := client.CreateAssets(file)
+		if err != nil {
+			lastErr = err
+		} else if len(asset) == 0 {
+			lastErr = fmt.Errorf("error creating asset")
+		} else if asset[0].Hash != hash {
+			lastErr = fmt.Errorf("uploaded %s is corrupted: platform hash %s, local hash %s", file, asset[0].Hash, hash)
+			if err := client.DeleteAsset(asset[0].Hash); err != nil {
+				log.ErrorH2("Failed to delete corrupted asset %s: %v", asset[0].Hash, err)
+			}
+		} else {
+			return &asset[0], nil
+		}
+		log.ErrorH2("Upload attempt %d/%d failed: %v", attempt, maxUploadAttempts, lastErr)
 	}
+	return nil, lastErr
+}
+
+// posterCache is the poster last uploaded for a game. The platform converts
+// posters to webp, so the hash of the local file is kept to skip uploading
+// the same file again
+type posterCache struct {
+	Hash string `yaml:"hash"`
+	Path string `yaml:"path"`
+}
 
-	return &asset[0], nil
+func posterCacheKey(game *gzapi.Game) string {
+	return fmt.Sprintf("poster_%d", game.Id)
 }
 
-func createPosterIfNotExistOrDifferent(file string, game *gzapi.Game, client *gzapi.GZAPI) (string, error) {
-	assets, err := client.GetAssets()
+func createPosterIfNotExistOrDifferent(config *Config, file string, game *gzapi.Game, client *gzapi.GZAPI) (string, error) {
+	hash, err := GetFileHashHex(file)
 	if err != nil {
 		return "", err
 	}
+	var cached posterCache
+	if err := GetCache(posterCacheKey(game), &cached); err == nil && cached.Hash == hash && cached.Path == game.Poster {
+		return cached.Path, nil
+	}
 
-	hash, err := GetFileHashHex(file)
+	assets, err := client.Cached(assetListCacheTTL).GetAssets()
 	if err != nil {
 		return "", err
 	}
@@ -53,6 +102,9 @@ func createPosterIfNotExistOrDifferent(file string, game *gzapi.Game, client *gz
 		}
 	}
 
+	if err := checkUploadLimit(config, file); err != nil {
+		return "", err
+	}
 	asset, err := game.UploadPoster(file)
 	if err != nil {
 		return "", err
@@ -62,19 +114,142 @@ func createPosterIfNotExistOrDifferent(file string, game *gzapi.Game, client *gz
 		return "", fmt.Errorf("error creating poster")
 	}
 	asset = strings.Replace(asset, ".webp", "", 1)
+	if err := setRemoteCache(posterCacheKey(game), posterCache{Hash: hash, Path: asset}); err != nil {
+		return "", err
+	}
 	return asset, nil
 }
 
+// checkUploadLimit fails fast when file is larger than the request body
+// limit of the server instead of letting the upload end in a 413. The limit
+// is uploadLimit of the config, or the Kestrel MaxRequestBodySize of
+// appsettings.json for servers deployed from this directory
+func checkUploadLimit(config *Config, file string) error {
+	var limit int64
+	if config.UploadLimit == "" {
+		limit = kestrelUploadLimit()
+	} else {
+		var err error
+		if limit, err = ParseSize(config.UploadLimit); err != nil {
+			return fmt.Errorf("invalid uploadLimit: %w", err)
+		}
+	}
+	if limit <= 0 {
+		return nil
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	if info.Size() > limit {
+		return fmt.Errorf("artifact %s is %s, exceeding the server limit of %s",
+			filepath.Base(file), FormatSize(info.Size()), FormatSize(limit))
+	}
+	return nil
+}
+
+// kestrelUploadLimit returns Kestrel.Limits.MaxRequestBodySize of
+// appsettings.json, or 0 when it is not set
+func kestrelUploadLimit() int64 {
+	appsettings, err := getAppSettings()
+	if err != nil {
+		return 0
+	}
+	kestrel, _ := appsettings["Kestrel"].(map[string]interface{})
+	limits, _ := kestrel["Limits"].(map[string]interface{})
+	size, _ := limits["MaxRequestBodySize"].(float64)
+	return int64(size)
+}
+
+// uploadOptions reads uploadTimeout and uploadWorkers from the config and
+// reports the progress of large uploads
+func uploadOptions(config *Config) (gzapi.UploadOptions, error) {
+	opts := gzapi.UploadOptions{
+		Limit: config.UploadWorkers,
+		Progress: func(file string, uploaded int64, total int64) {
+			if total > 0 && uploaded < total {
+				log.InfoH3("Uploading %s: %d%% (%s / %s)", file, uploaded*100/total, FormatSize(uploaded), FormatSize(total))
+			}
+		},
+	}
+	if config.UploadTimeout != "" {
+		timeout, err := time.ParseDuration(config.UploadTimeout)
+		if err != nil {
+			return opts, fmt.Errorf("invalid uploadTimeout: %w", err)
+		}
+		opts.Timeout = timeout
+	}
+	return opts, nil
+}
+
 func GetClient(api *gzapi.GZAPI) (*gzapi.GZAPI, error) {
 	config, err := GetConfig(api)
 	if err != nil {
 		return nil, err
 	}
 
-	client, err := gzapi.Init(config.Url, &config.Creds)
+	opts, err := uploadOptions(config)
 	if err != nil {
 		return nil, err
 	}
+	if err := setSlowCallLog(config); err != nil {
+		return nil, err
+	}
+	client, err := gzapi.Init(config.Url, &config.Creds, config.TLS)
+	if err != nil {
+		return nil, err
+	}
+	client.SetUploadOptions(opts)
 
 	return client, nil
 }
+
+// ListAssets returns the files stored on the platform
+func (gz *GZ) ListAssets() ([]gzapi.FileInfo, error) {
+	return gz.api.GetAssets()
+}
+
+// UploadAssets uploads every file that is not on the platform yet, compared
+// by hash, and reports which ones were uploaded
+func (gz *GZ) UploadAssets(files []string) ([]gzapi.FileInfo, []bool, error) {
+	config, err := getLocalConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	assets, err := gz.api.GetAssets()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result []gzapi.FileInfo
+	var uploaded []bool
+	for _, file := range files {
+		asset, isNew, err := uploadAssetIfMissing(config, file, assets, gz.api)
+		if err != nil {
+			return result, uploaded, fmt.Errorf("%s: %w", file, err)
+		}
+		if isNew {
+			assets = append(assets, *asset)
+			if err := recordOperation("asset.upload", map[string]string{"file": file, "hash": asset.Hash}); err != nil {
+				return result, uploaded, err
+			}
+		}
+		result = append(result, *asset)
+		uploaded = append(uploaded, isNew)
+	}
+	return result, uploaded, nil
+}
+
+// DeleteAssets removes the assets with the given hashes from the platform
+func (gz *GZ) DeleteAssets(hashes []string) error {
+	for _, hash := range hashes {
+		if err := gz.api.DeleteAsset(hash); err != nil {
+			return fmt.Errorf("%s: %w", hash, err)
+		}
+		if err := recordOperation("asset.delete", map[string]string{"hash": hash}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
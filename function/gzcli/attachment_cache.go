@@ -0,0 +1,108 @@
+package gzcli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+const attachmentCacheKey = "attachment-cache"
+
+// attachmentCacheEntry records the content of the last attachment uploaded
+// for a challenge
+type attachmentCacheEntry struct {
+	ContentHash    string `yaml:"contentHash"`
+	AttachmentType string `yaml:"attachmentType"`
+}
+
+// attachmentCacheMu serializes read-modify-write of the attachment cache
+// between concurrent challenge syncs
+var attachmentCacheMu sync.Mutex
+
+// fileManifest maps the files under a directory, relative and slash
+// separated, to their sha256
+type fileManifest map[string]string
+
+// hashFiles builds the manifest of every file under root, streaming the
+// contents. root may be a single file
+func hashFiles(root string) (fileManifest, error) {
+	manifest := fileManifest{}
+	buf := make([]byte, 32<<10)
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		h := sha256.New()
+		if _, err := io.CopyBuffer(h, f, buf); err != nil {
+			return err
+		}
+		manifest[filepath.ToSlash(rel)] = hex.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	return manifest, err
+}
+
+// hashTree writes the manifest of root into h in path order
+func hashTree(h hash.Hash, root string) error {
+	manifest, err := hashFiles(root)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(manifest))
+	for path := range manifest {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		fmt.Fprintf(h, "%s\x00%s\n", path, manifest[path])
+	}
+	return nil
+}
+
+func attachmentContentHash(source string) (string, error) {
+	h := sha256.New()
+	if err := hashTree(h, source); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// cachedAttachment returns the attachment recorded for a challenge on a
+// previous sync
+func cachedAttachment(cacheKey string) (attachmentCacheEntry, bool) {
+	attachmentCacheMu.Lock()
+	defer attachmentCacheMu.Unlock()
+
+	entries := map[string]attachmentCacheEntry{}
+	if err := GetCache(attachmentCacheKey, &entries); err != nil {
+		return attachmentCacheEntry{}, false
+	}
+	entry, ok := entries[cacheKey]
+	return entry, ok
+}
+
+func saveAttachmentCache(cacheKey string, entry attachmentCacheEntry) error {
+	attachmentCacheMu.Lock()
+	defer attachmentCacheMu.Unlock()
+
+	entries := map[string]attachmentCacheEntry{}
+	GetCache(attachmentCacheKey, &entries)
+	entries[cacheKey] = entry
+	return setRemoteCache(attachmentCacheKey, entries)
+}
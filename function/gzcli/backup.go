@@ -0,0 +1,277 @@
+package gzcli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+const backupDirName = ".gzcli-backups"
+
+// backupTimeFormat stamps the files of the backup directory. Microseconds
+// keep two backups in the same second from colliding, and the fixed width
+// keeps them sorted by name
+const backupTimeFormat = "20060102-150405.000000"
+
+// backupDir is .gzcli-backups, or .gzcli-backups/<profile> with a profile
+// so a restore never brings the state of another instance back
+func backupDir() string {
+	return filepath.Join(filepath.Dir(cacheDir), backupDirName, profile)
+}
+
+// HasCache reports whether the working directory has a cache directory for
+// Backup to snapshot
+func HasCache() bool {
+	info, err := os.Stat(cacheDir)
+	return err == nil && info.IsDir()
+}
+
+// Backup snapshots the cache directory (game config, challenge state and
+// issued team credentials) into a timestamped archive and returns its path
+func Backup() (string, error) {
+	if _, err := os.Stat(cacheDir); err != nil {
+		return "", fmt.Errorf("nothing to back up: %w", err)
+	}
+	if err := os.MkdirAll(backupDir(), 0700); err != nil {
+		return "", err
+	}
+
+	archive := filepath.Join(backupDir(), fmt.Sprintf("gzcli-%s.tar.gz", time.Now().Format(backupTimeFormat)))
+	f, err := os.OpenFile(archive, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	err = filepath.Walk(cacheDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(cacheDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if info.IsDir() && rel == zipCacheDir {
+			return filepath.SkipDir
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(tw, src)
+		return err
+	})
+	if err != nil {
+		os.Remove(archive)
+		return "", fmt.Errorf("backup failed: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+	if err := gw.Close(); err != nil {
+		return "", err
+	}
+	return archive, nil
+}
+
+// ListBackups returns the available backup archives, newest first
+func ListBackups() ([]string, error) {
+	entries, err := os.ReadDir(backupDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tar.gz") {
+			backups = append(backups, filepath.Join(backupDir(), entry.Name()))
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	return backups, nil
+}
+
+// RestoreBackup replaces the cache directory with the content of archive,
+// backing up the current state first
+func RestoreBackup(archive string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := os.Stat(cacheDir); err == nil {
+		current, err := Backup()
+		if err != nil {
+			return fmt.Errorf("backup of current state failed: %w", err)
+		}
+		log.Info("Current state saved to %s", current)
+	}
+
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	restoreDir := cacheDir + ".restore"
+	if err := os.RemoveAll(restoreDir); err != nil {
+		return err
+	}
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(restoreDir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, restoreDir+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path in backup: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			dst, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode).Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(dst, tr); err != nil {
+				dst.Close()
+				return err
+			}
+			dst.Close()
+		}
+	}
+
+	if err := os.RemoveAll(cacheDir); err != nil {
+		return err
+	}
+	return os.Rename(restoreDir, cacheDir)
+}
+
+// stripBackups rewrites every backup archive without the entries of the
+// cache keys and returns how many archives held them
+func stripBackups(keys ...string) (int, error) {
+	backups, err := ListBackups()
+	if err != nil {
+		return 0, err
+	}
+	stripped := 0
+	for _, archive := range backups {
+		found, err := stripBackup(archive, keys)
+		if err != nil {
+			return stripped, fmt.Errorf("%s: %w", archive, err)
+		}
+		if found {
+			stripped++
+		}
+	}
+	return stripped, nil
+}
+
+func stripBackup(archive string, keys []string) (bool, error) {
+	drop := map[string]bool{}
+	for _, key := range keys {
+		drop[key] = true
+	}
+
+	src, err := os.Open(archive)
+	if err != nil {
+		return false, err
+	}
+	defer src.Close()
+	gr, err := gzip.NewReader(src)
+	if err != nil {
+		return false, err
+	}
+	defer gr.Close()
+
+	tmp := archive + ".tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp)
+	defer dst.Close()
+	gw := gzip.NewWriter(dst)
+	tw := tar.NewWriter(gw)
+
+	found := false
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+		// Entries are the data and .meta files of a key
+		if drop[strings.TrimSuffix(header.Name, path.Ext(header.Name))] {
+			found = true
+			continue
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return false, err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return false, err
+		}
+	}
+	if !found {
+		return false, nil
+	}
+
+	if err := tw.Close(); err != nil {
+		return false, err
+	}
+	if err := gw.Close(); err != nil {
+		return false, err
+	}
+	if err := dst.Close(); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp, archive)
+}
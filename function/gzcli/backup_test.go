@@ -0,0 +1,84 @@
+package gzcli
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStripBackup(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		entries []string
+		want    []string
+		found   bool
+	}{
+		{"credentials", []string{"config.yaml", "teams_creds.yaml", "teams_creds.meta", "teams_creds_sealed.yaml"}, []string{"config.yaml"}, true},
+		{"none", []string{"config.yaml", "challenges/a.yaml"}, []string{"config.yaml", "challenges/a.yaml"}, false},
+		{"prefix only", []string{"teams_creds_old.yaml"}, []string{"teams_creds_old.yaml"}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			archive := filepath.Join(t.TempDir(), "gzcli.tar.gz")
+			writeTestArchive(t, archive, tt.entries)
+
+			found, err := stripBackup(archive, []string{teamsCredsCacheKey, teamsCredsSealedKey})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != tt.found {
+				t.Fatalf("found = %v, want %v", found, tt.found)
+			}
+			if got := readTestArchive(t, archive); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("entries = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func writeTestArchive(t *testing.T, archive string, names []string) {
+	f, err := os.Create(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+	for _, name := range names {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(name)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tw.Close()
+	gw.Close()
+}
+
+func readTestArchive(t *testing.T, archive string) []string {
+	f, err := os.Open(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, header.Name)
+	}
+}
@@ -11,8 +11,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"gopkg.in/gomail.v2"
-
 	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
 	"github.com/dimasma0305/ctfify/function/log"
 	"github.com/sethvargo/go-password/password"
@@ -26,6 +24,8 @@ type TeamCreds struct {
 	TeamName           string `json:"team_name" yaml:"team_name"`
 	IsEmailAlreadySent bool   `json:"is_email_already_sent" yaml:"is_email_already_sent"`
 	IsTeamCreated      bool   `json:"is_team_created" yaml:"is_team_created"`
+	Division           string `json:"division,omitempty" yaml:"division,omitempty"`
+	Institution        string `json:"institution,omitempty" yaml:"institution,omitempty"`
 }
 
 // CreteTeamAndUser creates a team and user, ensuring the team name is unique and within the specified length.
@@ -60,7 +60,7 @@ func (gz *GZ) CreteTeamAndUser(teamCreds *TeamCreds, config *Config, existingTea
 		api, err = gzapi.Init(config.Url, &gzapi.Creds{
 			Username: currentCreds.Username,
 			Password: currentCreds.Password,
-		})
+		}, config.TLS)
 		if err == nil {
 			alreadyLogin = true
 		} else {
@@ -80,36 +80,48 @@ func (gz *GZ) CreteTeamAndUser(teamCreds *TeamCreds, config *Config, existingTea
 			Email:    currentCreds.Email,
 			Username: currentCreds.Username,
 			Password: currentCreds.Password,
-		})
+		}, config.TLS)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	// Create the team
-	log.Info("Creating user %s with team %s", username, teamName)
+	log.Info("%s", log.Msg("team.create", username, teamName))
 	if !currentCreds.IsTeamCreated {
 		err = api.CreateTeam(&gzapi.TeamForm{
 			Bio:  "",
 			Name: teamName,
 		})
-		if err != nil {
-			log.ErrorH2("Team %s already exist", teamName)
+		switch {
+		case err == nil:
+			currentCreds.IsTeamCreated = true
+		case gzapi.IsConflict(err):
+			log.ErrorH2("%s", log.Msg("team.exists", teamName))
+			currentCreds.IsTeamCreated = true
+		default:
+			// The account exists already, keep its credentials and retry the
+			// team on the next run
+			log.ErrorH2("%s", log.Msg("team.create.failed", teamName, err))
 		}
 	} else {
-		log.InfoH2("Team %s already created", teamName)
+		log.InfoH2("%s", log.Msg("team.created", teamName))
 	}
-	currentCreds.IsTeamCreated = true
 
 	// Send credentials via email if enabled in the config
 	if isSendEmail && !currentCreds.IsEmailAlreadySent {
-		if err := sendEmail(teamCreds.Username, config.Url, currentCreds); err != nil {
-			log.ErrorH2("Failed to send email to %s: %v", currentCreds.Email, err)
+		response, err := sendEmail(config, teamCreds.Username, currentCreds)
+		if recordErr := recordEmail(currentCreds, response, err); recordErr != nil {
+			log.ErrorH2("%s", log.Msg("email.record.failed", currentCreds.Email, recordErr))
+		}
+		if err != nil {
+			log.ErrorH2("%s", log.Msg("email.failed", currentCreds.Email, err))
+		} else {
+			log.InfoH2("%s", log.Msg("email.sent", currentCreds.Email, response))
+			currentCreds.IsEmailAlreadySent = config.Email.delivers()
 		}
-		log.InfoH2("Successfully sending email to %s", currentCreds.Email)
-		currentCreds.IsEmailAlreadySent = true
 	} else {
-		log.ErrorH2("Email to %s already sended before", currentCreds.Email)
+		log.ErrorH2("%s", log.Msg("email.already.sent", currentCreds.Email))
 	}
 
 	return currentCreds, nil
@@ -190,133 +202,64 @@ func getAppSettings() (map[string]interface{}, error) {
 	return result, nil
 }
 
-// sendEmail sends the team credentials to the specified email address using gomail
-func sendEmail(realName string, website string, creds *TeamCreds) error {
+// smtpSettings holds the SMTP account from the EmailConfig of appsettings.json
+type smtpSettings struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// getSmtpSettings reads the SMTP account from appsettings.json and resolves
+// its secret references
+func getSmtpSettings() (*smtpSettings, error) {
 	appsettings, err := getAppSettings()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Type assertion to check if EmailConfig exists and is of type map[string]interface{}
 	emailConfig, ok := appsettings["EmailConfig"].(map[string]interface{})
 	if !ok {
-		return fmt.Errorf("failed to assert type map[string]interface{} for EmailConfig")
+		return nil, fmt.Errorf("failed to assert type map[string]interface{} for EmailConfig")
 	}
 
 	smtp, ok := emailConfig["Smtp"].(map[string]interface{})
 	if !ok {
-		return fmt.Errorf("smtp is missing or not a dict")
+		return nil, fmt.Errorf("smtp is missing or not a dict")
 	}
 
 	// Extract the necessary fields from the emailConfig map
 	smtpHost, ok := smtp["Host"].(string)
 	if !ok {
-		return fmt.Errorf("host is missing or not a string")
+		return nil, fmt.Errorf("host is missing or not a string")
 	}
 	smtpPort, ok := smtp["Port"].(float64)
 	if !ok {
-		return fmt.Errorf("port is missing or not a number")
+		return nil, fmt.Errorf("port is missing or not a number")
 	}
 	smtpUsername, ok := emailConfig["UserName"].(string)
 	if !ok {
-		return fmt.Errorf("smtpUsername is missing or not a string")
+		return nil, fmt.Errorf("smtpUsername is missing or not a string")
 	}
 	smtpPassword, ok := emailConfig["Password"].(string)
 	if !ok {
-		return fmt.Errorf("smtpPassword is missing or not a string")
-	}
-
-	m := gomail.NewMessage()
-	m.SetHeader("From", smtpUsername)
-	m.SetHeader("To", creds.Email)
-	m.SetHeader("Subject", "Your Team Credentials")
-
-	htmlBody := fmt.Sprintf(`
-	&nbsp;
-	<html>
-	<head>
-		<style>
-			body {
-				font-family: Arial, sans-serif;
-				line-height: 1.6;
-				color: #333;
-			}
-			.block {
-				max-width: 600px;
-				margin: 0 auto;
-				padding: 20px;
-				border: 1px solid #eaeaea;
-				border-radius: 5px;
-				background-color: #f9f9f9;
-			}
-			h1 {
-				color: #333;
-			}
-			.creds {
-				margin-bottom: 20px;
-			}
-			.creds p {
-				margin: 5px 0;
-			}
-			.cta {
-				text-align: center;
-				margin-top: 20px;
-			}
-			.cta a {
-				display: inline-block;
-				padding: 10px 20px;
-				text-decoration: none;
-				color: white;
-				background-color: #007BFF;
-				border-radius: 5px;
-			}
-			.cta a:hover {
-				background-color: #0056b3;
-			}
-		</style>
-	</head>
-	<body>
-		<div class="block">
-		<h1>Hello %s,</h1>
-		&nbsp;
-		<div class="creds">
-			<p>Here are your team credentials:</p>
-			&nbsp;
-			<p><strong>Username:</strong> %s</p>
-			<p><strong>Password:</strong> %s</p>
-			<p><strong>Team Name:</strong> %s</p>
-			<p><strong>Website:</strong> <a href="%s">%s</a></p>
-		</div>
-		&nbsp;
-		<p>After logging in with your credentials, you can copy your team invitation code from the /teams page, and then share it with your team members.</p>
-		&nbsp;
-		<p>Make sure to notify your team members to register first and then use the invitation code on the /team page.</p>
-		&nbsp;
-		<p>Once all your team members have joined, you can navigate to the /games page and request to join the game. The admin will verify your request, and you just need to wait for the CTF to start.</p>
-		&nbsp;
-		<div class="cta">
-			<a href="%s">Go to Website</a>
-		</div>
-		&nbsp;
-		</div>
-	</body>
-	</html>
-	`,
-		realName, creds.Username, creds.Password, creds.TeamName, website, website, website,
-	)
-
-	// Set the email body as HTML
-	m.SetBody("text/html", htmlBody)
-
-	// Dial the SMTP server
-	d := gomail.NewDialer(smtpHost, int(smtpPort), smtpUsername, smtpPassword)
-
-	// Send the email
-	if err := d.DialAndSend(m); err != nil {
-		return fmt.Errorf("failed to send email: %v", err)
-	}
-
-	return nil
+		return nil, fmt.Errorf("smtpPassword is missing or not a string")
+	}
+	if err := resolveSecrets(map[string]*string{
+		"EmailConfig.Smtp.Host": &smtpHost,
+		"EmailConfig.UserName":  &smtpUsername,
+		"EmailConfig.Password":  &smtpPassword,
+	}); err != nil {
+		return nil, fmt.Errorf("appsettings.json: %w", err)
+	}
+
+	return &smtpSettings{
+		Host:     smtpHost,
+		Port:     int(smtpPort),
+		Username: smtpUsername,
+		Password: smtpPassword,
+	}, nil
 }
 
 func parseCSV(data []byte, gz *GZ, config *Config, isSendEmail bool) error {
@@ -354,9 +297,9 @@ func parseCSV(data []byte, gz *GZ, config *Config, isSendEmail bool) error {
 	existingTeamNames := make(map[string]struct{})
 
 	// Load existing team credentials from cache
-	var teamsCredsCache []*TeamCreds
-	if err := GetCache("teams_creds", &teamsCredsCache); err != nil {
-		log.Error("%s", err.Error())
+	teamsCredsCache, err := loadTeamsCreds()
+	if err != nil && !isMissingTeamsCreds(err) {
+		return err
 	}
 
 	// Create a map for quick lookup of existing credentials by email
@@ -367,17 +310,35 @@ func parseCSV(data []byte, gz *GZ, config *Config, isSendEmail bool) error {
 
 	// List to hold the merged team credentials
 	var teamsCreds []*TeamCreds
+	var rejectedRows []string
 
-	for _, row := range records[1:] {
+	for i, row := range records[1:] {
 		realName := row[colIndices["RealName"]]
 		email := row[colIndices["Email"]]
 		teamName := row[colIndices["TeamName"]]
 
+		division := rowDivision(row, colIndices)
+
+		// Reject rows that break the configured team rules or name a
+		// division the game does not have
+		err := validateTeamRow(config.TeamRules, row, colIndices)
+		if err == nil {
+			err = validateDivision(config.Event.Organizations, division)
+		}
+		if err != nil {
+			rejected := fmt.Sprintf("row %d (%s): %v", i+2, email, err)
+			log.Error("Rejected %s", rejected)
+			rejectedRows = append(rejectedRows, rejected)
+			continue
+		}
+
 		// Create or update team and user based on the generated username
 		creds, err := gz.CreteTeamAndUser(&TeamCreds{
-			Username: realName,
-			Email:    email,
-			TeamName: teamName,
+			Username:    realName,
+			Email:       email,
+			TeamName:    teamName,
+			Division:    division,
+			Institution: csvColumn(row, colIndices, csvInstitution),
 		}, config, existingTeamNames, uniqueUsernames, teamsCredsCache, isSendEmail)
 		if err != nil {
 			log.Error("%s", err.Error())
@@ -391,6 +352,8 @@ func parseCSV(data []byte, gz *GZ, config *Config, isSendEmail bool) error {
 				existingCreds.Username = creds.Username
 				existingCreds.Password = creds.Password
 				existingCreds.TeamName = creds.TeamName
+				existingCreds.Division = division
+				existingCreds.Institution = csvColumn(row, colIndices, csvInstitution)
 			} else {
 				// Add new credentials to the list
 				teamsCreds = append(teamsCreds, creds)
@@ -404,9 +367,12 @@ func parseCSV(data []byte, gz *GZ, config *Config, isSendEmail bool) error {
 	}
 
 	// Save the merged credentials to cache
-	if err := setCache("teams_creds", teamsCreds); err != nil {
+	if err := saveTeamsCreds(teamsCreds); err != nil {
 		return err
 	}
 
+	if len(rejectedRows) > 0 {
+		return fmt.Errorf("%d rows rejected:\n  - %s", len(rejectedRows), strings.Join(rejectedRows, "\n  - "))
+	}
 	return nil
 }
@@ -0,0 +1,103 @@
+package gzcli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AttachmentReport describes the size of a challenge attachment against its category budget
+type AttachmentReport struct {
+	Challenge  string
+	Category   string
+	Size       int64
+	Budget     int64
+	OverBudget bool
+}
+
+func categoryBudget(config *Config, category string) (int64, error) {
+	budget, ok := config.Budgets[category]
+	if !ok {
+		return 0, nil
+	}
+	return ParseSize(budget)
+}
+
+// checkAttachmentBudget rejects an artifact larger than the budget of its category
+func checkAttachmentBudget(config *Config, challengeConf ChallengeYaml, file string) error {
+	budget, err := categoryBudget(config, challengeConf.Category)
+	if err != nil || budget == 0 {
+		return err
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return err
+	}
+	if info.Size() > budget {
+		return fmt.Errorf("attachment of %s is %s, exceeding the %s budget of %s",
+			challengeConf.Name, FormatSize(info.Size()), challengeConf.Category, FormatSize(budget))
+	}
+	return nil
+}
+
+// AttachmentBudgetReport measures every local attachment against the configured budgets
+func AttachmentBudgetReport() ([]AttachmentReport, error) {
+	config, err := GetConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []AttachmentReport
+	for _, challengeConf := range challengesConf {
+		if challengeConf.Provide == nil || strings.HasPrefix(*challengeConf.Provide, "http") {
+			continue
+		}
+
+		size, err := attachmentSize(challengeConf)
+		if err != nil {
+			return nil, fmt.Errorf("measure attachment of %s: %w", challengeConf.Name, err)
+		}
+		budget, err := categoryBudget(config, challengeConf.Category)
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, AttachmentReport{
+			Challenge:  challengeConf.Name,
+			Category:   challengeConf.Category,
+			Size:       size,
+			Budget:     budget,
+			OverBudget: budget > 0 && size > budget,
+		})
+	}
+	return reports, nil
+}
+
+// attachmentSize returns the size of the artifact sync would upload
+func attachmentSize(challengeConf ChallengeYaml) (int64, error) {
+	source := filepath.Join(challengeConf.Cwd, *challengeConf.Provide)
+	info, err := os.Stat(source)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return info.Size(), nil
+	}
+
+	archive, err := zipChallengeSource(challengeConf, source, NormalizeFileName(*challengeConf.Provide)+".zip")
+	if err != nil {
+		return 0, err
+	}
+	info, err = os.Stat(archive)
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
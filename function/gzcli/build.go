@@ -0,0 +1,374 @@
+package gzcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+	"gopkg.in/yaml.v3"
+)
+
+// imageDigestsFile maps the containerImage of challenge.yml to the registry
+// digest gzcli build pushed it as, which sync then deploys. It sits in the
+// CTF root rather than the ignored .gzctf, as it is meant to be committed so
+// every checkout deploys the same images
+const imageDigestsFile = "image-digests.yaml"
+
+const imageDigestsHeader = "# Written by gzcli build, remove pins with gzcli build --unpin\n"
+
+var imageDigestsMu sync.Mutex
+
+// registrySettings is the RegistryConfig of appsettings.json, the registry
+// the platform pulls challenge images from
+type registrySettings struct {
+	ServerAddress string
+	UserName      string
+	Password      string
+}
+
+// getRegistrySettings reads RegistryConfig from appsettings.json and resolves
+// its secret references. A missing RegistryConfig pushes images to the
+// registry their containerImage names
+func getRegistrySettings() (*registrySettings, error) {
+	appsettings, err := getAppSettings()
+	if err != nil {
+		return nil, err
+	}
+	registry := &registrySettings{}
+	settings, ok := appsettings["RegistryConfig"].(map[string]interface{})
+	if !ok {
+		return registry, nil
+	}
+	registry.ServerAddress, _ = settings["ServerAddress"].(string)
+	registry.UserName, _ = settings["UserName"].(string)
+	registry.Password, _ = settings["Password"].(string)
+	if err := resolveSecrets(map[string]*string{
+		"RegistryConfig.ServerAddress": &registry.ServerAddress,
+		"RegistryConfig.UserName":      &registry.UserName,
+		"RegistryConfig.Password":      &registry.Password,
+	}); err != nil {
+		return nil, fmt.Errorf("appsettings.json: %w", err)
+	}
+	registry.ServerAddress = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(registry.ServerAddress, "https://"), "http://"), "/")
+	return registry, nil
+}
+
+func (r *registrySettings) login() error {
+	if r.ServerAddress == "" || r.UserName == "" {
+		return nil
+	}
+	cmd := exec.Command("docker", "login", r.ServerAddress, "--username", r.UserName, "--password-stdin")
+	cmd.Stdin = strings.NewReader(r.Password)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("docker login %s: %w\n%s", r.ServerAddress, err, output)
+	}
+	return nil
+}
+
+// tag returns the reference image is pushed as: image moved to the
+// registry of RegistryConfig, with its digest dropped and latest as the
+// default tag
+func (r *registrySettings) tag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if r.ServerAddress != "" {
+		if host, rest, found := strings.Cut(image, "/"); found && (strings.ContainsAny(host, ".:") || host == "localhost") {
+			image = rest
+		}
+		image = r.ServerAddress + "/" + image
+	}
+	if repository, _ := splitImageTag(image); repository == image {
+		image += ":latest"
+	}
+	return image
+}
+
+// splitImageTag splits an image reference into its repository and tag. The
+// tag is empty when the reference has none
+func splitImageTag(image string) (string, string) {
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return image, ""
+	}
+	return image[:i], image[i+1:]
+}
+
+// BuildResult is the outcome of building and pushing one challenge image
+type BuildResult struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Image    string `json:"image"`
+	Digest   string `json:"digest,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// buildContext returns the directory holding the Dockerfile of a challenge,
+// src/ and then the challenge directory
+func buildContext(challengeConf ChallengeYaml) (string, error) {
+	for _, dir := range []string{"src", "."} {
+		path := filepath.Join(challengeConf.Cwd, dir)
+		if _, err := os.Stat(filepath.Join(path, "Dockerfile")); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("no Dockerfile in src/")
+}
+
+// BuildImages builds the Dockerfile of every DynamicContainer challenge,
+// or of the challenges named in only, pushes it to the registry of
+// RegistryConfig and pins the pushed digest in image-digests.yaml and
+// the synced challenge. Up to
+// workers images are built at once, in depends_on order
+func (gz *GZ) BuildImages(only []string, workers int) ([]BuildResult, error) {
+	config, err := lookupConfig(gz.api)
+	if err != nil {
+		return nil, err
+	}
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDependencies(challengesConf); err != nil {
+		return nil, err
+	}
+
+	selected := map[string]bool{}
+	for _, name := range only {
+		selected[name] = true
+	}
+	var targets []ChallengeYaml
+	for _, challengeConf := range challengesConf {
+		if challengeConf.Type != "DynamicContainer" || (len(only) > 0 && !selected[challengeConf.Name]) {
+			continue
+		}
+		delete(selected, challengeConf.Name)
+		targets = append(targets, challengeConf)
+	}
+	for name := range selected {
+		return nil, fmt.Errorf("no DynamicContainer challenge named %q", name)
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+
+	registry, err := getRegistrySettings()
+	if err != nil {
+		return nil, err
+	}
+	if err := registry.login(); err != nil {
+		return nil, err
+	}
+
+	var challenges []gzapi.Challenge
+	if game, err := gz.api.GetGameById(config.Event.Id); err != nil {
+		log.Error("Failed to fetch the game, digests are pinned on the next sync: %v", err)
+	} else if challenges, err = game.Handle().GetChallenges(); err != nil {
+		log.Error("Failed to fetch challenges, digests are pinned on the next sync: %v", err)
+	}
+
+	results := map[string]*BuildResult{}
+	for _, challengeConf := range targets {
+		results[challengeConf.Name] = &BuildResult{
+			Name:     challengeConf.Name,
+			Category: challengeConf.Category,
+			Image:    registry.tag(challengeConf.Container.ContainerImage),
+		}
+	}
+
+	pool := newSlotPool(max(workers, 1), config.SyncPolicy)
+	runInDependencyOrder(context.Background(), targets, pool, false, func(c ChallengeYaml) error {
+		result := results[c.Name]
+		digest, err := buildAndPush(c, result.Image)
+		if err != nil {
+			return err
+		}
+		result.Digest = digest
+		if err := pinImageDigest(c.Container.ContainerImage, digest); err != nil {
+			return err
+		}
+		for i := range challenges {
+			if challenges[i].Title != c.Name {
+				continue
+			}
+			value, _ := json.Marshal(digest)
+			return challenges[i].UpdateFields(map[string]json.RawMessage{"containerImage": value})
+		}
+		log.InfoH2("%s is not synced yet, its digest is pinned on the next sync", c.Name)
+		return nil
+	}, func(c ChallengeYaml, err error) {
+		if err != nil {
+			results[c.Name].Error = err.Error()
+			log.Error("%s: %v", c.Name, err)
+		}
+	})
+
+	var summary []BuildResult
+	failed := 0
+	for _, challengeConf := range targets {
+		result := *results[challengeConf.Name]
+		if result.Error != "" {
+			failed++
+		}
+		summary = append(summary, result)
+	}
+	return summary, recordOperation("build", map[string]string{
+		"images": fmt.Sprint(len(summary)),
+		"failed": fmt.Sprint(failed),
+	})
+}
+
+// buildAndPush builds the image of a challenge as tag, pushes it and returns
+// the repository@digest reference the registry stored it as
+func buildAndPush(challengeConf ChallengeYaml, tag string) (string, error) {
+	dir, err := buildContext(challengeConf)
+	if err != nil {
+		return "", err
+	}
+
+	log.Info("Build %s as %s", challengeConf.Name, tag)
+	if output, err := exec.Command("docker", "build", "-t", tag, dir).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("docker build: %w\n%s", err, output)
+	}
+	log.InfoH2("Push %s", tag)
+	if output, err := exec.Command("docker", "push", tag).CombinedOutput(); err != nil {
+		return "", fmt.Errorf("docker push: %w\n%s", err, output)
+	}
+
+	output, err := exec.Command("docker", "image", "inspect", "--format", "{{json .RepoDigests}}", tag).Output()
+	if err != nil {
+		return "", fmt.Errorf("docker image inspect: %w", err)
+	}
+	var digests []string
+	if err := json.Unmarshal(output, &digests); err != nil {
+		return "", fmt.Errorf("docker image inspect: %w", err)
+	}
+	repository, _ := splitImageTag(tag)
+	for _, digest := range digests {
+		if strings.HasPrefix(digest, repository+"@") {
+			return digest, nil
+		}
+	}
+	return "", fmt.Errorf("no digest of %s after push", repository)
+}
+
+func imageDigestsPath() string {
+	return filepath.Join(getWorkDir(), imageDigestsFile)
+}
+
+// loadImageDigests reads image-digests.yaml. A missing file pins
+// nothing
+func loadImageDigests() (map[string]string, error) {
+	digests := map[string]string{}
+	data, err := os.ReadFile(imageDigestsPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return digests, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &digests); err != nil {
+		return nil, fmt.Errorf("%s: %w", imageDigestsFile, err)
+	}
+	return digests, nil
+}
+
+func saveImageDigests(digests map[string]string) error {
+	data, err := yaml.Marshal(digests)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(imageDigestsPath(), append([]byte(imageDigestsHeader), data...), 0644)
+}
+
+func pinImageDigest(image, digest string) error {
+	imageDigestsMu.Lock()
+	defer imageDigestsMu.Unlock()
+
+	digests, err := loadImageDigests()
+	if err != nil {
+		return err
+	}
+	digests[image] = digest
+	return saveImageDigests(digests)
+}
+
+// pinnedImage returns the digest gzcli build last pushed image as, or image
+// itself when it is not pinned
+func pinnedImage(image string) string {
+	imageDigestsMu.Lock()
+	defer imageDigestsMu.Unlock()
+
+	digests, err := loadImageDigests()
+	if err != nil {
+		log.ErrorH2("Deploy %s unpinned: %v", image, err)
+		return image
+	}
+	if digests[image] == "" {
+		return image
+	}
+	return digests[image]
+}
+
+// UnpinImages drops the pinned digests of the DynamicContainer challenges
+// named in only, or of every image, so the next sync deploys containerImage
+// as written in challenge.yml. It returns the unpinned images
+func UnpinImages(only []string) ([]string, error) {
+	imageDigestsMu.Lock()
+	defer imageDigestsMu.Unlock()
+
+	digests, err := loadImageDigests()
+	if err != nil {
+		return nil, err
+	}
+	images := make([]string, 0, len(digests))
+	if len(only) == 0 {
+		for image := range digests {
+			images = append(images, image)
+		}
+	} else {
+		config, err := getLocalConfig()
+		if err != nil {
+			return nil, err
+		}
+		challengesConf, err := GetChallengesYaml(config)
+		if err != nil {
+			return nil, err
+		}
+		byName := map[string]string{}
+		for _, challengeConf := range challengesConf {
+			if challengeConf.Type == "DynamicContainer" {
+				byName[challengeConf.Name] = challengeConf.Container.ContainerImage
+			}
+		}
+		for _, name := range only {
+			image, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("no DynamicContainer challenge named %q", name)
+			}
+			if _, pinned := digests[image]; pinned {
+				images = append(images, image)
+			}
+		}
+	}
+	sort.Strings(images)
+	if len(images) == 0 {
+		return nil, nil
+	}
+
+	for _, image := range images {
+		delete(digests, image)
+	}
+	if err := saveImageDigests(digests); err != nil {
+		return nil, err
+	}
+	return images, recordOperation("build.unpin", map[string]string{"images": strings.Join(images, ",")})
+}
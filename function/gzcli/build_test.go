@@ -0,0 +1,44 @@
+package gzcli
+
+import "testing"
+
+func TestSplitImageTag(t *testing.T) {
+	for _, tt := range []struct {
+		image, repository, tag string
+	}{
+		{"nginx", "nginx", ""},
+		{"nginx:1.25", "nginx", "1.25"},
+		{"localhost:5000/web", "localhost:5000/web", ""},
+		{"localhost:5000/web:v2", "localhost:5000/web", "v2"},
+	} {
+		t.Run(tt.image, func(t *testing.T) {
+			repository, tag := splitImageTag(tt.image)
+			if repository != tt.repository || tag != tt.tag {
+				t.Fatalf("split = %q, %q, want %q, %q", repository, tag, tt.repository, tt.tag)
+			}
+		})
+	}
+}
+
+func TestRegistrySettingsTag(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		registry string
+		image    string
+		want     string
+	}{
+		{"default tag", "", "ctf/web", "ctf/web:latest"},
+		{"keeps tag", "", "ctf/web:v1", "ctf/web:v1"},
+		{"drops digest", "", "ctf/web:v1@sha256:abc", "ctf/web:v1"},
+		{"moves to registry", "registry.example.com", "ctf/web:v1", "registry.example.com/ctf/web:v1"},
+		{"replaces registry host", "registry.example.com", "ghcr.io/ctf/web", "registry.example.com/ctf/web:latest"},
+		{"replaces registry port", "registry.example.com", "localhost:5000/web", "registry.example.com/web:latest"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &registrySettings{ServerAddress: tt.registry}
+			if got := r.tag(tt.image); got != tt.want {
+				t.Fatalf("tag = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,89 +1,154 @@
 package gzcli
 
 import (
-	"bufio"
-	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
 
-	"gopkg.in/yaml.v2"
+	"github.com/dimasma0305/ctfify/function/gzcli/cache"
 )
 
+// remoteCacheTTL bounds how long state mirrored from the platform, like the
+// game and challenge ids, is trusted without asking the platform again
+const remoteCacheTTL = 24 * time.Hour
+
 // cacheDir caches the working directory to avoid repeated lookups
 var cacheDir = func() string {
 	dir, _ := os.Getwd()
 	return filepath.Join(dir, ".gzcli")
 }()
 
-// setCache atomically writes data to cache with proper directory creation
-func setCache(key string, data any) error {
-	cachePath := filepath.Join(cacheDir, key+".yaml")
+// cacheURL is the platform url of the loaded conf.yaml. Remote state cached
+// for another url is ignored
+var cacheURL atomic.Value
 
-	// Create cache directory with proper permissions
-	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
-		return fmt.Errorf("failed to create cache directory: %w", err)
-	}
+func setCacheURL(url string) {
+	cacheURL.Store(url)
+}
 
-	// Atomic write pattern using temp file
-	tmpFile, err := os.CreateTemp(cacheDir, "tmp-")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-	defer os.Remove(tmpFile.Name())
+func cacheStore() cache.Store {
+	url, _ := cacheURL.Load().(string)
+	return cache.Store{Dir: cacheDir, URL: url}
+}
 
-	// Use buffered writer with pre-allocated buffer
-	bw := bufio.NewWriterSize(tmpFile, 32*1024) // 32KB buffer
-	if err := yaml.NewEncoder(bw).Encode(data); err != nil {
-		return fmt.Errorf("encoding failed: %w", err)
-	}
+// setCache writes local data that stays valid whatever platform is used
+func setCache(key string, data any) error {
+	return cacheStore().Set(key, data, 0, "")
+}
 
-	// Flush buffer before renaming
-	if err := bw.Flush(); err != nil {
-		return fmt.Errorf("buffer flush failed: %w", err)
-	}
-	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("temp file close failed: %w", err)
-	}
+// setRemoteCache writes state mirrored from the platform, which expires after
+// remoteCacheTTL and is dropped when conf.yaml points to another url
+func setRemoteCache(key string, data any) error {
+	store := cacheStore()
+	return store.Set(key, data, remoteCacheTTL, store.URL)
+}
 
-	// Atomic rename to final path
-	if err := os.Rename(tmpFile.Name(), cachePath); err != nil {
-		return fmt.Errorf("failed to finalize cache: %w", err)
-	}
+// GetCache reads cached data, failing for missing, expired and stale entries
+func GetCache(key string, data any) error {
+	return cacheStore().Get(key, data)
+}
 
-	return nil
+// isTeamsCredsKey reports whether key holds the generated team credentials,
+// which are kept by ClearCache even when written before metadata existed
+func isTeamsCredsKey(key string) bool {
+	return key == teamsCredsCacheKey || key == teamsCredsSealedKey
 }
 
-// GetCache reads cached data using optimized file access
-func GetCache(key string, data any) error {
-	cachePath := filepath.Join(cacheDir, key+".yaml")
+// getLocalCache reads data written by setCache. It belongs to no platform,
+// so an entry written before metadata existed is never stale
+func getLocalCache(key string, data any) error {
+	return cache.Store{Dir: cacheDir}.Get(key, data)
+}
 
-	file, err := os.Open(cachePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("cache not found")
-		}
-		return fmt.Errorf("cache access error: %w", err)
+// DeleteCache removes a cache entry
+func DeleteCache(key string) error {
+	return cacheStore().Delete(key)
+}
+
+// CacheEntry is a cache entry with its state against the current conf.yaml
+type CacheEntry struct {
+	cache.Entry
+	// State is "fresh", "expired" or "stale"
+	State string
+}
+
+func cacheState(entry cache.Entry, url string) string {
+	switch entry.State(url, time.Now()) {
+	case cache.ErrExpired:
+		return "expired"
+	case cache.ErrStale:
+		return "stale"
 	}
-	defer file.Close()
+	return "fresh"
+}
 
-	buffered := bufio.NewReader(file)
-	if err := yaml.NewDecoder(buffered).Decode(data); err != nil {
-		return fmt.Errorf("decoding error: %w", err)
+// loadCacheURL binds the cache to the url of conf.yaml when there is one, so
+// listing and clearing can tell stale entries apart
+func loadCacheURL() string {
+	if config, err := getLocalConfig(); err == nil && config.Url != "" {
+		setCacheURL(config.Url)
 	}
+	return cacheStore().URL
+}
 
-	return nil
+// ListCache returns every cache entry with its state
+func ListCache() ([]CacheEntry, error) {
+	url := loadCacheURL()
+	entries, err := cacheStore().List()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]CacheEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, CacheEntry{Entry: entry, State: cacheState(entry, url)})
+	}
+	return result, nil
 }
 
-// DeleteCache removes cache files with minimal syscalls
-func DeleteCache(key string) error {
-	cachePath := filepath.Join(cacheDir, key+".yaml")
+// ShowCache returns the metadata and stored YAML of a cache entry
+func ShowCache(key string) (*CacheEntry, []byte, error) {
+	url := loadCacheURL()
+	store := cacheStore()
+	entry, err := store.Stat(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := store.Raw(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &CacheEntry{Entry: entry, State: cacheState(entry, url)}, data, nil
+}
 
-	if err := os.Remove(cachePath); err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("cache not found: %s", key)
+// ClearCache removes the given keys. Without keys it removes expired and
+// stale entries and all platform state, keeping local data like generated
+// team credentials unless all is set
+func ClearCache(keys []string, all bool) ([]string, error) {
+	store := cacheStore()
+	if len(keys) > 0 {
+		for _, key := range keys {
+			if err := store.Delete(key); err != nil {
+				return nil, err
+			}
 		}
-		return fmt.Errorf("deletion error: %w", err)
+		return keys, recordOperation("cache.clear", map[string]string{"keys": strings.Join(keys, ",")})
 	}
 
-	return nil
+	entries, err := ListCache()
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, entry := range entries {
+		if !all && (entry.State == "fresh" && entry.URL == "" || isTeamsCredsKey(entry.Key)) {
+			continue
+		}
+		if err := store.Delete(entry.Key); err != nil {
+			return removed, err
+		}
+		removed = append(removed, entry.Key)
+	}
+	return removed, recordOperation("cache.clear", map[string]string{"keys": strings.Join(removed, ",")})
 }
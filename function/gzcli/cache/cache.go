@@ -0,0 +1,217 @@
+// Package cache stores YAML entries under a directory with an optional time
+// to live and the platform URL they were fetched from, so state of one GZCTF
+// instance is never reused against another
+package cache
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	dataExt = ".yaml"
+	metaExt = ".meta"
+)
+
+var (
+	ErrNotFound = errors.New("cache not found")
+	ErrExpired  = errors.New("cache expired")
+	ErrStale    = errors.New("cache belongs to another platform url")
+)
+
+// Entry describes a cached value. Entries written before metadata existed
+// only have a Key and Size
+type Entry struct {
+	Key     string    `yaml:"key"`
+	Created time.Time `yaml:"created,omitempty"`
+	Expires time.Time `yaml:"expires,omitempty"`
+	URL     string    `yaml:"url,omitempty"`
+	Size    int64     `yaml:"-"`
+}
+
+// legacy reports whether the entry was written before metadata existed
+func (e Entry) legacy() bool {
+	return e.Created.IsZero()
+}
+
+// State reports whether the entry is usable against url at now. Entries
+// written before metadata existed may come from any platform, so they are
+// stale once url is known
+func (e Entry) State(url string, now time.Time) error {
+	if !e.Expires.IsZero() && now.After(e.Expires) {
+		return ErrExpired
+	}
+	if url != "" && (e.legacy() || e.URL != "" && e.URL != url) {
+		return ErrStale
+	}
+	return nil
+}
+
+// Store is a cache directory. URL is the platform the caller talks to now,
+// entries bound to another one are treated as missing
+type Store struct {
+	Dir string
+	URL string
+}
+
+func (s Store) dataPath(key string) string {
+	return filepath.Join(s.Dir, filepath.FromSlash(key)+dataExt)
+}
+
+func (s Store) metaPath(key string) string {
+	return filepath.Join(s.Dir, filepath.FromSlash(key)+metaExt)
+}
+
+// Set writes data under key, then its metadata, each file atomically. An
+// entry whose metadata write fails reads as one written before metadata
+// existed. A zero ttl never expires and an empty url does not bind the entry
+// to a platform
+func (s Store) Set(key string, data any, ttl time.Duration, url string) error {
+	entry := Entry{Key: key, Created: time.Now(), URL: url}
+	if ttl > 0 {
+		entry.Expires = entry.Created.Add(ttl)
+	}
+	if err := s.write(s.dataPath(key), data); err != nil {
+		return err
+	}
+	return s.write(s.metaPath(key), entry)
+}
+
+func (s Store) write(path string, data any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Atomic write pattern using temp file
+	tmpFile, err := os.CreateTemp(s.Dir, "tmp-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	bw := bufio.NewWriterSize(tmpFile, 32*1024)
+	if err := yaml.NewEncoder(bw).Encode(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("encoding failed: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("buffer flush failed: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("temp file close failed: %w", err)
+	}
+	if err := os.Rename(tmpFile.Name(), path); err != nil {
+		return fmt.Errorf("failed to finalize cache: %w", err)
+	}
+	return nil
+}
+
+// Get decodes the entry of key into data. Expired entries and entries of
+// another platform url are removed and reported as ErrExpired or ErrStale.
+// Entries without metadata are reported as ErrStale but kept, they may hold
+// local data a later Set rewrites
+func (s Store) Get(key string, data any) error {
+	entry, err := s.Stat(key)
+	if err != nil {
+		return err
+	}
+	if err := entry.State(s.URL, time.Now()); err != nil {
+		if !entry.legacy() {
+			s.Delete(key)
+		}
+		return err
+	}
+
+	file, err := os.Open(s.dataPath(key))
+	if err != nil {
+		return fmt.Errorf("cache access error: %w", err)
+	}
+	defer file.Close()
+
+	if err := yaml.NewDecoder(bufio.NewReader(file)).Decode(data); err != nil {
+		return fmt.Errorf("decoding error: %w", err)
+	}
+	return nil
+}
+
+// Stat returns the metadata of key
+func (s Store) Stat(key string) (Entry, error) {
+	info, err := os.Stat(s.dataPath(key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Entry{}, ErrNotFound
+		}
+		return Entry{}, fmt.Errorf("cache access error: %w", err)
+	}
+
+	entry := Entry{Key: key}
+	if meta, err := os.ReadFile(s.metaPath(key)); err == nil {
+		if err := yaml.Unmarshal(meta, &entry); err != nil {
+			return Entry{}, fmt.Errorf("metadata of %s: %w", key, err)
+		}
+		entry.Key = key
+	}
+	entry.Size = info.Size()
+	return entry, nil
+}
+
+// Raw returns the stored YAML of key without checking its state
+func (s Store) Raw(key string) ([]byte, error) {
+	data, err := os.ReadFile(s.dataPath(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Delete removes key and its metadata
+func (s Store) Delete(key string) error {
+	if err := os.Remove(s.dataPath(key)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return fmt.Errorf("deletion error: %w", err)
+	}
+	if err := os.Remove(s.metaPath(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("deletion error: %w", err)
+	}
+	return nil
+}
+
+// List returns every entry of the store ordered by key
+func (s Store) List() ([]Entry, error) {
+	var entries []Entry
+	err := filepath.WalkDir(s.Dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && path == s.Dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(path, dataExt) {
+			return nil
+		}
+		rel, err := filepath.Rel(s.Dir, path)
+		if err != nil {
+			return err
+		}
+		entry, err := s.Stat(filepath.ToSlash(strings.TrimSuffix(rel, dataExt)))
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, err
+}
@@ -0,0 +1,222 @@
+package gzcli
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	canaryCachePrefix      = "canary/"
+	canaryFingerprintCache = "canary/fingerprints"
+	canaryScript           = "canary"
+)
+
+// canarySync deploys the changed challenges into the hidden canary game,
+// verifies them there and returns the challenges that are safe to promote
+func (gz *GZ) canarySync(config *Config, challengesConf []ChallengeYaml) ([]ChallengeYaml, error) {
+	if config.Canary == nil || config.Canary.Title == "" {
+		return nil, fmt.Errorf("canary sync requires canary.title in %s", CONFIG_FILE)
+	}
+
+	changed, err := changedSinceLastPromotion(config, challengesConf)
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) == 0 {
+		log.Info("No changed challenges to canary")
+		return changed, nil
+	}
+
+	canaryGame, err := getOrCreateCanaryGame(config, gz.api)
+	if err != nil {
+		return nil, err
+	}
+
+	canaryConfig := *config
+	canaryConfig.Event = *canaryGame
+	canaryConfig.cachePrefix = config.cachePrefix + canaryCachePrefix
+	// Hints are announced once, when the challenges are promoted
+	canaryConfig.Announce = nil
+
+	log.Info("Deploy %d changed challenges to canary game %s", len(changed), canaryGame.Title)
+	if err := syncChallenges(&canaryConfig, canaryGame.Handle(), changed); err != nil {
+		return nil, fmt.Errorf("canary deploy failed: %w", err)
+	}
+
+	for _, challengeConf := range changed {
+		if err := verifyCanaryChallenge(config, canaryGame, challengeConf); err != nil {
+			return nil, fmt.Errorf("canary verification failed for %s: %w", challengeConf.Name, err)
+		}
+		log.InfoH2("Canary %s passed", challengeConf.Name)
+	}
+
+	log.Info("Promote %d challenges to %s", len(changed), config.Event.Title)
+	return changed, nil
+}
+
+func getOrCreateCanaryGame(config *Config, api *gzapi.GZAPI) (*gzapi.Game, error) {
+	// Only a successful listing without the title creates the game, an outage
+	// must not leave duplicate hidden games behind
+	games, err := api.GetGames()
+	if err != nil {
+		return nil, fmt.Errorf("list games: %w", err)
+	}
+	for _, game := range games {
+		if game.Title == config.Canary.Title {
+			return game, nil
+		}
+	}
+
+	log.Info("Create canary game %s", config.Canary.Title)
+	game, err := api.CreateGame(gzapi.CreateGameForm{
+		Title: config.Canary.Title,
+		Start: config.Event.Start.Time,
+		End:   config.Event.End.Time,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hidden := config.Event
+	hidden.Id = game.Id
+	hidden.PublicKey = game.PublicKey
+	hidden.Title = config.Canary.Title
+	hidden.Hidden = true
+	hidden.Poster = ""
+	if err := game.Update(&hidden); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// verifyCanaryChallenge checks the platform state of a canary challenge,
+// probes its healthcheck, runs its solver and its optional canary script.
+// The solver of a container challenge runs against a test container of the
+// canary game, the one of other challenges against the local deployment
+func verifyCanaryChallenge(config *Config, game *gzapi.Game, challengeConf ChallengeYaml) error {
+	challengeData, err := game.GetChallenge(challengeConf.Name)
+	if err != nil {
+		return err
+	}
+	if err := compareCanaryChallenge(challengeConf, challengeData); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if challengeConf.Healthcheck != nil {
+		if result := probe(ctx, challengeConf); !result.Healthy {
+			return fmt.Errorf("healthcheck failed: %s", result.Error)
+		}
+	}
+	if solverCommand(challengeConf) != "" {
+		resolved := challengeConf
+		if err := resolveChallengeSecrets(&resolved); err != nil {
+			return err
+		}
+		var result *SolverResult
+		if strings.HasSuffix(challengeConf.Type, "Container") {
+			if _, result, err = solveInTestContainer(ctx, challengeData, resolved, config.FlagFormat); err != nil {
+				return fmt.Errorf("solver: %w", err)
+			}
+		} else {
+			solved := testChallenge(ctx, resolved, config.FlagFormat)
+			result = &solved
+		}
+		if !result.Passed {
+			return fmt.Errorf("solver failed: %s", result.Error)
+		}
+	}
+
+	if challengeConf.Scripts[canaryScript] == "" {
+		return nil
+	}
+	log.InfoH2("Running canary script for %s", challengeConf.Name)
+	return runChallengeShell(challengeConf, challengeConf.Scripts[canaryScript], []string{
+		fmt.Sprintf("CANARY_GAME_ID=%d", game.Id),
+		fmt.Sprintf("CANARY_CHALLENGE_ID=%d", challengeData.Id),
+	})
+}
+
+// compareCanaryChallenge checks that the platform holds the flags,
+// attachment and image of challengeConf. The flags are compared once their
+// secret references are resolved, the way sync deployed them
+func compareCanaryChallenge(challengeConf ChallengeYaml, challengeData *gzapi.Challenge) error {
+	if err := resolveChallengeSecrets(&challengeConf); err != nil {
+		return err
+	}
+	for i, flag := range challengeConf.Flags {
+		if !isFlagExist(flag, challengeData.Flags) {
+			return fmt.Errorf("flags[%d] missing on platform", i)
+		}
+	}
+	if challengeConf.Provide != nil && challengeData.Attachment == nil {
+		return fmt.Errorf("attachment missing on platform")
+	}
+	if challengeData.ContainerImage != pinnedImage(challengeConf.Container.ContainerImage) {
+		return fmt.Errorf("container image mismatch: %q", challengeData.ContainerImage)
+	}
+	return nil
+}
+
+// changedSinceLastPromotion returns the challenges whose fingerprint differs
+// from the one saved when they were last promoted to the game of config
+func changedSinceLastPromotion(config *Config, challengesConf []ChallengeYaml) ([]ChallengeYaml, error) {
+	fingerprints := map[string]string{}
+	if err := GetCache(config.cachePrefix+canaryFingerprintCache, &fingerprints); err != nil {
+		log.InfoH2("No previous canary promotion found")
+	}
+
+	var changed []ChallengeYaml
+	for _, challengeConf := range challengesConf {
+		fingerprint, err := challengeFingerprint(challengeConf)
+		if err != nil {
+			return nil, err
+		}
+		if fingerprints[challengeConf.Category+"/"+challengeConf.Name] != fingerprint {
+			changed = append(changed, challengeConf)
+		}
+	}
+	return changed, nil
+}
+
+func saveCanaryFingerprints(config *Config, promoted []ChallengeYaml) error {
+	fingerprints := map[string]string{}
+	GetCache(config.cachePrefix+canaryFingerprintCache, &fingerprints)
+
+	for _, challengeConf := range promoted {
+		fingerprint, err := challengeFingerprint(challengeConf)
+		if err != nil {
+			return err
+		}
+		fingerprints[challengeConf.Category+"/"+challengeConf.Name] = fingerprint
+	}
+	return setCache(config.cachePrefix+canaryFingerprintCache, fingerprints)
+}
+
+// challengeFingerprint hashes the challenge config together with its local attachment content
+func challengeFingerprint(challengeConf ChallengeYaml) (string, error) {
+	h := sha256.New()
+
+	conf, err := yaml.Marshal(challengeConf)
+	if err != nil {
+		return "", err
+	}
+	h.Write(conf)
+	fmt.Fprintf(h, "%s\x00%s\x00", challengeConf.Category, challengeConf.Cwd)
+
+	if challengeConf.Provide == nil || strings.HasPrefix(*challengeConf.Provide, "http") {
+		return fmt.Sprintf("%x", h.Sum(nil)), nil
+	}
+
+	if err := hashTree(h, filepath.Join(challengeConf.Cwd, *challengeConf.Provide)); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
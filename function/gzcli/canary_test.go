@@ -0,0 +1,33 @@
+package gzcli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+)
+
+func TestCompareCanaryChallenge(t *testing.T) {
+	t.Setenv(secretEnvPrefix+"FLAG_PROD", "CTF{prod}")
+	platform := &gzapi.Challenge{Flags: []gzapi.Flag{{Flag: "CTF{prod}"}, {Flag: "CTF{plain}"}}}
+	for _, tt := range []struct {
+		name    string
+		flags   []string
+		wantErr string
+	}{
+		{"plain", []string{"CTF{plain}"}, ""},
+		{"secret", []string{secretStoreScheme + "flag_prod", "CTF{plain}"}, ""},
+		{"missing", []string{"CTF{other}"}, "flags[0] missing"},
+		{"unresolved", []string{secretStoreScheme + "flag_missing"}, "flag_missing"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := compareCanaryChallenge(ChallengeYaml{Name: tt.name, Flags: tt.flags}, platform)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
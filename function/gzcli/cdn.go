@@ -0,0 +1,154 @@
+package gzcli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"text/template"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// defaultCDNTimeout bounds a CDN upload or check when uploadTimeout is not set
+const defaultCDNTimeout = 5 * time.Minute
+
+// CDNConfig mirrors local attachments to external storage. UploadUrl and
+// PublicUrl are Go templates receiving .Hash, .Name and .Slug
+type CDNConfig struct {
+	UploadUrl string            `yaml:"uploadUrl"`
+	PublicUrl string            `yaml:"publicUrl"`
+	Headers   map[string]string `yaml:"headers"`
+}
+
+type cdnObject struct {
+	Hash string
+	Name string
+	Slug string
+}
+
+func renderCDNUrl(tmpl string, object cdnObject) (string, error) {
+	t, err := template.New("cdn").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, object); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// mirrorAttachmentToCDN uploads file to the CDN of config, verifies the
+// served copy and registers it as a remote attachment. Every CDN request is
+// bounded by uploadTimeout, or defaultCDNTimeout
+func mirrorAttachmentToCDN(config *Config, challengeConf ChallengeYaml, challengeData *gzapi.Challenge, file string) error {
+	cdn := config.CDN
+	opts, err := uploadOptions(config)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: defaultCDNTimeout}
+	if opts.Timeout > 0 {
+		client.Timeout = opts.Timeout
+	}
+
+	hash, err := GetFileHashHex(file)
+	if err != nil {
+		return err
+	}
+
+	object := cdnObject{Hash: hash, Name: filepath.Base(file), Slug: generateSlug(challengeConf)}
+	uploadUrl, err := renderCDNUrl(cdn.UploadUrl, object)
+	if err != nil {
+		return fmt.Errorf("cdn uploadUrl: %w", err)
+	}
+	publicUrl, err := renderCDNUrl(cdn.PublicUrl, object)
+	if err != nil {
+		return fmt.Errorf("cdn publicUrl: %w", err)
+	}
+
+	if challengeData.Attachment != nil && challengeData.Attachment.Url == publicUrl {
+		log.Info("Attachment for %s is the same...", challengeConf.Name)
+		return nil
+	}
+
+	if servedHash, err := cdnObjectHash(client, publicUrl); err != nil || servedHash != hash {
+		log.Info("Upload attachment for %s to CDN", challengeConf.Name)
+		if err := cdnUpload(client, uploadUrl, file, cdn.Headers); err != nil {
+			return fmt.Errorf("cdn upload for %s: %w", challengeConf.Name, err)
+		}
+		servedHash, err = cdnObjectHash(client, publicUrl)
+		if err != nil {
+			return fmt.Errorf("cdn verification for %s: %w", challengeConf.Name, err)
+		}
+		if servedHash != hash {
+			return fmt.Errorf("cdn copy of %s does not match local artifact (%s != %s)", challengeConf.Name, servedHash, hash)
+		}
+	}
+
+	log.Info("Update remote attachment for %s", challengeConf.Name)
+	return challengeData.CreateAttachment(gzapi.CreateAttachmentForm{
+		AttachmentType: "Remote",
+		RemoteUrl:      publicUrl,
+	})
+}
+
+func cdnUpload(client *http.Client, url string, file string, headers map[string]string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPut, url, f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/octet-stream")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request end with %d status, %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// cdnObjectHash downloads the public object and returns its sha256
+func cdnObjectHash(client *http.Client, url string) (string, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("request end with %d status", resp.StatusCode)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, resp.Body); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
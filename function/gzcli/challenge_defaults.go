@@ -0,0 +1,192 @@
+package gzcli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+const extendsKey = "extends"
+
+// challengeDefaultsFiles are looked up in every category directory and
+// merged into each challenge.yml of the category
+var challengeDefaultsFiles = []string{"_defaults.yml", "_defaults.yaml"}
+
+// categoryDefaultsFile returns the defaults file of a category directory,
+// or "" when it has none
+func categoryDefaultsFile(categoryPath string) string {
+	for _, name := range challengeDefaultsFiles {
+		file := filepath.Join(categoryPath, name)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+	return ""
+}
+
+// applyChallengeDefaults merges the category _defaults.yml, then the files
+// named by `extends:` (relative to the file that names them), then the
+// challenge itself. Mappings are merged key by key with the more specific
+// file winning, sequences and scalars are replaced as a whole and a null
+// value removes the inherited key. It returns the merged mapping and the
+// files it was merged from. When origins is not nil, every node of the
+// result that comes from another file than path is mapped to that file
+func applyChallengeDefaults(categoryPath string, path string, root *yaml.Node, origins map[*yaml.Node]string) (*yaml.Node, []string, error) {
+	chain, sources, err := extendsChain(path, root, map[string]bool{})
+	if err != nil {
+		return nil, nil, err
+	}
+	files := append(append([]string{}, sources...), path)
+	if defaults := categoryDefaultsFile(categoryPath); defaults != "" {
+		node, err := readYamlMapping(defaults)
+		if err != nil {
+			return nil, nil, err
+		}
+		chain = append([]*yaml.Node{node}, chain...)
+		files = append([]string{defaults}, files...)
+		sources = append(sources, defaults)
+	}
+	if origins != nil {
+		for i, node := range chain[:len(chain)-1] {
+			markYamlOrigin(node, files[i], origins)
+		}
+	}
+
+	merged := chain[0]
+	for _, node := range chain[1:] {
+		merged = mergeYamlNodes(merged, node, origins)
+	}
+	return withoutKey(merged, extendsKey), sources, nil
+}
+
+// markYamlOrigin maps node and everything below it to file
+func markYamlOrigin(node *yaml.Node, file string, origins map[*yaml.Node]string) {
+	origins[node] = file
+	for _, child := range node.Content {
+		markYamlOrigin(child, file, origins)
+	}
+}
+
+// extendsChain returns root preceded by the files it extends, most general
+// first
+func extendsChain(path string, root *yaml.Node, seen map[string]bool) ([]*yaml.Node, []string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if seen[abs] {
+		return nil, nil, fmt.Errorf("extends cycle at %s", path)
+	}
+	seen[abs] = true
+
+	extends := mappingFields(root)[extendsKey]
+	if extends == nil {
+		return []*yaml.Node{root}, nil, nil
+	}
+	if extends.Kind != yaml.ScalarNode || extends.Value == "" {
+		return nil, nil, fmt.Errorf("%s:%d: extends must be a file path", path, extends.Line)
+	}
+	base := extends.Value
+	if !filepath.IsAbs(base) {
+		base = filepath.Join(filepath.Dir(path), base)
+	}
+	node, err := readYamlMapping(base)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s:%d: extends: %w", path, extends.Line, err)
+	}
+	chain, sources, err := extendsChain(base, node, seen)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(chain, root), append(sources, base), nil
+}
+
+func readYamlMapping(path string) (*yaml.Node, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}, nil
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s must be a yaml mapping", path)
+	}
+	return doc.Content[0], nil
+}
+
+// mergeYamlNodes merges override into base without modifying either. A
+// merged mapping takes the position of override, and its file in origins
+// when origins is not nil
+func mergeYamlNodes(base, override *yaml.Node, origins map[*yaml.Node]string) *yaml.Node {
+	if base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode {
+		return override
+	}
+	merged := *base
+	merged.Content = nil
+	merged.Line, merged.Column = override.Line, override.Column
+	if origin, ok := origins[override]; ok {
+		origins[&merged] = origin
+	}
+	overrides := mappingFields(override)
+	for i := 0; i+1 < len(base.Content); i += 2 {
+		key, value := base.Content[i], base.Content[i+1]
+		if o, ok := overrides[key.Value]; ok {
+			if o.Tag == "!!null" {
+				continue
+			}
+			value = mergeYamlNodes(value, o, origins)
+		}
+		merged.Content = append(merged.Content, key, value)
+	}
+	inherited := mappingFields(base)
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+		if _, ok := inherited[key.Value]; !ok && value.Tag != "!!null" {
+			merged.Content = append(merged.Content, key, value)
+		}
+	}
+	return &merged
+}
+
+func withoutKey(mapping *yaml.Node, name string) *yaml.Node {
+	result := *mapping
+	result.Content = nil
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != name {
+			result.Content = append(result.Content, mapping.Content[i], mapping.Content[i+1])
+		}
+	}
+	return &result
+}
+
+// mergeChallengeSource returns the content of a challenge.yml with its
+// category defaults and extends applied, and the files merged into it.
+// Challenges without either are returned unchanged
+func mergeChallengeSource(categoryPath string, path string, content []byte) ([]byte, []string, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		// Left to the regular parser to report
+		return content, nil, nil
+	}
+	root := doc.Content[0]
+	if categoryDefaultsFile(categoryPath) == "" && mappingFields(root)[extendsKey] == nil {
+		return content, nil, nil
+	}
+
+	merged, sources, err := applyChallengeDefaults(categoryPath, path, root, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, sources, nil
+}
@@ -0,0 +1,83 @@
+package gzcli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMergeYamlNodes(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		base     string
+		override string
+		want     string
+	}{
+		{"override scalar", "value: 100\nauthor: a\n", "value: 500\n", "value: 500\nauthor: a\n"},
+		{"add key", "author: a\n", "name: b\n", "author: a\nname: b\n"},
+		{"merge mapping", "container:\n  cpuCount: 1\n  memoryLimit: 128\n", "container:\n  cpuCount: 2\n", "container:\n    cpuCount: 2\n    memoryLimit: 128\n"},
+		{"replace sequence", "flags:\n  - a\n  - b\n", "flags:\n  - c\n", "flags:\n    - c\n"},
+		{"null removes", "author: a\nvalue: 100\n", "author: null\n", "value: 100\n"},
+		{"null of missing key", "value: 100\n", "author: ~\n", "value: 100\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			base, override := testYamlMapping(t, tt.base), testYamlMapping(t, tt.override)
+			before, _ := yaml.Marshal(base)
+
+			out, err := yaml.Marshal(mergeYamlNodes(base, override, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Fatalf("merged =\n%s\nwant\n%s", out, tt.want)
+			}
+			if after, _ := yaml.Marshal(base); string(after) != string(before) {
+				t.Fatalf("base was modified:\n%s", after)
+			}
+		})
+	}
+}
+
+func TestLintReportsInheritedFile(t *testing.T) {
+	categoryPath := t.TempDir()
+	defaults := filepath.Join(categoryPath, "_defaults.yml")
+	if err := os.WriteFile(defaults, []byte("author: a\ncontainer:\n  cpuCount: -1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(categoryPath, "chall", "challenge.yml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	challenge := "name: chall\ntype: DynamicContainer\nbogus: 1\ncontainer:\n  flagTemplate: flag{[GUID]}\n  containerImage: chall\n  containerExposePort: 80\n"
+	if err := os.WriteFile(path, []byte(challenge), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := lintChallengeFile(categoryPath, path, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var inherited, own bool
+	for _, issue := range issues {
+		switch {
+		case strings.Contains(issue.Message, "cpuCount"):
+			inherited = issue.File == defaults && issue.Line == 3
+		case strings.Contains(issue.Message, "bogus"):
+			own = issue.File == "" && issue.Line == 3
+		}
+	}
+	if !inherited || !own {
+		t.Fatalf("issues = %v", issues)
+	}
+}
+
+func testYamlMapping(t *testing.T, content string) *yaml.Node {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc.Content[0]
+}
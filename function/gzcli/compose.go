@@ -0,0 +1,52 @@
+package gzcli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// composeFiles are looked up in the challenge directory and then in src/
+var composeFiles = []string{"docker-compose.yml", "docker-compose.yaml", "compose.yml", "compose.yaml"}
+
+// composeCommands are the lifecycle scripts a compose file can stand in for
+var composeCommands = map[string]string{
+	"start":             "up -d --build",
+	"stop":              "down --volumes",
+	healthRestartScript: "up -d --build --force-recreate",
+}
+
+// findComposeFile returns the compose file of a challenge relative to its
+// directory, or "" when it has none
+func findComposeFile(challengeConf ChallengeYaml) string {
+	for _, dir := range []string{".", "src"} {
+		for _, name := range composeFiles {
+			file := filepath.Join(dir, name)
+			if _, err := os.Stat(filepath.Join(challengeConf.Cwd, file)); err == nil {
+				return file
+			}
+		}
+	}
+	return ""
+}
+
+// challengeScript returns the script to run for a challenge. Challenges
+// without their own start, stop or restart script fall back to docker
+// compose on their compose file unless challenge.yml sets compose: false
+func challengeScript(challengeConf ChallengeYaml, script string) string {
+	if challengeConf.Scripts[script] != "" {
+		return challengeConf.Scripts[script]
+	}
+	if challengeConf.Compose != nil && !*challengeConf.Compose {
+		return ""
+	}
+	args, ok := composeCommands[script]
+	if !ok {
+		return ""
+	}
+	file := findComposeFile(challengeConf)
+	if file == "" {
+		return ""
+	}
+	return fmt.Sprintf("docker compose -p %s -f %s %s", generateSlug(challengeConf), file, args)
+}
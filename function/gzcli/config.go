@@ -2,7 +2,9 @@ package gzcli
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -37,7 +39,19 @@ var hostCache struct {
 	once sync.Once
 }
 
+// GetConfig reads conf.yaml and looks the game of the event up on the
+// platform, creating it when it does not exist yet
 func GetConfig(api *gzapi.GZAPI) (*Config, error) {
+	return getConfig(api, true)
+}
+
+// lookupConfig is GetConfig for read-only commands, which fail instead of
+// creating a missing game
+func lookupConfig(api *gzapi.GZAPI) (*Config, error) {
+	return getConfig(api, false)
+}
+
+func getConfig(api *gzapi.GZAPI, create bool) (*Config, error) {
 	dir, err := os.Getwd()
 	if err != nil {
 		return nil, err
@@ -47,6 +61,19 @@ func GetConfig(api *gzapi.GZAPI) (*Config, error) {
 	if err := ParseYamlFromFile(confPath, &config); err != nil {
 		return nil, err
 	}
+	if profile != "" {
+		if err := ParseYamlFromFile(profilePath(dir, profile), &config); err != nil {
+			return nil, fmt.Errorf("profile %s: %w", profile, err)
+		}
+	}
+	if err := resolveConfigSecrets(&config); err != nil {
+		return nil, fmt.Errorf("%s: %w", CONFIG_FILE, err)
+	}
+	if err := selectEvent(&config); err != nil {
+		return nil, err
+	}
+	setCacheURL(config.Url)
+	config.Event.Organizations = gameDivisions(&config)
 
 	// Parallel check for cache and API
 	var wg sync.WaitGroup
@@ -56,7 +83,7 @@ func GetConfig(api *gzapi.GZAPI) (*Config, error) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		cacheErr = GetCache("config", &configCache)
+		cacheErr = GetCache(config.configCacheKey(), &configCache)
 	}()
 
 	if api != nil && api.Client != nil {
@@ -64,8 +91,11 @@ func GetConfig(api *gzapi.GZAPI) (*Config, error) {
 		go func() {
 			defer wg.Done()
 			game, err := api.GetGameByTitle(config.Event.Title)
-			if err != nil {
+			switch {
+			case err != nil && create:
 				game, apiErr = createNewGame(&config, api)
+			case err != nil:
+				apiErr = fmt.Errorf("game %q not found on the platform, sync first: %w", config.Event.Title, err)
 			}
 			if game != nil {
 				config.Event.Id = game.Id
@@ -88,6 +118,16 @@ func GetConfig(api *gzapi.GZAPI) (*Config, error) {
 	return &config, nil
 }
 
+// getLocalConfig reads conf.yaml without contacting the platform, for
+// commands that also work in a repository without one
+func getLocalConfig() (*Config, error) {
+	config, err := GetConfig(nil)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &Config{}, nil
+	}
+	return config, err
+}
+
 func generateSlug(challengeConf ChallengeYaml) string {
 	var b strings.Builder
 	b.Grow(len(challengeConf.Category) + len(challengeConf.Name) + 1)
@@ -130,11 +170,12 @@ func GetChallengesYaml(config *Config) ([]ChallengeYaml, error) {
 	}()
 
 	// Process categories in parallel
-	for _, category := range CHALLENGE_CATEGORY {
+	root := config.challengeRootDir(dir)
+	for _, category := range config.challengeCategories() {
 		wg.Add(1)
 		go func(category string) {
 			defer wg.Done()
-			categoryPath := filepath.Join(dir, category)
+			categoryPath := filepath.Join(root, category)
 
 			if _, err := os.Stat(categoryPath); os.IsNotExist(err) {
 				return
@@ -149,6 +190,10 @@ func GetChallengesYaml(config *Config) ([]ChallengeYaml, error) {
 				if err != nil {
 					return fmt.Errorf("reading file error: %w", err)
 				}
+				content, sources, err := mergeChallengeSource(categoryPath, path, content)
+				if err != nil {
+					return err
+				}
 
 				var challenge ChallengeYaml
 				if err := ParseYamlFromBytes(content, &challenge); err != nil {
@@ -157,6 +202,8 @@ func GetChallengesYaml(config *Config) ([]ChallengeYaml, error) {
 
 				challenge.Category = category
 				challenge.Cwd = filepath.Dir(path)
+				challenge.runner = config.ScriptRunner
+				challenge.sources = sources
 
 				if category == "Game Hacking" {
 					challenge.Category = "Reverse"
@@ -0,0 +1,155 @@
+package gzcli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+)
+
+// containerProvider is the ContainerProvider the platform deploys challenges
+// with. PortMapping comes from the server, the rest from appsettings.json
+// when it is available locally
+type containerProvider struct {
+	Type             string
+	PortMapping      string
+	TrafficCapture   bool
+	ChallengeNetwork string
+	SwarmMode        bool
+	local            bool
+}
+
+// imageReference matches registry/repository:tag@digest image references
+var imageReference = regexp.MustCompile(`^(?:[a-zA-Z0-9.-]+(?::[0-9]+)?/)?[a-z0-9]+(?:[._-][a-z0-9]+)*(?:/[a-z0-9]+(?:[._-][a-z0-9]+)*)*(?::[\w][\w.-]{0,127})?(?:@sha256:[a-f0-9]{64})?$`)
+
+func (gz *GZ) containerProvider() (*containerProvider, error) {
+	platform, err := gz.api.GetPlatformConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch platform config: %w", err)
+	}
+	provider := &containerProvider{PortMapping: platform.PortMapping}
+	if provider.PortMapping == "" {
+		provider.PortMapping = gzapi.PortMappingDefault
+	}
+
+	appsettings, err := getAppSettings()
+	if err != nil {
+		return provider, nil
+	}
+	settings, ok := appsettings["ContainerProvider"].(map[string]interface{})
+	if !ok {
+		return provider, nil
+	}
+	provider.local = true
+	provider.Type, _ = settings["Type"].(string)
+	provider.TrafficCapture, _ = settings["EnableTrafficCapture"].(bool)
+	if docker, ok := settings["DockerConfig"].(map[string]interface{}); ok {
+		provider.ChallengeNetwork, _ = docker["ChallengeNetwork"].(string)
+		provider.SwarmMode, _ = docker["SwarmMode"].(bool)
+	}
+	return provider, nil
+}
+
+// validateContainers checks every container challenge against the platform
+// ContainerProvider, catching challenges that deploy but are never reachable
+func (gz *GZ) validateContainers(challengesConf []ChallengeYaml) error {
+	var containers []ChallengeYaml
+	for _, challengeConf := range challengesConf {
+		if strings.HasSuffix(challengeConf.Type, "Container") {
+			containers = append(containers, challengeConf)
+		}
+	}
+	if len(containers) == 0 {
+		return nil
+	}
+
+	provider, err := gz.containerProvider()
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, challengeConf := range containers {
+		for _, problem := range provider.check(challengeConf) {
+			problems = append(problems, fmt.Sprintf("%s: %s", challengeConf.Name, problem))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("container challenges conflict with the platform ContainerProvider:\n  - %s",
+			strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+func (p *containerProvider) check(challengeConf ChallengeYaml) []string {
+	var problems []string
+	container := challengeConf.Container
+
+	if container.EnableTrafficCapture {
+		if p.PortMapping != gzapi.PortMappingPlatformProxy {
+			problems = append(problems, fmt.Sprintf("enableTrafficCapture needs PortMappingType %s, the platform uses %s", gzapi.PortMappingPlatformProxy, p.PortMapping))
+		} else if p.local && !p.TrafficCapture {
+			problems = append(problems, "enableTrafficCapture is set but ContainerProvider.EnableTrafficCapture is off")
+		}
+	}
+
+	if p.local && p.PortMapping == gzapi.PortMappingPlatformProxy && p.Type == "Docker" && p.ChallengeNetwork == "" {
+		problems = append(problems, "the platform proxy cannot reach containers without DockerConfig.ChallengeNetwork")
+	}
+
+	image := container.ContainerImage
+	switch {
+	case !imageReference.MatchString(image):
+		problems = append(problems, fmt.Sprintf("containerImage %q is not a valid image reference", image))
+	case p.local && (p.Type == "Kubernetes" || p.SwarmMode) && isLocalRegistry(image):
+		problems = append(problems, fmt.Sprintf("containerImage %q is on a local registry the %s nodes cannot pull from", image, p.Type))
+	}
+
+	if exposed := dockerfileExposedPorts(challengeConf); len(exposed) > 0 && !exposed[container.ContainerExposePort] {
+		problems = append(problems, fmt.Sprintf("containerExposePort %d is not exposed by the Dockerfile", container.ContainerExposePort))
+	}
+	return problems
+}
+
+func isLocalRegistry(image string) bool {
+	registry, _, found := strings.Cut(image, "/")
+	if !found {
+		return false
+	}
+	host, _, _ := strings.Cut(registry, ":")
+	return host == "localhost" || host == "127.0.0.1"
+}
+
+// dockerfileExposedPorts returns the EXPOSE ports of the challenge
+// Dockerfile, looked up in the challenge directory and then in src/
+func dockerfileExposedPorts(challengeConf ChallengeYaml) map[int]bool {
+	for _, dir := range []string{".", "src"} {
+		f, err := os.Open(filepath.Join(challengeConf.Cwd, dir, "Dockerfile"))
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+
+		ports := map[int]bool{}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 2 || !strings.EqualFold(fields[0], "EXPOSE") {
+				continue
+			}
+			for _, field := range fields[1:] {
+				port, _, _ := strings.Cut(field, "/")
+				if n, err := strconv.Atoi(port); err == nil {
+					ports[n] = true
+				}
+			}
+		}
+		return ports
+	}
+	return nil
+}
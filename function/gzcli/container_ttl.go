@@ -0,0 +1,56 @@
+package gzcli
+
+import (
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// ContainerTTL is a running container that outlives the allowed lifetime
+type ContainerTTL struct {
+	Challenge string
+	Team      string
+	StartedAt time.Time
+	StopAt    time.Time
+	Lifetime  time.Duration
+}
+
+// syncContainerPolicy pushes the containerPolicy of conf.yaml when the
+// platform differs from it
+func (gz *GZ) syncContainerPolicy(config *Config) error {
+	if config.ContainerPolicy == nil {
+		return nil
+	}
+	current, err := gz.api.GetAdminConfig()
+	if err == nil && current.ContainerPolicy == *config.ContainerPolicy {
+		return nil
+	}
+	log.Info("Update container policy")
+	return gz.api.PatchAdminConfig(gzapi.ConfigContainerPolicy, config.ContainerPolicy)
+}
+
+// ContainerTTLReport lists running containers whose total lifetime, including
+// extensions, exceeds max
+func (gz *GZ) ContainerTTLReport(max time.Duration) ([]ContainerTTL, error) {
+	instances, err := gz.api.GetContainerInstances()
+	if err != nil {
+		return nil, err
+	}
+
+	var report []ContainerTTL
+	for _, instance := range instances {
+		lifetime := instance.ExpectStopAt.Sub(instance.StartedAt.Time)
+		if lifetime <= max {
+			continue
+		}
+		report = append(report, ContainerTTL{
+			Challenge: instance.Challenge.Title,
+			Team:      instance.Team.Name,
+			StartedAt: instance.StartedAt.Time,
+			StopAt:    instance.ExpectStopAt.Time,
+			Lifetime:  lifetime,
+		})
+	}
+	return report, nil
+}
@@ -1,32 +1,111 @@
 package gzcli
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
 	"github.com/dimasma0305/ctfify/function/log"
 )
 
-func (gz *GZ) DeleteAllUser() error {
+// UserDeletion is the set of accounts a deletion will remove. Teams are only
+// removed when every member is removed too
+type UserDeletion struct {
+	Users []*gzapi.User `json:"users"`
+	Teams []*gzapi.Team `json:"teams"`
+}
+
+// PlanUserDeletion lists the users and teams matched by filter without
+// deleting anything. The account gzcli is logged in as is never planned,
+// whatever the filter
+func (gz *GZ) PlanUserDeletion(filter UserFilter) (*UserDeletion, error) {
+	matched, err := gz.ListUsers(filter)
+	if err != nil {
+		return nil, err
+	}
+	var users []*gzapi.User
+	for _, user := range matched {
+		if gz.api.Creds != nil && user.UserName == gz.api.Creds.Username {
+			continue
+		}
+		users = append(users, user)
+	}
 	teams, err := gz.api.Teams()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	for t := range teams {
-		log.Info("deleting team %s", teams[t].Name)
-		if err := teams[t].Delete(); err != nil {
-			log.Error("%s", err.Error())
+
+	plan := &UserDeletion{Users: users}
+	deleted := map[string]bool{}
+	for _, user := range users {
+		deleted[user.Id] = true
+	}
+	for _, team := range teams {
+		keep := false
+		for _, member := range team.Members {
+			keep = keep || !deleted[member.Id]
+		}
+		if !keep {
+			plan.Teams = append(plan.Teams, team)
 		}
 	}
-	users, err := gz.api.Users()
-	fmt.Println(users)
+	return plan, nil
+}
+
+// DeleteUsers exports the planned accounts into the backup directory, then
+// deletes the teams and users of the plan and records it in the oplog
+func (gz *GZ) DeleteUsers(plan *UserDeletion) (string, error) {
+	export, err := exportUserDeletion(plan)
 	if err != nil {
-		return err
+		return "", fmt.Errorf("export before deletion failed, nothing was deleted: %w", err)
 	}
-	for i := range users {
-		log.Info("deleting user %s", users[i].UserName)
-		if err := users[i].Delete(); err != nil {
+	log.Info("Exported %d users and %d teams to %s", len(plan.Users), len(plan.Teams), export)
+
+	failed := 0
+	for _, team := range plan.Teams {
+		log.Info("deleting team %s", team.Name)
+		if err := team.Delete(); err != nil {
 			log.Error("%s", err.Error())
+			failed++
 		}
 	}
-	return nil
+	for _, user := range plan.Users {
+		log.Info("deleting user %s", user.UserName)
+		if err := user.Delete(); err != nil {
+			log.Error("%s", err.Error())
+			failed++
+		}
+	}
+
+	if err := recordOperation("users.delete", map[string]string{
+		"users":  fmt.Sprint(len(plan.Users)),
+		"teams":  fmt.Sprint(len(plan.Teams)),
+		"failed": fmt.Sprint(failed),
+		"export": export,
+	}); err != nil {
+		return export, err
+	}
+	if failed > 0 {
+		return export, fmt.Errorf("%d deletions failed", failed)
+	}
+	return export, nil
+}
+
+func exportUserDeletion(plan *UserDeletion) (string, error) {
+	if err := os.MkdirAll(backupDir(), 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(backupDir(), fmt.Sprintf("deleted-users-%s.json", time.Now().Format(backupTimeFormat)))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return path, enc.Encode(plan)
 }
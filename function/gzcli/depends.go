@@ -0,0 +1,160 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// validateUniqueNames rejects challenges sharing a name, which the platform
+// and runInDependencyOrder both key challenges by
+func validateUniqueNames(challengesConf []ChallengeYaml) error {
+	seen := make(map[string]int, len(challengesConf))
+	for _, c := range challengesConf {
+		seen[c.Name]++
+	}
+	var duplicates []string
+	for name, count := range seen {
+		if count > 1 {
+			duplicates = append(duplicates, name)
+		}
+	}
+	if len(duplicates) > 0 {
+		sort.Strings(duplicates)
+		return fmt.Errorf("multiple challenges with the same name found:\n  - %s",
+			strings.Join(duplicates, "\n  - "))
+	}
+	return nil
+}
+
+// validateDependencies checks that challenge names are unique, that every
+// depends_on entry names a challenge of the repository and that the
+// dependencies have no cycle
+func validateDependencies(challengesConf []ChallengeYaml) error {
+	if err := validateUniqueNames(challengesConf); err != nil {
+		return err
+	}
+	deps := make(map[string][]string, len(challengesConf))
+	for _, c := range challengesConf {
+		deps[c.Name] = c.DependsOn
+	}
+
+	var problems []string
+	for _, c := range challengesConf {
+		for _, dep := range c.DependsOn {
+			if _, ok := deps[dep]; !ok {
+				problems = append(problems, fmt.Sprintf("%s depends on unknown challenge %q", c.Name, dep))
+			} else if dep == c.Name {
+				problems = append(problems, fmt.Sprintf("%s depends on itself", c.Name))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("invalid depends_on:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(deps))
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			start := 0
+			for i, n := range path {
+				if n == name {
+					start = i
+				}
+			}
+			return fmt.Errorf("depends_on cycle: %s -> %s", strings.Join(path[start:], " -> "), name)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range deps[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, c := range challengesConf {
+		if err := visit(c.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runInDependencyOrder calls run for every challenge once the challenges it
+// depends on are done and pool has a slot for it, so independent branches
+// still run in parallel. With reverse set a challenge waits for its
+// dependents instead, which is the order to stop them in. Dependencies that
+// are not in challengesConf count as done. A challenge is skipped when one
+// of the challenges it waits for failed or ctx is cancelled. report is called
+// once per challenge, one call at a time, with the error of run or the
+// reason it was skipped. The dependencies must have been validated, which
+// also guarantees unique names
+func runInDependencyOrder(ctx context.Context, challengesConf []ChallengeYaml, pool *slotPool, reverse bool, run func(ChallengeYaml) error, report func(ChallengeYaml, error)) {
+	waitsFor := make(map[string][]string, len(challengesConf))
+	done := make(map[string]chan struct{}, len(challengesConf))
+	for _, c := range challengesConf {
+		done[c.Name] = make(chan struct{})
+	}
+	for _, c := range challengesConf {
+		for _, dep := range c.DependsOn {
+			if _, ok := done[dep]; !ok {
+				continue
+			}
+			if reverse {
+				waitsFor[dep] = append(waitsFor[dep], c.Name)
+			} else {
+				waitsFor[c.Name] = append(waitsFor[c.Name], dep)
+			}
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed = map[string]bool{}
+	)
+	for _, c := range challengesConf {
+		wg.Add(1)
+		go func(c ChallengeYaml) {
+			defer wg.Done()
+			defer close(done[c.Name])
+
+			var err error
+			for _, name := range waitsFor[c.Name] {
+				<-done[name]
+				mu.Lock()
+				if failed[name] && err == nil {
+					err = fmt.Errorf("skipped, %s failed", name)
+				}
+				mu.Unlock()
+			}
+			if err == nil {
+				if err = pool.acquire(ctx, c.Category); err == nil {
+					err = run(c)
+					pool.release(c.Category)
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			failed[c.Name] = err != nil
+			report(c, err)
+		}(c)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,91 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		challenges []ChallengeYaml
+		wantErr    string
+	}{
+		{"valid", []ChallengeYaml{{Name: "a"}, {Name: "b", DependsOn: []string{"a"}}}, ""},
+		{"duplicate", []ChallengeYaml{{Name: "a"}, {Name: "a"}}, "same name"},
+		{"unknown", []ChallengeYaml{{Name: "a", DependsOn: []string{"x"}}}, "unknown challenge"},
+		{"self", []ChallengeYaml{{Name: "a", DependsOn: []string{"a"}}}, "depends on itself"},
+		{"cycle", []ChallengeYaml{{Name: "a", DependsOn: []string{"b"}}, {Name: "b", DependsOn: []string{"a"}}}, "cycle"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDependencies(tt.challenges)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunInDependencyOrder(t *testing.T) {
+	challenges := []ChallengeYaml{
+		{Name: "c", DependsOn: []string{"b"}},
+		{Name: "b", DependsOn: []string{"a"}},
+		{Name: "a"},
+		{Name: "d", DependsOn: []string{"a"}},
+	}
+	for _, tt := range []struct {
+		name    string
+		reverse bool
+		fail    string
+		before  [][2]string
+		skipped []string
+	}{
+		{"forward", false, "", [][2]string{{"a", "b"}, {"b", "c"}, {"a", "d"}}, nil},
+		{"reverse", true, "", [][2]string{{"c", "b"}, {"b", "a"}, {"d", "a"}}, nil},
+		{"failure skips dependents", false, "b", nil, []string{"c"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var order []string
+			reported := map[string]error{}
+			runInDependencyOrder(context.Background(), challenges, newSlotPool(2, nil), tt.reverse, func(c ChallengeYaml) error {
+				mu.Lock()
+				order = append(order, c.Name)
+				mu.Unlock()
+				if c.Name == tt.fail {
+					return fmt.Errorf("failed")
+				}
+				return nil
+			}, func(c ChallengeYaml, err error) {
+				reported[c.Name] = err
+			})
+
+			if len(reported) != len(challenges) {
+				t.Fatalf("reported %d challenges, want %d", len(reported), len(challenges))
+			}
+			position := map[string]int{}
+			for i, name := range order {
+				position[name] = i
+			}
+			for _, pair := range tt.before {
+				if position[pair[0]] > position[pair[1]] {
+					t.Fatalf("%s ran after %s: %v", pair[0], pair[1], order)
+				}
+			}
+			for _, name := range tt.skipped {
+				if _, ran := position[name]; ran {
+					t.Fatalf("%s ran although a dependency failed", name)
+				}
+				if reported[name] == nil {
+					t.Fatalf("%s reported no error", name)
+				}
+			}
+		})
+	}
+}
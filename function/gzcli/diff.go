@@ -0,0 +1,282 @@
+package gzcli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UpdateType is the kind of deploy a change requires, ordered by impact
+type UpdateType string
+
+const (
+	UpdateNone         UpdateType = "none"
+	UpdateMetadata     UpdateType = "metadata"
+	UpdateAttachment   UpdateType = "attachment"
+	UpdateFullRedeploy UpdateType = "redeploy"
+)
+
+var updateTypeRank = map[UpdateType]int{
+	UpdateNone:         0,
+	UpdateMetadata:     1,
+	UpdateAttachment:   2,
+	UpdateFullRedeploy: 3,
+}
+
+// Challenge diff statuses
+const (
+	DiffAdded    = "added"
+	DiffModified = "modified"
+	DiffRemoved  = "removed"
+)
+
+// ChallengeDiff describes how one challenge changed between a git ref and the working tree
+type ChallengeDiff struct {
+	Name        string     `json:"name"`
+	Category    string     `json:"category"`
+	Path        string     `json:"path"`
+	Status      string     `json:"status"`
+	UpdateType  UpdateType `json:"updateType"`
+	Fields      []string   `json:"fields,omitempty"`
+	Attachments []string   `json:"attachments,omitempty"`
+	Files       []string   `json:"files"`
+}
+
+// DiffSince lists the challenges changed since ref, including uncommitted
+// and untracked files
+func DiffSince(ref string) ([]ChallengeDiff, error) {
+	changed, err := runGit("diff", "--name-only", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := runGit("ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+	return diffFiles(ref, "", append(splitLines(changed), splitLines(untracked)...))
+}
+
+// DiffBetween lists the challenges changed by the commits between from and
+// to, leaving out local edits. It is what a pull brought in
+func DiffBetween(from, to string) ([]ChallengeDiff, error) {
+	if from == to {
+		return []ChallengeDiff{}, nil
+	}
+	changed, err := runGit("diff", "--name-only", from+".."+to, "--")
+	if err != nil {
+		return nil, err
+	}
+	return diffFiles(from, to, splitLines(changed))
+}
+
+// diffFiles classifies the files changed between ref and to, given relative
+// to the repository root, into challenge diffs. An empty to is the working
+// tree
+func diffFiles(ref, to string, files []string) ([]ChallengeDiff, error) {
+	root, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := getLocalConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := validateUpdateRules(config.UpdateRules); err != nil {
+		return nil, fmt.Errorf("%s: %w", CONFIG_FILE, err)
+	}
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return nil, err
+	}
+	for _, challengeConf := range challengesConf {
+		if err := validateUpdateRules(challengeConf.UpdateRules); err != nil {
+			return nil, fmt.Errorf("%s: %w", challengeConf.Name, err)
+		}
+	}
+
+	diffs := map[string]*ChallengeDiff{}
+	for _, file := range files {
+		path := filepath.Join(root, filepath.FromSlash(file))
+
+		challengeConf, ok := challengeOfPath(challengesConf, path)
+		if !ok {
+			// A changed _defaults.yml or extends file changes the metadata of
+			// every challenge merged from it
+			for _, c := range challengesOfSource(challengesConf, path) {
+				diff := challengeDiffOf(diffs, c)
+				fileRel, _ := filepath.Rel(c.Cwd, path)
+				diff.Files = append(diff.Files, filepath.ToSlash(fileRel))
+				if updateTypeRank[UpdateMetadata] > updateTypeRank[diff.UpdateType] {
+					diff.UpdateType = UpdateMetadata
+				}
+			}
+			if challengeFileRegex.MatchString(path) {
+				if removed, err := removedChallengeDiff(ref, root, file); err == nil {
+					diffs[removed.Path] = removed
+				}
+			}
+			continue
+		}
+
+		diff := challengeDiffOf(diffs, challengeConf)
+
+		fileRel, _ := filepath.Rel(challengeConf.Cwd, path)
+		fileRel = filepath.ToSlash(fileRel)
+		diff.Files = append(diff.Files, fileRel)
+
+		updateType := classifyChange(config, challengeConf, fileRel)
+		if updateTypeRank[updateType] > updateTypeRank[diff.UpdateType] {
+			diff.UpdateType = updateType
+		}
+
+		switch {
+		case challengeFileRegex.MatchString(fileRel) && !strings.Contains(fileRel, "/"):
+			fields, added := changedChallengeFields(ref, to, file, path)
+			diff.Fields = fields
+			if added {
+				diff.Status = DiffAdded
+				diff.UpdateType = UpdateFullRedeploy
+			}
+		case updateType == UpdateAttachment:
+			diff.Attachments = append(diff.Attachments, fileRel)
+		}
+	}
+
+	result := make([]ChallengeDiff, 0, len(diffs))
+	for _, diff := range diffs {
+		result = append(result, *diff)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result, nil
+}
+
+// challengeDiffOf returns the diff of a challenge, adding an empty one when
+// it has none yet
+func challengeDiffOf(diffs map[string]*ChallengeDiff, challengeConf ChallengeYaml) *ChallengeDiff {
+	rel, _ := filepath.Rel(getWorkDir(), challengeConf.Cwd)
+	diff, ok := diffs[rel]
+	if !ok {
+		diff = &ChallengeDiff{
+			Name:       challengeConf.Name,
+			Category:   challengeConf.Category,
+			Path:       filepath.ToSlash(rel),
+			Status:     DiffModified,
+			UpdateType: UpdateNone,
+		}
+		diffs[rel] = diff
+	}
+	return diff
+}
+
+// challengesOfSource returns the challenges a _defaults.yml or extends file
+// is merged into
+func challengesOfSource(challengesConf []ChallengeYaml, path string) []ChallengeYaml {
+	var result []ChallengeYaml
+	for _, challengeConf := range challengesConf {
+		for _, source := range challengeConf.sources {
+			if abs, err := filepath.Abs(source); err == nil && abs == path {
+				result = append(result, challengeConf)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func challengeOfPath(challengesConf []ChallengeYaml, path string) (ChallengeYaml, bool) {
+	var match ChallengeYaml
+	for _, challengeConf := range challengesConf {
+		if strings.HasPrefix(path, challengeConf.Cwd+string(os.PathSeparator)) && len(challengeConf.Cwd) > len(match.Cwd) {
+			match = challengeConf
+		}
+	}
+	return match, match.Cwd != ""
+}
+
+// classifyChange maps a file path relative to the challenge directory to the
+// update it requires, using the update rules of challenge.yml, then those of
+// conf.yaml, then the provided files and the default layout
+func classifyChange(config *Config, challengeConf ChallengeYaml, file string) UpdateType {
+	if challengeFileRegex.MatchString(file) && !strings.Contains(file, "/") {
+		return UpdateMetadata
+	}
+	if update, ok := matchUpdateRules(challengeConf.UpdateRules, file); ok {
+		return update
+	}
+	if update, ok := matchUpdateRules(config.UpdateRules, file); ok {
+		return update
+	}
+	if challengeConf.Provide != nil && !strings.HasPrefix(*challengeConf.Provide, "http") {
+		provide := strings.TrimPrefix(filepath.ToSlash(filepath.Clean(*challengeConf.Provide)), "./")
+		if file == provide || strings.HasPrefix(file, provide+"/") {
+			return UpdateAttachment
+		}
+	}
+	update, _ := matchUpdateRules(defaultUpdateRules, file)
+	return update
+}
+
+// changedChallengeFields compares the raw challenge.yml at ref with the one
+// at to, or in the working tree when to is empty
+func changedChallengeFields(ref, to, file, path string) (fields []string, added bool) {
+	oldContent, err := runGit("show", ref+":"+file)
+	if err != nil {
+		return nil, true
+	}
+	var newContent []byte
+	if to != "" {
+		content, err := runGit("show", to+":"+file)
+		if err != nil {
+			return nil, false
+		}
+		newContent = []byte(content)
+	} else if newContent, err = os.ReadFile(path); err != nil {
+		return nil, false
+	}
+
+	var before, after ChallengeYaml
+	if ParseYamlFromBytes([]byte(oldContent), &before) != nil || ParseYamlFromBytes(newContent, &after) != nil {
+		return nil, false
+	}
+
+	beforeValue := reflect.ValueOf(before)
+	afterValue := reflect.ValueOf(after)
+	for i := 0; i < beforeValue.NumField(); i++ {
+		field := beforeValue.Type().Field(i)
+		tag := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if tag == "-" {
+			continue
+		}
+		if !reflect.DeepEqual(beforeValue.Field(i).Interface(), afterValue.Field(i).Interface()) {
+			fields = append(fields, tag)
+		}
+	}
+	return fields, false
+}
+
+func removedChallengeDiff(ref, root, file string) (*ChallengeDiff, error) {
+	content, err := runGit("show", ref+":"+file)
+	if err != nil {
+		return nil, err
+	}
+
+	var challengeConf ChallengeYaml
+	if err := ParseYamlFromBytes([]byte(content), &challengeConf); err != nil {
+		return nil, err
+	}
+
+	dir, _ := filepath.Rel(getWorkDir(), filepath.Dir(filepath.Join(root, filepath.FromSlash(file))))
+	dir = filepath.ToSlash(dir)
+	return &ChallengeDiff{
+		Name:       challengeConf.Name,
+		Category:   strings.Split(dir, "/")[0],
+		Path:       dir,
+		Status:     DiffRemoved,
+		UpdateType: UpdateFullRedeploy,
+		Files:      []string{filepath.Base(file)},
+	}, nil
+}
@@ -0,0 +1,36 @@
+package gzcli
+
+import (
+	"fmt"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// Doctor checks that conf.yaml parses and that the GZCTF instance is
+// reachable with the configured TLS settings and credentials. Unlike Init it
+// never falls back to registering the admin account
+func Doctor() error {
+	config, err := GetConfig(&gzapi.GZAPI{})
+	if err != nil {
+		return fmt.Errorf("config error: %w", err)
+	}
+	log.InfoH2("%s parsed", CONFIG_FILE)
+
+	if config.TLS != nil {
+		log.InfoH2("Using custom TLS settings (ca: %q, cert: %q)", config.TLS.CAFile, config.TLS.CertFile)
+	}
+
+	api, err := gzapi.Init(config.Url, &config.Creds, config.TLS)
+	if err != nil {
+		return fmt.Errorf("cannot log in to %s: %w", config.Url, err)
+	}
+	log.InfoH2("Logged in to %s as %s", config.Url, config.Creds.Username)
+
+	if _, err := api.GetGameByTitle(config.Event.Title); err != nil {
+		log.ErrorH2("Game %s not found, it will be created on the next sync", config.Event.Title)
+	} else {
+		log.InfoH2("Game %s found", config.Event.Title)
+	}
+	return nil
+}
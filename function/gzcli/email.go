@@ -0,0 +1,251 @@
+package gzcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/log"
+	"gopkg.in/gomail.v2"
+)
+
+const (
+	emailTemplatesDir       = "email-templates"
+	credentialsTemplateFile = "credentials.html"
+	sendgridEndpoint        = "https://api.sendgrid.com/v3/mail/send"
+)
+
+// Email providers
+const (
+	EmailProviderSMTP     = "smtp"
+	EmailProviderSendGrid = "sendgrid"
+	EmailProviderFile     = "file"
+)
+
+// EmailConfig selects how team credentials are mailed. SMTP uses the
+// EmailConfig of appsettings.json, SendGrid its v3 API and file writes every
+// message into Dir without sending it
+type EmailConfig struct {
+	Provider       string `yaml:"provider,omitempty"`
+	From           string `yaml:"from,omitempty"`
+	Subject        string `yaml:"subject,omitempty"`
+	Template       string `yaml:"template,omitempty"`
+	SendGridApiKey string `yaml:"sendgridApiKey,omitempty"`
+	Dir            string `yaml:"dir,omitempty"`
+}
+
+// CredentialsEmail is the data available to the credentials email template
+type CredentialsEmail struct {
+	RealName    string
+	Username    string
+	Password    string
+	Email       string
+	TeamName    string
+	Division    string
+	Institution string
+	Website     string
+}
+
+type emailMessage struct {
+	From    string
+	To      string
+	Subject string
+	HTML    string
+}
+
+// emailSender delivers a message and describes where it went
+type emailSender interface {
+	send(message emailMessage) (string, error)
+}
+
+var emailTemplate string
+
+// SetEmailTemplate makes credential emails use the template at path instead
+// of the one of conf.yaml or .gzctf/email-templates
+func SetEmailTemplate(path string) {
+	emailTemplate = path
+}
+
+func (c *EmailConfig) provider() string {
+	if c == nil || c.Provider == "" {
+		return EmailProviderSMTP
+	}
+	return c.Provider
+}
+
+// delivers reports whether the provider really sends mail, as opposed to
+// writing it to disk
+func (c *EmailConfig) delivers() bool {
+	return c.provider() != EmailProviderFile
+}
+
+// credentialsTemplate loads the credentials template from --email-template,
+// email.template of conf.yaml, .gzctf/email-templates/credentials.html or the
+// built in one, in that order
+func credentialsTemplate(config *Config) (*template.Template, error) {
+	path := emailTemplate
+	if path == "" && config.Email != nil {
+		path = config.Email.Template
+	}
+	if path == "" {
+		candidate := filepath.Join(getWorkDir(), GZCTF_DIR, emailTemplatesDir, credentialsTemplateFile)
+		if _, err := os.Stat(candidate); err == nil {
+			path = candidate
+		}
+	}
+	if path == "" {
+		return template.ParseFS(embedTemplate, "embeds/email/"+credentialsTemplateFile)
+	}
+	return template.ParseFiles(path)
+}
+
+func newEmailSender(config *Config) (emailSender, error) {
+	switch provider := config.Email.provider(); provider {
+	case EmailProviderSMTP:
+		smtp, err := getSmtpSettings()
+		if err != nil {
+			return nil, err
+		}
+		return smtpSender{smtp}, nil
+	case EmailProviderSendGrid:
+		if config.Email.SendGridApiKey == "" {
+			return nil, fmt.Errorf("email.sendgridApiKey is required for the sendgrid provider")
+		}
+		return sendgridSender{config.Email.SendGridApiKey}, nil
+	case EmailProviderFile:
+		dir := config.Email.Dir
+		if dir == "" {
+			dir = filepath.Join(cacheDir, "emails")
+		}
+		return fileSender{dir}, nil
+	default:
+		return nil, fmt.Errorf("unknown email provider %q, expected smtp, sendgrid or file", provider)
+	}
+}
+
+// sendEmail renders the credentials template for creds and hands it to the
+// provider of conf.yaml. It returns where the message went, for the email log
+func sendEmail(config *Config, realName string, creds *TeamCreds) (string, error) {
+	tmpl, err := credentialsTemplate(config)
+	if err != nil {
+		return "", fmt.Errorf("email template: %w", err)
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, CredentialsEmail{
+		RealName:    realName,
+		Username:    creds.Username,
+		Password:    creds.Password,
+		Email:       creds.Email,
+		TeamName:    creds.TeamName,
+		Division:    creds.Division,
+		Institution: creds.Institution,
+		Website:     config.Url,
+	}); err != nil {
+		return "", fmt.Errorf("email template: %w", err)
+	}
+
+	sender, err := newEmailSender(config)
+	if err != nil {
+		return "", err
+	}
+	message := emailMessage{To: creds.Email, Subject: log.Msg("email.subject"), HTML: body.String()}
+	if config.Email != nil {
+		message.From = config.Email.From
+		if config.Email.Subject != "" {
+			message.Subject = config.Email.Subject
+		}
+	}
+	return sender.send(message)
+}
+
+type smtpSender struct {
+	smtp *smtpSettings
+}
+
+func (s smtpSender) send(message emailMessage) (string, error) {
+	from := message.From
+	if from == "" {
+		from = s.smtp.Username
+	}
+	m := gomail.NewMessage()
+	m.SetHeader("From", from)
+	m.SetHeader("To", message.To)
+	m.SetHeader("Subject", message.Subject)
+	m.SetBody("text/html", message.HTML)
+
+	d := gomail.NewDialer(s.smtp.Host, s.smtp.Port, s.smtp.Username, s.smtp.Password)
+	if err := d.DialAndSend(m); err != nil {
+		return "", fmt.Errorf("failed to send email: %v", err)
+	}
+	return "accepted by SMTP server", nil
+}
+
+type sendgridSender struct {
+	apiKey string
+}
+
+func (s sendgridSender) send(message emailMessage) (string, error) {
+	if message.From == "" {
+		return "", fmt.Errorf("email.from is required for the sendgrid provider")
+	}
+	type address struct {
+		Email string `json:"email"`
+	}
+	type content struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+	payload, err := json.Marshal(map[string]any{
+		"personalizations": []map[string]any{{"to": []address{{message.To}}}},
+		"from":             address{message.From},
+		"subject":          message.Subject,
+		"content":          []content{{"text/html", message.HTML}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sendgridEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send email: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("failed to send email: SendGrid returned %s", resp.Status)
+	}
+	return "accepted by SendGrid", nil
+}
+
+type fileSender struct {
+	dir string
+}
+
+func (s fileSender) send(message emailMessage) (string, error) {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return "", err
+	}
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '_'
+		}
+		return r
+	}, message.To)
+	path := filepath.Join(s.dir, name+".eml")
+	eml := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s",
+		message.From, message.To, message.Subject, message.HTML)
+	if err := os.WriteFile(path, []byte(eml), 0600); err != nil {
+		return "", err
+	}
+	return "written to " + path, nil
+}
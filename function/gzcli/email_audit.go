@@ -0,0 +1,67 @@
+package gzcli
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const emailLogFile = "emails.jsonl"
+
+// EmailRecord is one attempt at sending team credentials by email
+type EmailRecord struct {
+	Time     time.Time `json:"time"`
+	Email    string    `json:"email"`
+	Username string    `json:"username"`
+	TeamName string    `json:"teamName"`
+	Sent     bool      `json:"sent"`
+	Response string    `json:"response"`
+}
+
+// recordEmail appends a credential email attempt to the email log, response
+// is where the provider put the message and err the error if it was rejected
+func recordEmail(creds *TeamCreds, response string, err error) error {
+	record := EmailRecord{
+		Time:     time.Now(),
+		Email:    creds.Email,
+		Username: creds.Username,
+		TeamName: creds.TeamName,
+		Sent:     err == nil,
+		Response: response,
+	}
+	if err != nil {
+		record.Response = err.Error()
+	}
+
+	return appendJSONL(filepath.Join(cacheDir, emailLogFile), record)
+}
+
+// ReadEmailLog returns every recorded credential email, oldest first. When
+// email is set only attempts to that address are returned
+func ReadEmailLog(email string) ([]EmailRecord, error) {
+	f, err := os.Open(filepath.Join(cacheDir, emailLogFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []EmailRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record EmailRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if email != "" && !strings.EqualFold(record.Email, email) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,100 @@
+package gzcli
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is published to the subscribers of GZ.Subscribe while gzcli works,
+// so programs embedding ctfify can render progress without parsing logs.
+// It is one of SyncStarted, ChallengeSynced, DeployFailed, GitPulled or
+// SyncFinished
+type Event interface {
+	When() time.Time
+}
+
+// SyncStarted is published before the challenges of a sync are processed
+type SyncStarted struct {
+	Time       time.Time
+	Game       string
+	Challenges int
+}
+
+// ChallengeSynced is published after a challenge is synced to the platform
+type ChallengeSynced struct {
+	Time      time.Time
+	Challenge string
+	Done      int
+	Total     int
+}
+
+// DeployFailed is published when syncing or restarting a challenge fails
+type DeployFailed struct {
+	Time      time.Time
+	Challenge string
+	Err       error
+}
+
+// GitPulled is published after the repository is pulled
+type GitPulled struct {
+	Time     time.Time
+	Revision string
+	Output   string
+}
+
+// SyncFinished is published after every challenge of a sync is processed
+type SyncFinished struct {
+	Time   time.Time
+	Failed int
+	Total  int
+}
+
+func (e SyncStarted) When() time.Time     { return e.Time }
+func (e ChallengeSynced) When() time.Time { return e.Time }
+func (e DeployFailed) When() time.Time    { return e.Time }
+func (e GitPulled) When() time.Time       { return e.Time }
+func (e SyncFinished) When() time.Time    { return e.Time }
+
+// eventBus fans events out to subscribers. Its zero value has no subscribers
+type eventBus struct {
+	mu          sync.Mutex
+	subscribers map[chan Event]struct{}
+}
+
+// Subscribe returns a channel receiving the events of gz and a function that
+// unsubscribes and closes it. Events are dropped for a subscriber whose
+// buffer is full, so a slow UI never stalls a sync
+func (gz *GZ) Subscribe(buffer int) (<-chan Event, func()) {
+	ch := make(chan Event, buffer)
+	gz.events.mu.Lock()
+	if gz.events.subscribers == nil {
+		gz.events.subscribers = map[chan Event]struct{}{}
+	}
+	gz.events.subscribers[ch] = struct{}{}
+	gz.events.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			gz.events.mu.Lock()
+			delete(gz.events.subscribers, ch)
+			gz.events.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// publish sends event to every subscriber. A nil bus publishes nothing
+func (b *eventBus) publish(event Event) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
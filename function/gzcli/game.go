@@ -0,0 +1,78 @@
+package gzcli
+
+import (
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// GameWriteupForm holds the writeup fields to change, nil fields are left untouched
+type GameWriteupForm struct {
+	Deadline *time.Time
+	Note     *string
+}
+
+func (gz *GZ) currentGame() (*gzapi.Game, error) {
+	config, err := lookupConfig(gz.api)
+	if err != nil {
+		return nil, err
+	}
+	return gz.api.GetGameById(config.Event.Id)
+}
+
+// SetGameWriteup updates only the writeup deadline and note of the current game
+func (gz *GZ) SetGameWriteup(form GameWriteupForm) error {
+	game, err := gz.currentGame()
+	if err != nil {
+		return err
+	}
+
+	if form.Deadline != nil {
+		log.Info("Set writeup deadline of %s to %s", game.Title, form.Deadline.Format(time.RFC3339))
+		game.WriteupDeadline = gzapi.CustomTime{Time: *form.Deadline}
+	}
+	if form.Note != nil {
+		log.Info("Set writeup note of %s", game.Title)
+		game.WriteupNote = *form.Note
+	}
+
+	return game.Update(game)
+}
+
+// SetGamePoster makes file the poster of the current game, skipping the
+// upload when it already is. A different event.poster in conf.yaml is put
+// back by the next sync
+func (gz *GZ) SetGamePoster(file string) (string, error) {
+	config, err := lookupConfig(gz.api)
+	if err != nil {
+		return "", err
+	}
+	game, err := gz.api.GetGameById(config.Event.Id)
+	if err != nil {
+		return "", err
+	}
+
+	poster, err := createPosterIfNotExistOrDifferent(config, file, game, gz.api)
+	if err != nil {
+		return "", err
+	}
+	if poster == game.Poster {
+		log.Info("%s is already the poster of %s", file, game.Title)
+		return poster, nil
+	}
+	log.Info("Set poster of %s to %s", game.Title, file)
+	game.Poster = poster
+	if err := game.Update(game); err != nil {
+		return "", err
+	}
+
+	if config.Event.Poster != "" {
+		if hash, err := GetFileHashHex(config.Event.Poster); err == nil {
+			if local, err := GetFileHashHex(file); err == nil && hash != local {
+				log.ErrorH2("conf.yaml sets event.poster to %s, the next sync restores it", config.Event.Poster)
+			}
+		}
+	}
+	return poster, recordOperation("game.poster", map[string]string{"file": file, "poster": poster})
+}
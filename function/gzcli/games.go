@@ -0,0 +1,88 @@
+package gzcli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+)
+
+// GameEvent is an extra game driven from the same repository, selected with
+// --game-title. Root is the directory holding its category directories and
+// Categories limits which of them belong to the game
+type GameEvent struct {
+	gzapi.Game `yaml:",inline"`
+	Root       string   `yaml:"root,omitempty"`
+	Categories []string `yaml:"categories,omitempty"`
+}
+
+var gameTitle string
+
+// SetGameTitle selects the game of conf.yaml that gzcli works on, either the
+// event or one of the events. It must run before Init
+func SetGameTitle(title string) {
+	gameTitle = title
+}
+
+func gameSlug(title string) string {
+	return slugRegex.ReplaceAllString(strings.ReplaceAll(strings.ToLower(title), " ", "_"), "")
+}
+
+// selectEvent replaces the event of config with the game picked by
+// SetGameTitle. Each extra game keeps its cache under games/<slug>/
+func selectEvent(config *Config) error {
+	if gameTitle == "" || gameTitle == config.Event.Title {
+		return nil
+	}
+	for _, event := range config.Events {
+		if event.Title != gameTitle {
+			continue
+		}
+		config.Event = event.Game
+		config.challengeRoot = event.Root
+		config.categories = event.Categories
+		config.cachePrefix = "games/" + gameSlug(event.Title) + "/"
+		return nil
+	}
+
+	titles := []string{config.Event.Title}
+	for _, event := range config.Events {
+		titles = append(titles, event.Title)
+	}
+	return fmt.Errorf("game %q is not in %s, expected one of: %s", gameTitle, CONFIG_FILE, strings.Join(titles, ", "))
+}
+
+// configCacheKey is where the id and public key of the selected game are kept
+func (config *Config) configCacheKey() string {
+	return config.cachePrefix + "config"
+}
+
+// challengeCategories returns the categories of the selected game
+func (config *Config) challengeCategories() []string {
+	if len(config.categories) == 0 {
+		return CHALLENGE_CATEGORY
+	}
+	var categories []string
+	for _, category := range CHALLENGE_CATEGORY {
+		if containsCategory(config.categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	return categories
+}
+
+// challengeRootDir returns the directory holding the category directories
+// of the selected game
+func (config *Config) challengeRootDir(dir string) string {
+	return filepath.Join(dir, config.challengeRoot)
+}
+
+func containsCategory(categories []string, category string) bool {
+	for _, c := range categories {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,12 +1,5 @@
 package gzapi
 
-func (cs *GZAPI) Login() error {
-	if err := cs.post("/api/account/login", cs.Creds, nil); err != nil {
-		return err
-	}
-	return nil
-}
-
 type RegisterForm struct {
 	Email    string `json:"email"`
 	Username string `json:"username"`
@@ -1,5 +1,7 @@
 package gzapi
 
+import "fmt"
+
 type FileInfo struct {
 	Hash string `json:"hash"`
 	Name string `json:"name"`
@@ -22,3 +24,7 @@ func (cs *GZAPI) GetAssets() ([]FileInfo, error) {
 	}
 	return data.Data, nil
 }
+
+func (cs *GZAPI) DeleteAsset(hash string) error {
+	return cs.delete(fmt.Sprintf("/api/assets/%s", hash), nil)
+}
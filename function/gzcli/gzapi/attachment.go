@@ -31,5 +31,13 @@ func (c *Challenge) CreateAttachment(attachment CreateAttachmentForm) error {
 		Type: attachment.AttachmentType,
 		Url:  attachment.FileHash,
 	}
+	if attachment.AttachmentType == "Remote" {
+		c.Attachment.Url = attachment.RemoteUrl
+	}
 	return nil
 }
+
+// Download saves the attachment file served by the platform into dst
+func (a *Attachment) Download(dst string) error {
+	return a.CS.download(a.Url, dst)
+}
@@ -0,0 +1,92 @@
+package gzapi
+
+import (
+	"sync"
+	"time"
+)
+
+type cachedResponse struct {
+	body []byte
+	at   time.Time
+}
+
+// inflightGet is a GET whose response other callers of the same url wait for
+type inflightGet struct {
+	done chan struct{}
+	body []byte
+	err  error
+}
+
+// responseCache holds GET response bodies keyed by url and coalesces
+// concurrent GETs of the same url into one request. Any write through the
+// client clears it and bumps its generation, so a GET that was in flight
+// during the write is neither stored nor joined by later reads
+type responseCache struct {
+	mu         sync.Mutex
+	entries    map[string]cachedResponse
+	inflight   map[string]*inflightGet
+	generation uint64
+}
+
+// do returns the body of url from the cache when it is younger than ttl,
+// else from the GET of url already in flight, else from fetch
+func (c *responseCache) do(url string, ttl time.Duration, fetch func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[url]; ok && time.Since(entry.at) <= ttl {
+		c.mu.Unlock()
+		return entry.body, nil
+	}
+	if call, ok := c.inflight[url]; ok {
+		c.mu.Unlock()
+		<-call.done
+		return call.body, call.err
+	}
+	call := &inflightGet{done: make(chan struct{})}
+	if c.inflight == nil {
+		c.inflight = map[string]*inflightGet{}
+	}
+	c.inflight[url] = call
+	generation := c.generation
+	c.mu.Unlock()
+
+	call.body, call.err = fetch()
+
+	c.mu.Lock()
+	if call.err == nil && c.generation == generation {
+		if c.entries == nil {
+			c.entries = map[string]cachedResponse{}
+		}
+		c.entries[url] = cachedResponse{body: call.body, at: time.Now()}
+	}
+	if c.inflight[url] == call {
+		delete(c.inflight, url)
+	}
+	c.mu.Unlock()
+	close(call.done)
+	return call.body, call.err
+}
+
+func (c *responseCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+	c.inflight = nil
+	c.generation++
+}
+
+// Cached returns a view of cs whose GET requests are served from an
+// in-process cache for up to ttl, with concurrent identical GETs sharing one
+// request. The cache is shared by every view of cs and cleared by any write
+// made through them. It pays off where many goroutines read the same
+// endpoint, like the asset listing of concurrent challenge syncs
+func (cs *GZAPI) Cached(ttl time.Duration) *GZAPI {
+	if cs.cache == nil {
+		cs.cache = &responseCache{}
+	}
+	view := *cs
+	view.cacheTTL = ttl
+	return &view
+}
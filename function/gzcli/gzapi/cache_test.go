@@ -0,0 +1,67 @@
+package gzapi
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestResponseCacheCoalescesGets(t *testing.T) {
+	var cache responseCache
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func() ([]byte, error) {
+		fetches.Add(1)
+		<-release
+		return []byte("body"), nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if body, err := cache.do("/api/assets", time.Minute, fetch); err != nil || string(body) != "body" {
+				t.Errorf("do = %q, %v", body, err)
+			}
+		}()
+	}
+	// Let every goroutine join the first GET before it returns
+	for {
+		cache.mu.Lock()
+		started := cache.inflight["/api/assets"] != nil
+		cache.mu.Unlock()
+		if started {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := fetches.Load(); n != 1 {
+		t.Fatalf("fetched %d times, want 1", n)
+	}
+}
+
+func TestResponseCacheDropsGetsInFlightDuringWrite(t *testing.T) {
+	var cache responseCache
+	fetch := func(body string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			if body == "before" {
+				// A write lands while this GET is in flight
+				cache.clear()
+			}
+			return []byte(body), nil
+		}
+	}
+
+	if body, _ := cache.do("/api/assets", time.Minute, fetch("before")); string(body) != "before" {
+		t.Fatalf("first GET = %q", body)
+	}
+	if body, _ := cache.do("/api/assets", time.Minute, fetch("after")); string(body) != "after" {
+		t.Fatalf("GET after the write = %q, want the response fetched after it", body)
+	}
+}
@@ -1,35 +1,37 @@
 package gzapi
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"sync"
 )
 
 type Challenge struct {
-	Id                   int         `json:"id" yaml:"id"`
-	Title                string      `json:"title" yaml:"title"`
-	Content              string      `json:"content" yaml:"content"`
-	Category             string      `json:"category" yaml:"category"`
-	Type                 string      `json:"type" yaml:"type"`
-	Hints                []string    `json:"hints" yaml:"hints"`
-	FlagTemplate         string      `json:"flagTemplate" yaml:"flagTemplate"`
-	IsEnabled            *bool       `json:"isEnabled,omitempty" yaml:"isEnabled,omitempty"`
-	AcceptedCount        int         `json:"acceptedCount" yaml:"acceptedCount"`
-	FileName             string      `json:"fileName" yaml:"fileName"`
-	Attachment           *Attachment `json:"attachment" yaml:"attachment"`
-	TestContainer        string      `json:"testContainer" yaml:"testContainer"`
-	Flags                []Flag      `json:"flags" yaml:"flags"`
-	ContainerImage       string      `json:"containerImage" yaml:"containerImage"`
-	MemoryLimit          int         `json:"memoryLimit" yaml:"memoryLimit"`
-	CpuCount             int         `json:"cpuCount" yaml:"cpuCount"`
-	StorageLimit         int         `json:"storageLimit" yaml:"storageLimit"`
-	ContainerExposePort  int         `json:"containerExposePort" yaml:"containerExposePort"`
-	EnableTrafficCapture bool        `json:"enableTrafficCapture" yaml:"enableTrafficCapture"`
-	OriginalScore        int         `json:"originalScore" yaml:"originalScore"`
-	MinScoreRate         float64     `json:"minScoreRate" yaml:"minScoreRate"`
-	Difficulty           float64     `json:"difficulty" yaml:"difficulty"`
-	GameId               int         `json:"-" yaml:"gameId"`
-	CS                   *GZAPI      `json:"-" yaml:"-"`
+	Id                   int            `json:"id" yaml:"id"`
+	Title                string         `json:"title" yaml:"title"`
+	Content              string         `json:"content" yaml:"content"`
+	Category             string         `json:"category" yaml:"category"`
+	Type                 string         `json:"type" yaml:"type"`
+	Hints                []string       `json:"hints" yaml:"hints"`
+	FlagTemplate         string         `json:"flagTemplate" yaml:"flagTemplate"`
+	IsEnabled            *bool          `json:"isEnabled,omitempty" yaml:"isEnabled,omitempty"`
+	AcceptedCount        int            `json:"acceptedCount" yaml:"acceptedCount"`
+	FileName             string         `json:"fileName" yaml:"fileName"`
+	Attachment           *Attachment    `json:"attachment" yaml:"attachment"`
+	TestContainer        *ContainerInfo `json:"testContainer,omitempty" yaml:"testContainer,omitempty"`
+	Flags                []Flag         `json:"flags" yaml:"flags"`
+	ContainerImage       string         `json:"containerImage" yaml:"containerImage"`
+	MemoryLimit          int            `json:"memoryLimit" yaml:"memoryLimit"`
+	CpuCount             int            `json:"cpuCount" yaml:"cpuCount"`
+	StorageLimit         int            `json:"storageLimit" yaml:"storageLimit"`
+	ContainerExposePort  int            `json:"containerExposePort" yaml:"containerExposePort"`
+	EnableTrafficCapture bool           `json:"enableTrafficCapture" yaml:"enableTrafficCapture"`
+	OriginalScore        int            `json:"originalScore" yaml:"originalScore"`
+	MinScoreRate         float64        `json:"minScoreRate" yaml:"minScoreRate"`
+	Difficulty           float64        `json:"difficulty" yaml:"difficulty"`
+	GameId               int            `json:"-" yaml:"gameId"`
+	CS                   *GZAPI         `json:"-" yaml:"-"`
 }
 
 func (c *Challenge) Delete() error {
@@ -43,6 +45,52 @@ func (c *Challenge) Update(challenge Challenge) (*Challenge, error) {
 	return &challenge, nil
 }
 
+// challengeUpdateFields are the challenge fields the platform accepts in an
+// update. Fields missing from an update are left as they are
+var challengeUpdateFields = []string{
+	"title", "content", "category", "hints", "flagTemplate", "isEnabled",
+	"fileName", "containerImage", "memoryLimit", "cpuCount", "storageLimit",
+	"containerExposePort", "enableTrafficCapture", "originalScore",
+	"minScoreRate", "difficulty",
+}
+
+// ChangedFields returns the updatable fields of updated that differ from
+// before, keyed by their JSON name
+func ChangedFields(before, updated Challenge) (map[string]json.RawMessage, error) {
+	var old, current map[string]json.RawMessage
+	if err := remarshal(before, &old); err != nil {
+		return nil, err
+	}
+	if err := remarshal(updated, &current); err != nil {
+		return nil, err
+	}
+	fields := map[string]json.RawMessage{}
+	for _, name := range challengeUpdateFields {
+		value, ok := current[name]
+		if ok && !bytes.Equal(old[name], value) {
+			fields[name] = value
+		}
+	}
+	return fields, nil
+}
+
+func remarshal(v any, out *map[string]json.RawMessage) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+// UpdateFields sends only the given fields, so edits made to other fields
+// in the web UI since the last sync are kept
+func (c *Challenge) UpdateFields(fields map[string]json.RawMessage) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return c.CS.put(fmt.Sprintf("/api/edit/games/%d/challenges/%d", c.GameId, c.Id), fields, nil)
+}
+
 func (c *Challenge) Refresh() (*Challenge, error) {
 	var data Challenge
 	if err := c.CS.get(fmt.Sprintf("/api/edit/games/%d/challenges/%d", c.GameId, c.Id), &data); err != nil {
@@ -89,6 +137,9 @@ func (g *Game) GetChallenges() ([]Challenge, error) {
 			}
 			c.GameId = g.Id
 			c.CS = g.CS
+			if c.Attachment != nil {
+				c.Attachment.CS = g.CS
+			}
 
 			mu.Lock()
 			data = append(data, c)
@@ -0,0 +1,81 @@
+package gzapi
+
+import "encoding/json"
+
+// adminConfigPath serves every section of the admin config
+const adminConfigPath = "/api/admin/config"
+
+// AccountPolicy is the registration and login policy of the platform
+type AccountPolicy struct {
+	AllowRegister             bool   `json:"allowRegister"`
+	ActiveOnRegister          bool   `json:"activeOnRegister"`
+	UseCaptcha                bool   `json:"useCaptcha"`
+	EmailConfirmationRequired bool   `json:"emailConfirmationRequired"`
+	EmailDomainList           string `json:"emailDomainList"`
+}
+
+// ContainerPolicy is the platform wide container lifetime policy, GZCTF does
+// not support per challenge lifetimes. Durations are in minutes
+type ContainerPolicy struct {
+	DefaultLifetime           int  `json:"defaultLifetime" yaml:"defaultLifetime"`
+	ExtensionDuration         int  `json:"extensionDuration" yaml:"extensionDuration"`
+	RenewalWindow             int  `json:"renewalWindow" yaml:"renewalWindow"`
+	AutoDestroyOnLimitReached bool `json:"autoDestroyOnLimitReached" yaml:"autoDestroyOnLimitReached"`
+}
+
+// GlobalConfig is the site wide config of the platform
+type GlobalConfig struct {
+	Title      string `json:"title"`
+	Slogan     string `json:"slogan"`
+	FooterInfo string `json:"footerInfo"`
+}
+
+// AdminConfig is the global config of the platform managed by admins
+type AdminConfig struct {
+	AccountPolicy   AccountPolicy   `json:"accountPolicy"`
+	GlobalConfig    GlobalConfig    `json:"globalConfig"`
+	ContainerPolicy ContainerPolicy `json:"containerPolicy"`
+}
+
+// Admin config sections accepted by PatchAdminConfig
+const (
+	ConfigAccountPolicy   = "accountPolicy"
+	ConfigGlobalConfig    = "globalConfig"
+	ConfigContainerPolicy = "containerPolicy"
+)
+
+func (cs *GZAPI) GetAdminConfig() (*AdminConfig, error) {
+	var data AdminConfig
+	if err := cs.get(adminConfigPath, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// PatchAdminConfig changes the keys of one config section that patch, a map
+// or a section struct such as ContainerPolicy, sets. The platform replaces a
+// section as a whole, so the current section is read first and every key the
+// patch does not touch is sent back unchanged
+func (cs *GZAPI) PatchAdminConfig(section string, patch any) error {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var current map[string]map[string]any
+	if err := cs.get(adminConfigPath, &current); err != nil {
+		return err
+	}
+	merged := current[section]
+	if merged == nil {
+		merged = map[string]any{}
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return cs.put(adminConfigPath, map[string]any{section: merged}, nil)
+}
@@ -0,0 +1,80 @@
+package gzapi
+
+import "fmt"
+
+type InstanceChallenge struct {
+	Id       int    `json:"id"`
+	Title    string `json:"title"`
+	Category string `json:"category"`
+}
+
+type InstanceTeam struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// ContainerInstance is a running challenge container
+type ContainerInstance struct {
+	Team          InstanceTeam      `json:"team"`
+	Challenge     InstanceChallenge `json:"challenge"`
+	Image         string            `json:"image"`
+	ContainerGuid string            `json:"containerGuid"`
+	ContainerId   string            `json:"containerId"`
+	StartedAt     CustomTime        `json:"startedAt"`
+	ExpectStopAt  CustomTime        `json:"expectStopAt"`
+	Ip            string            `json:"ip"`
+	Port          int               `json:"port"`
+}
+
+// ContainerInfo is the test container of a challenge. Entry is host:port, or
+// the container guid when the platform proxies containers
+type ContainerInfo struct {
+	Status       string     `json:"status" yaml:"status"`
+	StartedAt    CustomTime `json:"startedAt" yaml:"startedAt"`
+	ExpectStopAt CustomTime `json:"expectStopAt" yaml:"expectStopAt"`
+	Entry        string     `json:"entry" yaml:"entry"`
+}
+
+// CreateTestContainer starts the test container of a container challenge
+func (c *Challenge) CreateTestContainer() (*ContainerInfo, error) {
+	var data ContainerInfo
+	if err := c.CS.post(fmt.Sprintf("/api/edit/games/%d/challenges/%d/container", c.GameId, c.Id), nil, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// DestroyTestContainer stops the test container of a challenge
+func (c *Challenge) DestroyTestContainer() error {
+	return c.CS.delete(fmt.Sprintf("/api/edit/games/%d/challenges/%d/container", c.GameId, c.Id), nil)
+}
+
+// Port mapping types of the platform ContainerProvider
+const (
+	PortMappingDefault       = "Default"
+	PortMappingPlatformProxy = "PlatformProxy"
+)
+
+// PlatformConfig is the public client config of the platform
+type PlatformConfig struct {
+	Title       string `json:"title"`
+	PortMapping string `json:"portMapping"`
+}
+
+func (cs *GZAPI) GetPlatformConfig() (*PlatformConfig, error) {
+	var data PlatformConfig
+	if err := cs.get("/api/config", &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (cs *GZAPI) GetContainerInstances() ([]ContainerInstance, error) {
+	var data struct {
+		Data []ContainerInstance `json:"data"`
+	}
+	if err := cs.get("/api/admin/instances", &data); err != nil {
+		return nil, err
+	}
+	return data.Data, nil
+}
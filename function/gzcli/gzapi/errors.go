@@ -0,0 +1,59 @@
+package gzapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/imroc/req/v3"
+)
+
+// APIError is a non 200 response of the platform. Callers branch on it with
+// errors.As or the Is* helpers instead of matching the error text
+type APIError struct {
+	StatusCode int
+	Message    string
+	Method     string
+	Endpoint   string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: request end with %d status, %s", e.Method, e.Endpoint, e.StatusCode, e.Message)
+}
+
+// newAPIError builds the APIError of resp, using the title of the GZCTF
+// RequestResponse body as message when there is one
+func newAPIError(resp *req.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    resp.String(),
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			apiErr.Endpoint = resp.Request.URL.Path
+		}
+	}
+	var body struct {
+		Title string `json:"title"`
+	}
+	if json.Unmarshal(resp.Bytes(), &body) == nil && body.Title != "" {
+		apiErr.Message = body.Title
+	}
+	return apiErr
+}
+
+// IsStatus reports whether err is an APIError with the given status code
+func IsStatus(err error, code int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == code
+}
+
+func IsNotFound(err error) bool {
+	return IsStatus(err, http.StatusNotFound)
+}
+
+func IsConflict(err error) bool {
+	return IsStatus(err, http.StatusConflict)
+}
@@ -0,0 +1,149 @@
+package gzapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// SignalR json protocol framing, see https://github.com/dotnet/aspnetcore/blob/main/src/SignalR/docs/specs/HubProtocol.md
+const (
+	signalRRecordSeparator = 0x1e
+	signalRInvocation      = 1
+	signalRPing            = 6
+	signalRClose           = 7
+)
+
+// Hub targets pushed by the GZCTF monitor hub
+const (
+	EventGameEvent  = "ReceivedGameEvent"
+	EventSubmission = "ReceivedSubmissions"
+	EventGameNotice = "ReceivedGameNotice"
+)
+
+type HubMessage struct {
+	Type      int               `json:"type"`
+	Target    string            `json:"target"`
+	Arguments []json.RawMessage `json:"arguments"`
+	Error     string            `json:"error"`
+}
+
+type GameEvent struct {
+	Type   string     `json:"type"`
+	Values []string   `json:"values"`
+	Time   CustomTime `json:"time"`
+	User   string     `json:"user"`
+	Team   string     `json:"team"`
+}
+
+type Submission struct {
+	Answer    string     `json:"answer"`
+	Status    string     `json:"status"`
+	Time      CustomTime `json:"time"`
+	User      string     `json:"user"`
+	Team      string     `json:"team"`
+	Challenge string     `json:"challenge"`
+}
+
+type negotiateResponse struct {
+	ConnectionToken string `json:"connectionToken"`
+	ConnectionId    string `json:"connectionId"`
+}
+
+// Subscribe streams monitor hub messages of the game to handler until ctx is
+// cancelled, reconnecting with backoff when the connection drops
+func (g *Game) Subscribe(ctx context.Context, handler func(HubMessage)) error {
+	backoff := time.Second
+	for {
+		connected, err := g.subscribeOnce(ctx, handler)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			backoff = time.Second
+		}
+		if err != nil && !connected && backoff >= 30*time.Second {
+			return fmt.Errorf("event subscription failed: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+func (g *Game) subscribeOnce(ctx context.Context, handler func(HubMessage)) (bool, error) {
+	hub := "/hub/monitor"
+	query := fmt.Sprintf("game=%d", g.Id)
+
+	var negotiate negotiateResponse
+	if err := g.CS.post(fmt.Sprintf("%s/negotiate?negotiateVersion=1&%s", hub, query), nil, &negotiate); err != nil {
+		return false, err
+	}
+
+	wsUrl, err := url.Parse(g.CS.resolve(hub))
+	if err != nil {
+		return false, err
+	}
+	wsUrl.Scheme = strings.Replace(wsUrl.Scheme, "http", "ws", 1)
+	token := negotiate.ConnectionToken
+	if token == "" {
+		token = negotiate.ConnectionId
+	}
+	wsUrl.RawQuery = fmt.Sprintf("id=%s&%s", url.QueryEscape(token), query)
+
+	dialer := websocket.Dialer{
+		Jar:              g.CS.Client.GetClient().Jar,
+		TLSClientConfig:  g.CS.Client.GetTLSClientConfig(),
+		HandshakeTimeout: 30 * time.Second,
+	}
+	conn, _, err := dialer.DialContext(ctx, wsUrl.String(), nil)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	handshake := append([]byte(`{"protocol":"json","version":1}`), signalRRecordSeparator)
+	if err := conn.WriteMessage(websocket.TextMessage, handshake); err != nil {
+		return false, err
+	}
+
+	// Unblock ReadMessage on cancel, without outliving this connection
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return true, err
+		}
+		for _, record := range bytes.Split(data, []byte{signalRRecordSeparator}) {
+			if len(record) == 0 {
+				continue
+			}
+			var message HubMessage
+			if err := json.Unmarshal(record, &message); err != nil {
+				continue
+			}
+			switch message.Type {
+			case signalRInvocation:
+				handler(message)
+			case signalRPing:
+				conn.WriteMessage(websocket.TextMessage, append([]byte(`{"type":6}`), signalRRecordSeparator))
+			case signalRClose:
+				return true, fmt.Errorf("hub closed connection: %s", message.Error)
+			}
+		}
+	}
+}
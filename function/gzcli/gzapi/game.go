@@ -52,11 +52,32 @@ func (ct *CustomTime) UnmarshalJSON(b []byte) error {
 		return nil
 	}
 
+	// Zero is what MarshalJSON writes for an unset time.
+	if ms == 0 {
+		ct.Time = time.Time{}
+		return nil
+	}
+
 	// Convert milliseconds to seconds and set the time.
 	ct.Time = time.Unix(0, ms*int64(time.Millisecond))
 	return nil
 }
 
+// MarshalJSON writes milliseconds since epoch, the format GZCTF uses for
+// every date, so the server never has to guess a timezone. An unset time is
+// written as 0.
+func (ct CustomTime) MarshalJSON() ([]byte, error) {
+	if ct.Time.IsZero() {
+		return []byte("0"), nil
+	}
+	return json.Marshal(ct.Time.UnixMilli())
+}
+
+// MarshalYAML writes the time as an RFC3339 string, the format used in conf.yaml.
+func (ct CustomTime) MarshalYAML() (interface{}, error) {
+	return ct.Time.Format(time.RFC3339Nano), nil
+}
+
 func (cs *GZAPI) GetGames() ([]*Game, error) {
 	var data struct {
 		Data []*Game `json:"data"`
@@ -86,7 +107,6 @@ func (cs *GZAPI) GetGameByTitle(title string) (*Game, error) {
 		return nil, err
 	}
 	for _, game := range games {
-		fmt.Println(game.Title, title)
 		if game.Title == title {
 			return game, nil
 		}
@@ -94,6 +114,20 @@ func (cs *GZAPI) GetGameByTitle(title string) (*Game, error) {
 	return nil, fmt.Errorf("game not found")
 }
 
+// GameJoinForm requests a team to take part in a game. Organization is the
+// division the team plays in
+type GameJoinForm struct {
+	TeamId       int    `json:"teamId"`
+	Organization string `json:"organization,omitempty"`
+	InviteCode   string `json:"inviteCode,omitempty"`
+}
+
+// JoinGame requests participation for a team. g.CS must be logged in as a
+// member of the team
+func (g *Game) JoinGame(form *GameJoinForm) error {
+	return g.CS.post(fmt.Sprintf("/api/game/%d", g.Id), form, nil)
+}
+
 func (g *Game) Delete() error {
 	return g.CS.delete(fmt.Sprintf("/api/edit/games/%d", g.Id), nil)
 }
@@ -0,0 +1,91 @@
+package gzapi_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCustomTimeJSONRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		time time.Time
+		wire string
+	}{
+		{"utc", time.Date(2024, 10, 11, 12, 0, 0, 0, time.UTC), "1728648000000"},
+		{"offset", time.Date(2024, 10, 11, 19, 0, 0, 0, time.FixedZone("WIB", 7*60*60)), "1728648000000"},
+		{"millis", time.Date(2024, 10, 11, 12, 0, 0, int(123*time.Millisecond), time.UTC), "1728648000123"},
+		{"zero", time.Time{}, "0"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(gzapi.CustomTime{Time: tt.time})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.wire {
+				t.Fatalf("marshal = %s, want %s", b, tt.wire)
+			}
+
+			var got gzapi.CustomTime
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.time) {
+				t.Fatalf("round trip = %v, want %v", got.Time, tt.time)
+			}
+		})
+	}
+}
+
+func TestCustomTimeUnmarshalRFC3339(t *testing.T) {
+	var got gzapi.CustomTime
+	if err := json.Unmarshal([]byte(`"2024-10-11T19:00:00+07:00"`), &got); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 10, 11, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("unmarshal = %v, want %v", got.Time, want)
+	}
+}
+
+func TestGameJSONUsesEpochMillis(t *testing.T) {
+	game := gzapi.Game{
+		Start: gzapi.CustomTime{Time: time.Date(2024, 10, 11, 12, 0, 0, 0, time.UTC)},
+	}
+	b, err := json.Marshal(game)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["start"] != float64(1728648000000) {
+		t.Fatalf("start = %v, want 1728648000000", fields["start"])
+	}
+	if fields["end"] != float64(0) {
+		t.Fatalf("end = %v, want 0", fields["end"])
+	}
+}
+
+func TestCustomTimeYAMLRoundTrip(t *testing.T) {
+	want := gzapi.Game{
+		Start: gzapi.CustomTime{Time: time.Date(2024, 10, 11, 12, 0, 0, 0, time.UTC)},
+		End:   gzapi.CustomTime{Time: time.Date(2024, 10, 12, 12, 30, 0, 0, time.FixedZone("", 7*60*60))},
+	}
+	b, err := yaml.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got gzapi.Game
+	if err := yaml.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Start.Equal(want.Start.Time) || !got.End.Equal(want.End.Time) {
+		t.Fatalf("round trip = %v..%v, want %v..%v\n%s", got.Start.Time, got.End.Time, want.Start.Time, want.End.Time, b)
+	}
+}
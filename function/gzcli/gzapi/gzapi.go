@@ -1,8 +1,12 @@
 package gzapi
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/imroc/req/v3"
 )
@@ -16,15 +20,23 @@ type GZAPI struct {
 	Url    string
 	Creds  *Creds
 	Client *req.Client
+
+	cache    *responseCache
+	cacheTTL time.Duration
+	upload   *uploader
 }
 
-func Init(url string, creds *Creds) (*GZAPI, error) {
+func Init(url string, creds *Creds, tlsConfig *TLSConfig) (*GZAPI, error) {
 	url = strings.TrimRight(url, "/")
+	client, err := newClient(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
 	newGz := &GZAPI{
-		Client: req.C().
-			SetUserAgent("Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/110.0"),
-		Url:   url,
-		Creds: creds,
+		Client: client,
+		Url:    url,
+		Creds:  creds,
+		cache:  &responseCache{},
 	}
 	if err := newGz.Login(); err != nil {
 		return nil, err
@@ -32,12 +44,16 @@ func Init(url string, creds *Creds) (*GZAPI, error) {
 	return newGz, nil
 }
 
-func Register(url string, creds *RegisterForm) (*GZAPI, error) {
+func Register(url string, creds *RegisterForm, tlsConfig *TLSConfig) (*GZAPI, error) {
 	url = strings.TrimRight(url, "/")
+	client, err := newClient(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
 	newGz := &GZAPI{
-		Client: req.C().
-			SetUserAgent("Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/110.0"),
-		Url: url,
+		Client: client,
+		Url:    url,
+		cache:  &responseCache{},
 		Creds: &Creds{
 			Username: creds.Username,
 			Password: creds.Password,
@@ -50,30 +66,48 @@ func Register(url string, creds *RegisterForm) (*GZAPI, error) {
 }
 
 func (cs *GZAPI) get(url string, data any) error {
-	url = cs.Url + url
-	req, err := cs.Client.R().Get(url)
+	url = cs.resolve(url)
+	var body []byte
+	var err error
+	if cs.cacheTTL > 0 && cs.cache != nil {
+		body, err = cs.cache.do(url, cs.cacheTTL, func() ([]byte, error) {
+			return cs.fetch(url)
+		})
+	} else {
+		body, err = cs.fetch(url)
+	}
 	if err != nil {
 		return err
 	}
-	if req.StatusCode != 200 {
-		return fmt.Errorf("request end with %d status, %s", req.StatusCode, req.String())
-	}
 	if data != nil {
-		if err := req.UnmarshalJson(&data); err != nil {
-			return fmt.Errorf("error unmarshal json: %w, %s", err, req.String())
+		if err := json.Unmarshal(body, data); err != nil {
+			return fmt.Errorf("error unmarshal json: %w, %s", err, body)
 		}
 	}
 	return nil
 }
 
+// fetch returns the body of a GET of url
+func (cs *GZAPI) fetch(url string) ([]byte, error) {
+	req, err := cs.Client.R().Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if req.StatusCode != 200 {
+		return nil, newAPIError(req)
+	}
+	return req.Bytes(), nil
+}
+
 func (cs *GZAPI) delete(url string, data any) error {
-	url = cs.Url + url
+	url = cs.resolve(url)
+	cs.cache.clear()
 	req, err := cs.Client.R().Delete(url)
 	if err != nil {
 		return err
 	}
 	if req.StatusCode != 200 {
-		return fmt.Errorf("request end with %d status, %s", req.StatusCode, req.String())
+		return newAPIError(req)
 	}
 	if data != nil {
 		if err := req.UnmarshalJson(&data); err != nil {
@@ -84,13 +118,14 @@ func (cs *GZAPI) delete(url string, data any) error {
 }
 
 func (cs *GZAPI) post(url string, json any, data any) error {
-	url = cs.Url + url
+	url = cs.resolve(url)
+	cs.cache.clear()
 	req, err := cs.Client.R().SetBodyJsonMarshal(json).Post(url)
 	if err != nil {
 		return err
 	}
 	if req.StatusCode != 200 {
-		return fmt.Errorf("request end with %d status, %s", req.StatusCode, req.String())
+		return newAPIError(req)
 	}
 	if data != nil {
 		if err := req.UnmarshalJson(&data); err != nil {
@@ -101,13 +136,19 @@ func (cs *GZAPI) post(url string, json any, data any) error {
 }
 
 func (cs *GZAPI) postMultiPart(url string, file string, data any) error {
-	url = cs.Url + url
-	req, err := cs.Client.R().SetFile("files", file).Post(url)
+	url = cs.resolve(url)
+	cs.cache.clear()
+	r, release := cs.uploadRequest(file)
+	defer release()
+	req, err := r.SetFile("files", file).Post(url)
 	if err != nil {
 		return err
 	}
+	if req.StatusCode == http.StatusRequestEntityTooLarge {
+		return fmt.Errorf("upload of %s rejected, it exceeds the server request size limit: %w", filepath.Base(file), newAPIError(req))
+	}
 	if req.StatusCode != 200 {
-		return fmt.Errorf("request end with %d status, %s", req.StatusCode, req.String())
+		return newAPIError(req)
 	}
 	if data != nil {
 		if err := req.UnmarshalJson(&data); err != nil {
@@ -118,13 +159,19 @@ func (cs *GZAPI) postMultiPart(url string, file string, data any) error {
 }
 
 func (cs *GZAPI) putMultiPart(url string, file string, data any) error {
-	url = cs.Url + url
-	req, err := cs.Client.R().SetFile("file", file).Put(url)
+	url = cs.resolve(url)
+	cs.cache.clear()
+	r, release := cs.uploadRequest(file)
+	defer release()
+	req, err := r.SetFile("file", file).Put(url)
 	if err != nil {
 		return err
 	}
+	if req.StatusCode == http.StatusRequestEntityTooLarge {
+		return fmt.Errorf("upload of %s rejected, it exceeds the server request size limit: %w", filepath.Base(file), newAPIError(req))
+	}
 	if req.StatusCode != 200 {
-		return fmt.Errorf("request end with %d status, %s", req.StatusCode, req.String())
+		return newAPIError(req)
 	}
 	if data != nil {
 		if err := req.UnmarshalJson(&data); err != nil {
@@ -135,13 +182,14 @@ func (cs *GZAPI) putMultiPart(url string, file string, data any) error {
 }
 
 func (cs *GZAPI) put(url string, json any, data any) error {
-	url = cs.Url + url
+	url = cs.resolve(url)
+	cs.cache.clear()
 	req, err := cs.Client.R().SetBodyJsonMarshal(json).Put(url)
 	if err != nil {
 		return err
 	}
 	if req.StatusCode != 200 {
-		return fmt.Errorf("request end with %d status, %s", req.StatusCode, req.String())
+		return newAPIError(req)
 	}
 	if data != nil {
 		if err := req.UnmarshalJson(&data); err != nil {
@@ -150,3 +198,15 @@ func (cs *GZAPI) put(url string, json any, data any) error {
 	}
 	return nil
 }
+
+func (cs *GZAPI) download(url string, dst string) error {
+	url = cs.resolve(url)
+	req, err := cs.Client.R().SetOutputFile(dst).Get(url)
+	if err != nil {
+		return err
+	}
+	if req.StatusCode != 200 {
+		return newAPIError(req)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package gzapi
+
+import "time"
+
+// GameHandle is an immutable reference to a game, resolved once per run and
+// safe to share between goroutines. Unlike *Game, nothing can reassign its
+// id or API client after it is created
+type GameHandle struct {
+	id        int
+	publicKey string
+	title     string
+	start     time.Time
+	cs        *GZAPI
+}
+
+// Handle freezes the identity and API client of the game
+func (g *Game) Handle() GameHandle {
+	return GameHandle{id: g.Id, publicKey: g.PublicKey, title: g.Title, start: g.Start.Time, cs: g.CS}
+}
+
+func (h GameHandle) Id() int           { return h.id }
+func (h GameHandle) PublicKey() string { return h.publicKey }
+func (h GameHandle) Title() string     { return h.title }
+func (h GameHandle) Start() time.Time  { return h.start }
+func (h GameHandle) API() *GZAPI       { return h.cs }
+
+// game returns a private Game value so calls never touch a shared struct
+func (h GameHandle) game() *Game {
+	return &Game{Id: h.id, PublicKey: h.publicKey, Title: h.title, CS: h.cs}
+}
+
+func (h GameHandle) CreateChallenge(challenge CreateChallengeForm) (*Challenge, error) {
+	return h.game().CreateChallenge(challenge)
+}
+
+func (h GameHandle) GetChallenges() ([]Challenge, error) {
+	return h.game().GetChallenges()
+}
+
+func (h GameHandle) GetChallenge(name string) (*Challenge, error) {
+	return h.game().GetChallenge(name)
+}
+
+func (h GameHandle) CreateNotice(notice NoticeForm) (*Notice, error) {
+	return h.game().CreateNotice(notice)
+}
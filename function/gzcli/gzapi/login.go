@@ -0,0 +1,112 @@
+package gzapi
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	loginAttempts   = 5
+	loginBackoff    = time.Second
+	maxLoginBackoff = 30 * time.Second
+)
+
+// session is shared by every client of one account in this process, so that
+// concurrent Init calls log in once instead of tripping the rate limiter
+type session struct {
+	mu      sync.Mutex
+	cookies []*http.Cookie
+}
+
+var sessions = struct {
+	sync.Mutex
+	byAccount map[string]*session
+}{byAccount: map[string]*session{}}
+
+func accountSession(baseUrl string, username string) *session {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	key := baseUrl + "\x00" + username
+	s, ok := sessions.byAccount[key]
+	if !ok {
+		s = &session{}
+		sessions.byAccount[key] = s
+	}
+	return s
+}
+
+// Login signs in with cs.Creds. Only one login per account runs at a time,
+// a still valid session of the same account is reused, and rate limited or
+// failed attempts are retried with exponential backoff. Wrong credentials
+// fail at once
+func (cs *GZAPI) Login() error {
+	s := accountSession(cs.Url, cs.Creds.Username)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.cookies) > 0 {
+		cs.setSessionCookies(s.cookies)
+		if cs.sessionValid() {
+			return nil
+		}
+	}
+
+	if err := cs.loginWithBackoff(); err != nil {
+		return err
+	}
+	s.cookies = cs.sessionCookies()
+	return nil
+}
+
+func (cs *GZAPI) loginWithBackoff() error {
+	backoff := loginBackoff
+	for attempt := 1; ; attempt++ {
+		cs.cache.clear()
+		resp, err := cs.Client.R().SetBodyJsonMarshal(cs.Creds).Post(cs.resolve("/api/account/login"))
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode == 200 {
+			return nil
+		}
+
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if !retryable || attempt == loginAttempts {
+			return newAPIError(resp)
+		}
+
+		wait := backoff
+		if seconds, err := strconv.Atoi(resp.GetHeader("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		time.Sleep(wait)
+		if backoff *= 2; backoff > maxLoginBackoff {
+			backoff = maxLoginBackoff
+		}
+	}
+}
+
+func (cs *GZAPI) sessionValid() bool {
+	resp, err := cs.Client.R().Get(cs.resolve("/api/account/profile"))
+	return err == nil && resp.StatusCode == 200
+}
+
+func (cs *GZAPI) sessionCookies() []*http.Cookie {
+	u, err := url.Parse(cs.resolve("/"))
+	if err != nil || cs.Client.GetClient().Jar == nil {
+		return nil
+	}
+	return cs.Client.GetClient().Jar.Cookies(u)
+}
+
+func (cs *GZAPI) setSessionCookies(cookies []*http.Cookie) {
+	u, err := url.Parse(cs.resolve("/"))
+	if err != nil || cs.Client.GetClient().Jar == nil {
+		return
+	}
+	cs.Client.GetClient().Jar.SetCookies(u, cookies)
+}
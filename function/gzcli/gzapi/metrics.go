@@ -0,0 +1,156 @@
+package gzapi
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imroc/req/v3"
+)
+
+// LatencyBuckets are the upper bounds of the latency histogram buckets. A
+// last implicit bucket holds everything slower
+var LatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// LatencyStats is the latency histogram of one endpoint. Endpoints are the
+// method and path with ids replaced, such as GET /api/edit/games/{id}
+type LatencyStats struct {
+	Endpoint string        `json:"endpoint"`
+	Count    int           `json:"count"`
+	Errors   int           `json:"errors"`
+	Total    time.Duration `json:"total"`
+	Max      time.Duration `json:"max"`
+	Buckets  []int         `json:"buckets"`
+}
+
+func (s LatencyStats) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Count)
+}
+
+// Quantile estimates the q quantile as the upper bound of the bucket it
+// falls in, or Max when it falls past the last bucket
+func (s LatencyStats) Quantile(q float64) time.Duration {
+	rank := int(q*float64(s.Count) + 0.5)
+	seen := 0
+	for i, n := range s.Buckets {
+		if seen += n; seen >= rank && i < len(LatencyBuckets) {
+			return LatencyBuckets[i]
+		}
+	}
+	return s.Max
+}
+
+// SlowCallFunc is told about every request slower than the slow call threshold
+type SlowCallFunc func(endpoint string, status int, took time.Duration)
+
+var (
+	latencyMu     sync.Mutex
+	latency       = map[string]*LatencyStats{}
+	slowThreshold time.Duration
+	slowCall      SlowCallFunc
+)
+
+var (
+	idSegment   = regexp.MustCompile(`^[0-9]+$`)
+	guidSegment = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hashSegment = regexp.MustCompile(`^[0-9a-fA-F]{32,}$`)
+)
+
+// SetSlowCallLog calls fn for every request slower than threshold. A zero
+// threshold turns it off
+func SetSlowCallLog(threshold time.Duration, fn SlowCallFunc) {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	slowThreshold, slowCall = threshold, fn
+}
+
+// Latency returns the latency histograms recorded by every client of this
+// process, slowest total first
+func Latency() []LatencyStats {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	stats := make([]LatencyStats, 0, len(latency))
+	for _, s := range latency {
+		copied := *s
+		copied.Buckets = append([]int(nil), s.Buckets...)
+		stats = append(stats, copied)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Total > stats[j].Total })
+	return stats
+}
+
+func ResetLatency() {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	latency = map[string]*LatencyStats{}
+}
+
+// endpointOf names the endpoint of a request by its method and path with id,
+// guid and hash segments replaced
+func endpointOf(method string, u *url.URL) string {
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		switch {
+		case idSegment.MatchString(segment):
+			segments[i] = "{id}"
+		case guidSegment.MatchString(segment):
+			segments[i] = "{guid}"
+		case hashSegment.MatchString(segment):
+			segments[i] = "{hash}"
+		}
+	}
+	return method + " " + strings.Join(segments, "/")
+}
+
+// recordLatency is the response middleware every client records its
+// requests with
+func recordLatency(_ *req.Client, resp *req.Response) error {
+	if resp.Request == nil || resp.Request.RawRequest == nil {
+		return nil
+	}
+	took := time.Since(resp.Request.StartTime)
+	endpoint := endpointOf(resp.Request.RawRequest.Method, resp.Request.RawRequest.URL)
+	status := 0
+	if resp.Response != nil {
+		status = resp.StatusCode
+	}
+
+	latencyMu.Lock()
+	s, ok := latency[endpoint]
+	if !ok {
+		s = &LatencyStats{Endpoint: endpoint, Buckets: make([]int, len(LatencyBuckets)+1)}
+		latency[endpoint] = s
+	}
+	s.Count++
+	if resp.Err != nil || status >= 400 {
+		s.Errors++
+	}
+	s.Total += took
+	if took > s.Max {
+		s.Max = took
+	}
+	bucket := sort.Search(len(LatencyBuckets), func(i int) bool { return took <= LatencyBuckets[i] })
+	s.Buckets[bucket]++
+	threshold, fn := slowThreshold, slowCall
+	latencyMu.Unlock()
+
+	if fn != nil && threshold > 0 && took > threshold {
+		fn(endpoint, status, took)
+	}
+	return nil
+}
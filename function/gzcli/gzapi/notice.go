@@ -0,0 +1,42 @@
+package gzapi
+
+import "fmt"
+
+type Notice struct {
+	Id       int        `json:"id"`
+	Content  string     `json:"content"`
+	IsPinned bool       `json:"isPinned"`
+	Time     CustomTime `json:"time"`
+	GameId   int        `json:"-"`
+	CS       *GZAPI     `json:"-"`
+}
+
+type NoticeForm struct {
+	Content string `json:"content"`
+}
+
+func (n *Notice) Delete() error {
+	return n.CS.delete(fmt.Sprintf("/api/edit/games/%d/notices/%d", n.GameId, n.Id), nil)
+}
+
+func (g *Game) CreateNotice(notice NoticeForm) (*Notice, error) {
+	var data *Notice
+	if err := g.CS.post(fmt.Sprintf("/api/edit/games/%d/notices", g.Id), notice, &data); err != nil {
+		return nil, err
+	}
+	data.GameId = g.Id
+	data.CS = g.CS
+	return data, nil
+}
+
+func (g *Game) GetNotices() ([]*Notice, error) {
+	var data []*Notice
+	if err := g.CS.get(fmt.Sprintf("/api/edit/games/%d/notices", g.Id), &data); err != nil {
+		return nil, err
+	}
+	for _, notice := range data {
+		notice.GameId = g.Id
+		notice.CS = g.CS
+	}
+	return data, nil
+}
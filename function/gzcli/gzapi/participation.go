@@ -0,0 +1,44 @@
+package gzapi
+
+import "fmt"
+
+// Participation statuses accepted by the platform
+const (
+	ParticipationPending   = "Pending"
+	ParticipationAccepted  = "Accepted"
+	ParticipationRejected  = "Rejected"
+	ParticipationSuspended = "Suspended"
+)
+
+type ParticipationTeam struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type Participation struct {
+	Id     int               `json:"id"`
+	Team   ParticipationTeam `json:"team"`
+	Status string            `json:"status"`
+	CS     *GZAPI            `json:"-"`
+}
+
+func (g *Game) GetParticipations() ([]*Participation, error) {
+	var data []*Participation
+	if err := g.CS.get(fmt.Sprintf("/api/game/%d/participations", g.Id), &data); err != nil {
+		return nil, err
+	}
+	for _, p := range data {
+		p.CS = g.CS
+	}
+	return data, nil
+}
+
+func (p *Participation) UpdateStatus(status string) error {
+	if err := p.CS.put(fmt.Sprintf("/api/admin/participation/%d", p.Id), map[string]string{
+		"status": status,
+	}, nil); err != nil {
+		return err
+	}
+	p.Status = status
+	return nil
+}
@@ -5,15 +5,28 @@ import (
 )
 
 type ScoreboardChallenge struct {
+	Id       int    `json:"id"`
 	Score    int    `json:"score"`
 	Category string `json:"category"`
 	Title    string `json:"title"`
 }
 
+// ScoreboardSolve is one challenge solved by a team, with the points it
+// earned including blood bonus
+type ScoreboardSolve struct {
+	Id       int        `json:"id"`
+	Score    int        `json:"score"`
+	Type     string     `json:"type"`
+	UserName string     `json:"userName"`
+	Time     CustomTime `json:"time"`
+}
+
 type ScoreboardItem struct {
-	Name  string `json:"name"`
-	Rank  int    `json:"rank"`
-	Score int    `json:"score"`
+	Name             string            `json:"name"`
+	Rank             int               `json:"rank"`
+	Score            int               `json:"score"`
+	LastSolvedTime   CustomTime        `json:"lastSolvedTime"`
+	SolvedChallenges []ScoreboardSolve `json:"solvedChallenges,omitempty"`
 }
 
 type Scoreboard struct {
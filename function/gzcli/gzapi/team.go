@@ -30,15 +30,37 @@ func (cs *GZAPI) CreateTeam(teamForm *TeamForm) error {
 	return nil
 }
 
+// teamsPageSize is the page size used to walk the admin team list
+const teamsPageSize = 100
+
+// Teams lists every team, one page at a time
 func (cs *GZAPI) Teams() ([]*Team, error) {
-	var teams struct {
-		Data []*Team `json:"data"`
+	var all []*Team
+	for skip := 0; ; skip += teamsPageSize {
+		var teams struct {
+			Data []*Team `json:"data"`
+		}
+		if err := cs.get(fmt.Sprintf("/api/admin/teams?count=%d&skip=%d", teamsPageSize, skip), &teams); err != nil {
+			return nil, err
+		}
+		for _, team := range teams.Data {
+			team.CS = cs
+		}
+		all = append(all, teams.Data...)
+		if len(teams.Data) < teamsPageSize {
+			return all, nil
+		}
 	}
-	if err := cs.get("/api/admin/teams", &teams); err != nil {
+}
+
+// MyTeams lists the teams of the logged in user
+func (cs *GZAPI) MyTeams() ([]*Team, error) {
+	var teams []*Team
+	if err := cs.get("/api/team", &teams); err != nil {
 		return nil, err
 	}
-	for t := range teams.Data {
-		teams.Data[t].CS = cs
+	for _, team := range teams {
+		team.CS = cs
 	}
-	return teams.Data, nil
+	return teams, nil
 }
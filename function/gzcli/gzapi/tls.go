@@ -0,0 +1,65 @@
+package gzapi
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/imroc/req/v3"
+)
+
+// TLSConfig trusts a private certificate authority and optionally presents
+// a client certificate for deployments that require mutual TLS
+type TLSConfig struct {
+	CAFile   string `yaml:"caFile"`
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+}
+
+func (t *TLSConfig) clientConfig() (*tls.Config, error) {
+	conf := &tls.Config{}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read ca file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", t.CAFile)
+		}
+		conf.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		if t.CertFile == "" || t.KeyFile == "" {
+			return nil, fmt.Errorf("client certificate requires both certFile and keyFile")
+		}
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return conf, nil
+}
+
+func newClient(tlsConfig *TLSConfig) (*req.Client, error) {
+	client := req.C().
+		SetUserAgent("Mozilla/5.0 (X11; Linux x86_64; rv:109.0) Gecko/20100101 Firefox/110.0").
+		OnAfterResponse(recordLatency)
+	if tlsConfig == nil {
+		return client, nil
+	}
+
+	conf, err := tlsConfig.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return client.SetTLSClientConfig(conf), nil
+}
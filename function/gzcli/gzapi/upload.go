@@ -0,0 +1,67 @@
+package gzapi
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/imroc/req/v3"
+)
+
+const uploadProgressInterval = 2 * time.Second
+
+// UploadOptions tune multipart uploads of large attachments and posters.
+// GZCTF has no resumable upload endpoint, so a failed upload starts over
+type UploadOptions struct {
+	// Timeout of a single upload, zero keeps the client timeout
+	Timeout time.Duration
+	// Limit is the number of uploads running at once, zero means no limit
+	Limit int
+	// Progress is called periodically while a file is uploaded
+	Progress func(file string, uploaded int64, total int64)
+}
+
+type uploader struct {
+	client   *req.Client
+	sem      chan struct{}
+	progress func(file string, uploaded int64, total int64)
+}
+
+// SetUploadOptions applies opts to every upload made through cs and the
+// views returned by Cached
+func (cs *GZAPI) SetUploadOptions(opts UploadOptions) {
+	u := &uploader{client: cs.Client, progress: opts.Progress}
+	if opts.Timeout > 0 {
+		// a clone gets a fresh cookie jar, keep the logged in session
+		u.client = cs.Client.Clone().
+			SetCookieJar(cs.Client.GetClient().Jar).
+			SetTimeout(opts.Timeout)
+	}
+	if opts.Limit > 0 {
+		u.sem = make(chan struct{}, opts.Limit)
+	}
+	cs.upload = u
+}
+
+// uploadRequest returns a request for uploading file and a release func that
+// must be called once the upload is done
+func (cs *GZAPI) uploadRequest(file string) (*req.Request, func()) {
+	u := cs.upload
+	if u == nil {
+		return cs.Client.R(), func() {}
+	}
+
+	release := func() {}
+	if u.sem != nil {
+		u.sem <- struct{}{}
+		release = func() { <-u.sem }
+	}
+
+	r := u.client.R()
+	if u.progress != nil {
+		name := filepath.Base(file)
+		r.SetUploadCallbackWithInterval(func(info req.UploadInfo) {
+			u.progress(name, info.UploadedSize, info.FileSize)
+		}, uploadProgressInterval)
+	}
+	return r, release
+}
@@ -0,0 +1,27 @@
+package gzapi
+
+import (
+	"net/url"
+	"strings"
+)
+
+// resolve turns a server path into a full url. Instances hosted under a path
+// prefix (https://host/ctf) get it prepended once, paths the server already
+// returned with the prefix and absolute urls are left as they are
+func (cs *GZAPI) resolve(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	base, err := url.Parse(cs.Url)
+	if err != nil || base.Path == "" || base.Path == "/" {
+		return cs.Url + path
+	}
+	if path == base.Path || strings.HasPrefix(path, base.Path+"/") {
+		return base.Scheme + "://" + base.Host + path
+	}
+	return cs.Url + path
+}
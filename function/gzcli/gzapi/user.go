@@ -1,13 +1,27 @@
 package gzapi
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+)
+
+// User roles reported by the admin user list
+const (
+	RoleAdmin   = "Admin"
+	RoleMonitor = "Monitor"
+	RoleUser    = "User"
+	RoleBanned  = "Banned"
+)
 
 type User struct {
-	Id       string `json:"id"`
-	UserName string `json:"username"`
-	Bio      string `json:"bio"`
-	Captain  bool   `json:"captain"`
-	API      *GZAPI `json:"-"`
+	Id             string `json:"id"`
+	UserName       string `json:"username"`
+	Email          string `json:"email,omitempty"`
+	Role           string `json:"role,omitempty"`
+	EmailConfirmed bool   `json:"emailConfirmed,omitempty"`
+	Bio            string `json:"bio"`
+	Captain        bool   `json:"captain"`
+	API            *GZAPI `json:"-"`
 }
 
 func (user *User) Delete() error {
@@ -17,15 +31,64 @@ func (user *User) Delete() error {
 	return nil
 }
 
+// UserUpdateForm changes an account from the admin panel. Nil fields are
+// left unchanged
+type UserUpdateForm struct {
+	UserName       *string `json:"userName,omitempty"`
+	Email          *string `json:"email,omitempty"`
+	Bio            *string `json:"bio,omitempty"`
+	EmailConfirmed *bool   `json:"emailConfirmed,omitempty"`
+	Role           *string `json:"role,omitempty"`
+}
+
+// usersPageSize is the page size used to walk the admin user list
+const usersPageSize = 100
+
+// Users lists every account, one page at a time
 func (api *GZAPI) Users() ([]*User, error) {
+	var all []*User
+	for skip := 0; ; skip += usersPageSize {
+		var users struct {
+			Data []*User `json:"data"`
+		}
+		if err := api.get(fmt.Sprintf("/api/admin/users?count=%d&skip=%d", usersPageSize, skip), &users); err != nil {
+			return nil, err
+		}
+		for _, user := range users.Data {
+			user.API = api
+		}
+		all = append(all, users.Data...)
+		if len(users.Data) < usersPageSize {
+			return all, nil
+		}
+	}
+}
+
+// SearchUsers returns the accounts whose username, email, real name or id
+// contain hint
+func (api *GZAPI) SearchUsers(hint string) ([]*User, error) {
 	var users struct {
 		Data []*User `json:"data"`
 	}
-	if err := api.get("/api/admin/users", &users); err != nil {
+	if err := api.post("/api/admin/users/search?hint="+url.QueryEscape(hint), nil, &users); err != nil {
 		return nil, err
 	}
-	for t := range users.Data {
-		users.Data[t].API = api
+	for _, user := range users.Data {
+		user.API = api
 	}
 	return users.Data, nil
 }
+
+// Update changes the account with the non nil fields of form
+func (user *User) Update(form *UserUpdateForm) error {
+	return user.API.put(fmt.Sprintf("/api/admin/users/%s", user.Id), form, nil)
+}
+
+// ResetPassword replaces the password of the user with a random one and returns it
+func (user *User) ResetPassword() (string, error) {
+	var password string
+	if err := user.API.delete(fmt.Sprintf("/api/admin/users/%s/password", user.Id), &password); err != nil {
+		return "", err
+	}
+	return password, nil
+}
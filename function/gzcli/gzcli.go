@@ -2,20 +2,52 @@ package gzcli
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
 	"github.com/dimasma0305/ctfify/function/log"
 )
 
 type Config struct {
-	Url   string      `yaml:"url"`
-	Creds gzapi.Creds `yaml:"creds"`
-	Event gzapi.Game  `yaml:"event"`
+	Url             string                 `yaml:"url"`
+	Creds           gzapi.Creds            `yaml:"creds"`
+	Event           gzapi.Game             `yaml:"event"`
+	Events          []GameEvent            `yaml:"events,omitempty"`
+	Canary          *CanaryConfig          `yaml:"canary,omitempty"`
+	Announce        *AnnounceConfig        `yaml:"announce,omitempty"`
+	Budgets         map[string]string      `yaml:"attachmentBudgets,omitempty"`
+	CDN             *CDNConfig             `yaml:"cdn,omitempty"`
+	TeamRules       *TeamRules             `yaml:"teamRules,omitempty"`
+	TLS             *gzapi.TLSConfig       `yaml:"tls,omitempty"`
+	UploadLimit     string                 `yaml:"uploadLimit,omitempty"`
+	UploadTimeout   string                 `yaml:"uploadTimeout,omitempty"`
+	UploadWorkers   int                    `yaml:"uploadWorkers,omitempty"`
+	FlagFormat      string                 `yaml:"flagFormat,omitempty"`
+	SlowApiCall     string                 `yaml:"slowApiCall,omitempty"`
+	GitLfsPull      bool                   `yaml:"gitLfsPull,omitempty"`
+	ContainerPolicy *gzapi.ContainerPolicy `yaml:"containerPolicy,omitempty"`
+	ScriptRunner    *ScriptRunner          `yaml:"scriptRunner,omitempty"`
+	UpdateRules     []UpdateRule           `yaml:"updateRules,omitempty"`
+	SyncPolicy      map[string]SyncPolicy  `yaml:"syncPolicy,omitempty"`
+	Email           *EmailConfig           `yaml:"email,omitempty"`
+	CredsRetention  string                 `yaml:"credsRetention,omitempty"`
+
+	cachePrefix   string
+	challengeRoot string
+	categories    []string
+	syncWorkers   int
+	events        *eventBus
+}
+
+type CanaryConfig struct {
+	Title string `yaml:"title"`
 }
 
 type Container struct {
@@ -35,19 +67,36 @@ type ChallengeYaml struct {
 	Flags       []string          `yaml:"flags"`
 	Value       int               `yaml:"value"`
 	Provide     *string           `yaml:"provide,omitempty"`
-	Visible     *bool             `yaml:"visible"`
+	Visible     *bool             `yaml:"visible,omitempty"`
 	Type        string            `yaml:"type"`
-	Hints       []string          `yaml:"hints"`
-	Container   Container         `yaml:"container"`
-	Scripts     map[string]string `yaml:"scripts"`
+	Hints       []Hint            `yaml:"hints,omitempty"`
+	Container   Container         `yaml:"container,omitempty"`
+	Scripts     map[string]string `yaml:"scripts,omitempty"`
+	Healthcheck *Healthcheck      `yaml:"healthcheck,omitempty"`
+	Compose     *bool             `yaml:"compose,omitempty"`
+	Solver      *Solver           `yaml:"solver,omitempty"`
+	DependsOn   []string          `yaml:"depends_on,omitempty"`
+	UpdateRules []UpdateRule      `yaml:"updateRules,omitempty"`
 	Category    string            `yaml:"-"`
 	Cwd         string            `yaml:"-"`
+
+	runner *ScriptRunner
+	// sources are the _defaults.yml and extends files merged into it
+	sources []string
+}
+
+// TaskStat is a solved task in the CTFtime scoreboard feed
+type TaskStat struct {
+	Points int   `json:"points"`
+	Time   int64 `json:"time"`
 }
 
 type Standing struct {
-	Pos   int    `json:"pos"`
-	Team  string `json:"team"`
-	Score int    `json:"score"`
+	Pos        int                 `json:"pos"`
+	Team       string              `json:"team"`
+	Score      int                 `json:"score"`
+	TaskStats  map[string]TaskStat `json:"taskStats,omitempty"`
+	LastAccept int64               `json:"lastAccept,omitempty"`
 }
 
 type CTFTimeFeed struct {
@@ -56,10 +105,17 @@ type CTFTimeFeed struct {
 }
 
 type GZ struct {
-	api        *gzapi.GZAPI
-	UpdateGame bool
+	api         *gzapi.GZAPI
+	UpdateGame  bool
+	Canary      bool
+	SyncWorkers int
+
+	events eventBus
 }
 
+// defaultSyncWorkers bounds concurrent challenge syncs when GZ.SyncWorkers is unset
+const defaultSyncWorkers = 8
+
 // Cache frequently used paths and configurations
 var (
 	workDirOnce   sync.Once
@@ -112,8 +168,19 @@ func Init() (*GZ, error) {
 			return
 		}
 
-		api, err := gzapi.Init(config.Url, &config.Creds)
+		opts, err := uploadOptions(config)
+		if err != nil {
+			initErr = err
+			return
+		}
+		if err := setSlowCallLog(config); err != nil {
+			initErr = err
+			return
+		}
+
+		api, err := gzapi.Init(config.Url, &config.Creds, config.TLS)
 		if err == nil {
+			api.SetUploadOptions(opts)
 			initGZ = &GZ{api: api}
 			return
 		}
@@ -123,7 +190,7 @@ func Init() (*GZ, error) {
 			Email:    "admin@localhost",
 			Username: config.Creds.Username,
 			Password: config.Creds.Password,
-		})
+		}, config.TLS)
 		if err != nil {
 			initErr = fmt.Errorf("registration failed: %w", err)
 			return
@@ -137,6 +204,7 @@ func Init() (*GZ, error) {
 			return
 		}
 
+		api.SetUploadOptions(opts)
 		initGZ = &GZ{api: api}
 	})
 	return initGZ, initErr
@@ -207,12 +275,12 @@ func (gz *GZ) RemoveAllEvent() error {
 
 // Preallocated scoreboard generation
 func (gz *GZ) Scoreboard2CTFTimeFeed() (*CTFTimeFeed, error) {
-	config, err := GetConfig(gz.api)
+	game, err := gz.currentGame()
 	if err != nil {
 		return nil, err
 	}
 
-	scoreboard, err := config.Event.GetScoreboard()
+	scoreboard, err := game.GetScoreboard()
 	if err != nil {
 		return nil, fmt.Errorf("scoreboard error: %w", err)
 	}
@@ -222,73 +290,78 @@ func (gz *GZ) Scoreboard2CTFTimeFeed() (*CTFTimeFeed, error) {
 		Tasks:     make([]string, 0, len(scoreboard.Challenges)*5),
 	}
 
+	taskNames := map[int]string{}
+	for category, items := range scoreboard.Challenges {
+		for _, item := range items {
+			name := fmt.Sprintf("%s - %s", category, item.Title)
+			taskNames[item.Id] = name
+			feed.Tasks = append(feed.Tasks, name)
+		}
+	}
+	sort.Strings(feed.Tasks)
+
 	for _, item := range scoreboard.Items {
-		feed.Standings = append(feed.Standings, Standing{
+		standing := Standing{
 			Pos:   item.Rank,
 			Team:  item.Name,
 			Score: item.Score,
-		})
-	}
-
-	for category, items := range scoreboard.Challenges {
-		for _, item := range items {
-			feed.Tasks = append(feed.Tasks, fmt.Sprintf("%s - %s", category, item.Title))
 		}
+		if !item.LastSolvedTime.IsZero() {
+			standing.LastAccept = item.LastSolvedTime.Unix()
+		}
+		for _, solve := range item.SolvedChallenges {
+			name, ok := taskNames[solve.Id]
+			if !ok {
+				continue
+			}
+			if standing.TaskStats == nil {
+				standing.TaskStats = map[string]TaskStat{}
+			}
+			standing.TaskStats[name] = TaskStat{Points: solve.Score, Time: solve.Time.Unix()}
+		}
+		feed.Standings = append(feed.Standings, standing)
 	}
 	return feed, nil
 }
 
 // Optimized script runner with worker pool
 func RunScripts(script string) error {
-	challengesConf, err := GetChallengesYaml(&Config{})
+	config, err := getLocalConfig()
+	if err != nil {
+		return err
+	}
+	challengesConf, err := GetChallengesYaml(config)
 	if err != nil {
 		return err
 	}
 
+	if err := validateDependencies(challengesConf); err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	workChan := make(chan ChallengeYaml, len(challengesConf))
-	errChan := make(chan error, 1)
-	var wg sync.WaitGroup
-
-	// Create worker pool
-	for i := 0; i < maxParallelScripts; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for challengeConf := range workChan {
-				select {
-				case <-ctx.Done():
-					return
-				default:
-					if err := runScript(challengeConf, script); err != nil {
-						select {
-						case errChan <- fmt.Errorf("script error in %s: %w", challengeConf.Name, err):
-							cancel()
-						default:
-						}
-					}
-				}
-			}
-		}()
-	}
-
-	// Distribute work
+	var withScript []ChallengeYaml
 	for _, conf := range challengesConf {
-		if _, ok := conf.Scripts[script]; ok {
-			workChan <- conf
+		if challengeScript(conf, script) != "" {
+			withScript = append(withScript, conf)
 		}
 	}
-	close(workChan)
-	wg.Wait()
 
-	select {
-	case err := <-errChan:
-		return err
-	default:
-		return nil
-	}
+	// Start in depends_on order and stop in reverse, cancelling the rest on
+	// the first failure
+	var firstErr error
+	pool := newSlotPool(maxParallelScripts, config.SyncPolicy)
+	runInDependencyOrder(ctx, withScript, pool, script == "stop", func(c ChallengeYaml) error {
+		return runScript(c, script)
+	}, func(c ChallengeYaml, err error) {
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("script error in %s: %w", c.Name, err)
+			cancel()
+		}
+	})
+	return firstErr
 }
 
 func (gz *GZ) Sync() error {
@@ -311,7 +384,7 @@ func (gz *GZ) Sync() error {
 
 	currentGame := findCurrentGame(games, config.Event.Title, gz.api)
 	if currentGame == nil {
-		DeleteCache("config")
+		DeleteCache(config.configCacheKey())
 		return gz.Sync()
 	}
 
@@ -325,37 +398,75 @@ func (gz *GZ) Sync() error {
 		return err
 	}
 
-	// Get fresh challenges list
-	config.Event.CS = gz.api
-	challenges, err := config.Event.GetChallenges()
-	if err != nil {
+	if err := gz.validateContainers(challengesConf); err != nil {
 		return err
 	}
 
-	// Process challenges
-	var wg sync.WaitGroup
-	errChan := make(chan error, len(challengesConf))
+	if err := gz.syncContainerPolicy(config); err != nil {
+		return err
+	}
 
-	for _, conf := range challengesConf {
-		wg.Add(1)
-		go func(c ChallengeYaml) {
-			defer wg.Done()
-			if err := syncChallenge(config, c, challenges, gz.api); err != nil {
-				errChan <- err
-			}
-		}(conf)
+	config.syncWorkers = gz.SyncWorkers
+	config.events = &gz.events
+	if gz.Canary {
+		if challengesConf, err = gz.canarySync(config, challengesConf); err != nil {
+			return err
+		}
 	}
 
-	wg.Wait()
-	close(errChan)
+	if err := syncChallenges(config, currentGame.Handle(), challengesConf); err != nil {
+		return err
+	}
+
+	if gz.Canary {
+		return saveCanaryFingerprints(config, challengesConf)
+	}
+	return nil
+}
 
-	// Return first error if any
-	select {
-	case err := <-errChan:
+// syncChallenges syncs every challenge config into game. The workers share
+// only the immutable game handle, never config.Event
+func syncChallenges(config *Config, game gzapi.GameHandle, challengesConf []ChallengeYaml) error {
+	// Get fresh challenges list
+	challenges, err := game.GetChallenges()
+	if err != nil {
 		return err
-	default:
-		return nil
 	}
+
+	workers := config.syncWorkers
+	if workers <= 0 {
+		workers = defaultSyncWorkers
+	}
+
+	// Process challenges in depends_on order with a bounded number of
+	// workers, collecting every error
+	var (
+		errs  []error
+		done  int
+		total = len(challengesConf)
+	)
+
+	config.events.publish(SyncStarted{Time: time.Now(), Game: game.Title(), Challenges: total})
+	pool := newSlotPool(workers, config.SyncPolicy)
+	runInDependencyOrder(context.Background(), challengesConf, pool, false, func(c ChallengeYaml) error {
+		return syncChallenge(config, game, c, challenges)
+	}, func(c ChallengeYaml, err error) {
+		done++
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Name, err))
+			log.Error("%s", log.Msg("sync.failed", done, total, c.Name, err))
+			config.events.publish(DeployFailed{Time: time.Now(), Challenge: c.Name, Err: err})
+		} else {
+			log.Info("%s", log.Msg("sync.synced", done, total, c.Name))
+			config.events.publish(ChallengeSynced{Time: time.Now(), Challenge: c.Name, Done: done, Total: total})
+		}
+	})
+
+	config.events.publish(SyncFinished{Time: time.Now(), Failed: len(errs), Total: total})
+	if err := saveAPILatency(); err != nil {
+		log.ErrorH2("Failed to save API latency: %v", err)
+	}
+	return errors.Join(errs...)
 }
 
 // MustInit initializes GZ or fatally logs error
@@ -396,9 +507,16 @@ func (gz *GZ) MustCreateTeams(url string, sendEmail bool) {
 	}
 }
 
-// MustDeleteAllUser removes all users or fatally logs error
-func (gz *GZ) MustDeleteAllUser() {
-	if err := gz.DeleteAllUser(); err != nil {
-		log.Fatal("User deletion failed: ", err)
+// MustImportGame imports a game into dir or fatally logs error
+func (gz *GZ) MustImportGame(title string, dir string) {
+	if err := gz.ImportGame(title, dir); err != nil {
+		log.Fatal("Game import failed: ", err)
+	}
+}
+
+// MustSetGameWriteup updates the game writeup settings or fatally logs error
+func (gz *GZ) MustSetGameWriteup(form GameWriteupForm) {
+	if err := gz.SetGameWriteup(form); err != nil {
+		log.Fatal("Game update failed: ", err)
 	}
 }
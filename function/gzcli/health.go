@@ -0,0 +1,241 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+const (
+	healthLogFile          = "health.jsonl"
+	healthRestartScript    = "restart"
+	defaultHealthInterval  = 30 * time.Second
+	defaultHealthTimeout   = 10 * time.Second
+	defaultHealthFailLimit = 3
+)
+
+// Healthcheck probes a deployed challenge with a command, a TCP connect or
+// an HTTP request. Retries consecutive failures trigger the restart script
+type Healthcheck struct {
+	Command  string `yaml:"command,omitempty"`
+	TCP      string `yaml:"tcp,omitempty"`
+	HTTP     string `yaml:"http,omitempty"`
+	Interval string `yaml:"interval,omitempty"`
+	Timeout  string `yaml:"timeout,omitempty"`
+	Retries  int    `yaml:"retries,omitempty"`
+}
+
+// HealthResult is the outcome of one probe
+type HealthResult struct {
+	Time       time.Time `json:"time"`
+	Challenge  string    `json:"challenge"`
+	Healthy    bool      `json:"healthy"`
+	DurationMs int64     `json:"durationMs"`
+	Error      string    `json:"error,omitempty"`
+}
+
+func (h *Healthcheck) interval() (time.Duration, error) {
+	return parseHealthDuration(h.Interval, defaultHealthInterval)
+}
+
+func (h *Healthcheck) timeout() (time.Duration, error) {
+	return parseHealthDuration(h.Timeout, defaultHealthTimeout)
+}
+
+func (h *Healthcheck) retries() int {
+	if h.Retries <= 0 {
+		return defaultHealthFailLimit
+	}
+	return h.Retries
+}
+
+func parseHealthDuration(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration %s must be positive", value)
+	}
+	return d, nil
+}
+
+// probe runs the healthcheck of a challenge once
+func probe(ctx context.Context, challengeConf ChallengeYaml) HealthResult {
+	result := HealthResult{Time: time.Now(), Challenge: challengeConf.Name}
+	check := challengeConf.Healthcheck
+
+	timeout, err := check.timeout()
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid timeout: %v", err)
+		return result
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	switch {
+	case check.Command != "":
+		cmd := exec.CommandContext(ctx, shell, "-c", check.Command)
+		cmd.Dir = challengeConf.Cwd
+		if output, cmdErr := cmd.CombinedOutput(); cmdErr != nil {
+			err = fmt.Errorf("%w: %s", cmdErr, strings.TrimSpace(string(output)))
+		}
+	case check.TCP != "":
+		var conn net.Conn
+		if conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", check.TCP); err == nil {
+			conn.Close()
+		}
+	case check.HTTP != "":
+		var req *http.Request
+		if req, err = http.NewRequestWithContext(ctx, http.MethodGet, check.HTTP, nil); err == nil {
+			var resp *http.Response
+			if resp, err = http.DefaultClient.Do(req); err == nil {
+				resp.Body.Close()
+				if resp.StatusCode >= 400 {
+					err = fmt.Errorf("http status %d", resp.StatusCode)
+				}
+			}
+		}
+	default:
+		err = fmt.Errorf("healthcheck needs command, tcp or http")
+	}
+
+	result.DurationMs = time.Since(result.Time).Milliseconds()
+	result.Healthy = err == nil
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+func healthcheckedChallenges() ([]ChallengeYaml, error) {
+	config, err := GetConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var checked []ChallengeYaml
+	for _, challengeConf := range challengesConf {
+		if challengeConf.Healthcheck != nil {
+			checked = append(checked, challengeConf)
+		}
+	}
+	return checked, nil
+}
+
+// RunHealthchecks probes every challenge with a healthcheck block once
+func RunHealthchecks(ctx context.Context) ([]HealthResult, error) {
+	challengesConf, err := healthcheckedChallenges()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]HealthResult, len(challengesConf))
+	var wg sync.WaitGroup
+	for i, challengeConf := range challengesConf {
+		wg.Add(1)
+		go func(i int, challengeConf ChallengeYaml) {
+			defer wg.Done()
+			results[i] = probe(ctx, challengeConf)
+		}(i, challengeConf)
+	}
+	wg.Wait()
+
+	for _, result := range results {
+		if err := recordHealth(result); err != nil {
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// WatchHealth probes every challenge on its own interval until ctx is
+// cancelled and runs the restart script after too many consecutive failures
+func WatchHealth(ctx context.Context) error {
+	challengesConf, err := healthcheckedChallenges()
+	if err != nil {
+		return err
+	}
+	if len(challengesConf) == 0 {
+		return fmt.Errorf("no challenge has a healthcheck")
+	}
+
+	var wg sync.WaitGroup
+	for _, challengeConf := range challengesConf {
+		interval, err := challengeConf.Healthcheck.interval()
+		if err != nil {
+			return fmt.Errorf("healthcheck interval of %s: %w", challengeConf.Name, err)
+		}
+
+		wg.Add(1)
+		go func(challengeConf ChallengeYaml, interval time.Duration) {
+			defer wg.Done()
+			watchChallengeHealth(ctx, challengeConf, interval)
+		}(challengeConf, interval)
+	}
+	log.Info("Watching health of %d challenges", len(challengesConf))
+	wg.Wait()
+	return nil
+}
+
+func watchChallengeHealth(ctx context.Context, challengeConf ChallengeYaml, interval time.Duration) {
+	// interval comes from parseHealthDuration, which rejects non-positive
+	// durations
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	failures := 0
+	for {
+		result := probe(ctx, challengeConf)
+		if ctx.Err() != nil {
+			return
+		}
+		if err := recordHealth(result); err != nil {
+			log.Error("Failed to record health of %s: %v", challengeConf.Name, err)
+		}
+
+		if result.Healthy {
+			if failures > 0 {
+				log.Info("%s is healthy again", challengeConf.Name)
+			}
+			failures = 0
+		} else {
+			failures++
+			log.Error("%s is unhealthy (%d/%d): %s", challengeConf.Name, failures, challengeConf.Healthcheck.retries(), result.Error)
+			if failures >= challengeConf.Healthcheck.retries() {
+				failures = 0
+				if challengeScript(challengeConf, healthRestartScript) == "" {
+					log.ErrorH2("%s has no %s script", challengeConf.Name, healthRestartScript)
+				} else if err := runScript(challengeConf, healthRestartScript); err != nil {
+					log.ErrorH2("Restart of %s failed: %v", challengeConf.Name, err)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recordHealth appends a probe result to the health log
+func recordHealth(result HealthResult) error {
+	return appendJSONL(filepath.Join(cacheDir, healthLogFile), result)
+}
@@ -22,7 +22,7 @@ func findCurrentGame(games []*gzapi.Game, title string, api *gzapi.GZAPI) *gzapi
 }
 
 func createNewGame(config *Config, api *gzapi.GZAPI) (*gzapi.Game, error) {
-	log.Info("Create new game")
+	log.Info("%s", log.Msg("sync.game.create"))
 	event := gzapi.CreateGameForm{
 		Title: config.Event.Title,
 		Start: config.Event.Start.Time,
@@ -36,7 +36,7 @@ func createNewGame(config *Config, api *gzapi.GZAPI) (*gzapi.Game, error) {
 		return nil, fmt.Errorf("poster is required")
 	}
 
-	poster, err := createPosterIfNotExistOrDifferent(config.Event.Poster, game, api)
+	poster, err := createPosterIfNotExistOrDifferent(config, config.Event.Poster, game, api)
 	if err != nil {
 		return nil, err
 	}
@@ -47,20 +47,20 @@ func createNewGame(config *Config, api *gzapi.GZAPI) (*gzapi.Game, error) {
 	if err := game.Update(&config.Event); err != nil {
 		return nil, err
 	}
-	if err := setCache("config", config); err != nil {
+	if err := setRemoteCache(config.configCacheKey(), &Config{Event: config.Event}); err != nil {
 		return nil, err
 	}
 	return game, nil
 }
 
 func updateGameIfNeeded(config *Config, currentGame *gzapi.Game, api *gzapi.GZAPI) error {
-	poster, err := createPosterIfNotExistOrDifferent(config.Event.Poster, currentGame, api)
+	poster, err := createPosterIfNotExistOrDifferent(config, config.Event.Poster, currentGame, api)
 	if err != nil {
 		return err
 	}
 	config.Event.Poster = poster
 	if fmt.Sprintf("%v", config.Event) != fmt.Sprintf("%v", *currentGame) {
-		log.Info("Updated %s game", config.Event.Title)
+		log.Info("%s", log.Msg("sync.game.update", config.Event.Title))
 
 		config.Event.Id = currentGame.Id
 		config.Event.PublicKey = currentGame.PublicKey
@@ -68,7 +68,7 @@ func updateGameIfNeeded(config *Config, currentGame *gzapi.Game, api *gzapi.GZAP
 		if err := currentGame.Update(&config.Event); err != nil {
 			return err
 		}
-		if err := setCache("config", config); err != nil {
+		if err := setRemoteCache(config.configCacheKey(), &Config{Event: config.Event}); err != nil {
 			return err
 		}
 	}
@@ -76,50 +76,45 @@ func updateGameIfNeeded(config *Config, currentGame *gzapi.Game, api *gzapi.GZAP
 }
 
 func validateChallenges(challengesConf []ChallengeYaml) error {
-	// Track seen names and duplicate occurrences
-	seenNames := make(map[string]int, len(challengesConf))
-	var duplicates []string
-
-	// First pass: count occurrences
-	for _, challengeConf := range challengesConf {
-		seenNames[challengeConf.Name]++
+	if err := validateDependencies(challengesConf); err != nil {
+		return err
 	}
-
-	// Collect names with duplicates
-	for name, count := range seenNames {
-		if count > 1 {
-			duplicates = append(duplicates, name)
+	for _, challengeConf := range challengesConf {
+		if err := validateChallenge(challengeConf); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	// Return all duplicates at once
-	if len(duplicates) > 0 {
-		return fmt.Errorf("multiple challenges with the same name found:\n  - %s",
-			strings.Join(duplicates, "\n  - "))
+// validateChallenge checks the fields of a single challenge
+func validateChallenge(challengeConf ChallengeYaml) error {
+	if challengeConf.Type == "" {
+		challengeConf.Type = "StaticAttachments"
 	}
-
-	// Existing validation logic
-	for _, challengeConf := range challengesConf {
-		if challengeConf.Type == "" {
-			challengeConf.Type = "StaticAttachments"
-		}
-		log.Info("Validating %s challenge...", challengeConf.Cwd)
-		if err := isGoodChallenge(challengeConf); err != nil {
-			return fmt.Errorf("invalid challenge %q: %w", challengeConf.Name, err)
-		}
-		log.Info("Challenge %s is valid.", challengeConf.Cwd)
+	log.Info("Validating %s challenge...", challengeConf.Cwd)
+	if err := isGoodChallenge(challengeConf); err != nil {
+		return fmt.Errorf("invalid challenge %q: %w", challengeConf.Name, err)
 	}
-
+	if err := validateHints(challengeConf.Hints); err != nil {
+		return fmt.Errorf("invalid challenge %q: %w", challengeConf.Name, err)
+	}
+	log.Info("Challenge %s is valid.", challengeConf.Cwd)
 	return nil
 }
 
-func syncChallenge(config *Config, challengeConf ChallengeYaml, challenges []gzapi.Challenge, api *gzapi.GZAPI) error {
+func syncChallenge(config *Config, game gzapi.GameHandle, challengeConf ChallengeYaml, challenges []gzapi.Challenge) error {
+	api := game.API()
 	var challengeData *gzapi.Challenge
 	var err error
 
+	if err = resolveChallengeSecrets(&challengeConf); err != nil {
+		return fmt.Errorf("%s: %w", challengeConf.Name, err)
+	}
+
 	if !isChallengeExist(challengeConf.Name, challenges) {
-		log.Info("Create challenge %s", challengeConf.Name)
-		challengeData, err = config.Event.CreateChallenge(gzapi.CreateChallengeForm{
+		log.Info("%s", log.Msg("sync.challenge.create", challengeConf.Name))
+		challengeData, err = game.CreateChallenge(gzapi.CreateChallengeForm{
 			Title:    challengeConf.Name,
 			Category: challengeConf.Category,
 			Tag:      challengeConf.Category,
@@ -129,9 +124,9 @@ func syncChallenge(config *Config, challengeConf ChallengeYaml, challenges []gza
 			return fmt.Errorf("create challenge %s: %v", challengeConf.Name, err)
 		}
 	} else {
-		log.Info("Update challenge %s", challengeConf.Name)
-		if err = GetCache(challengeConf.Category+"/"+challengeConf.Name+"/challenge", &challengeData); err != nil {
-			challengeData, err = config.Event.GetChallenge(challengeConf.Name)
+		log.Info("%s", log.Msg("sync.challenge.update", challengeConf.Name))
+		if err = GetCache(challengeCacheKey(config, challengeConf), &challengeData); err != nil {
+			challengeData, err = game.GetChallenge(challengeConf.Name)
 			if err != nil {
 				return fmt.Errorf("get challenge %s: %v", challengeConf.Name, err)
 			}
@@ -139,47 +134,73 @@ func syncChallenge(config *Config, challengeConf ChallengeYaml, challenges []gza
 		// fix bug nill pointer because cache didn't return gzapi
 		challengeData.CS = api
 	}
-	err = handleChallengeAttachments(challengeConf, challengeData, api)
+	err = handleChallengeAttachments(config, challengeConf, challengeData, api)
 	if err != nil {
 		return err
 	}
 
-	err = updateChallengeFlags(config, challengeConf, challengeData)
+	err = updateChallengeFlags(game, challengeConf, challengeData)
 	if err != nil {
 		return fmt.Errorf("update flags for %s: %v", challengeConf.Name, err)
 	}
 
-	challengeData = mergeChallengeData(&challengeConf, challengeData)
-	if isConfigEdited(&challengeConf, challengeData) {
-		if challengeData, err = challengeData.Update(*challengeData); err != nil {
-			log.ErrorH2("Update failed %s", err.Error())
-			if strings.Contains(err.Error(), "404") {
-				challengeData, err = config.Event.GetChallenge(challengeConf.Name)
-				if err != nil {
-					return fmt.Errorf("get challenge %s: %v", challengeConf.Name, err)
-				}
-				challengeData, err = challengeData.Update(*challengeData)
-				if err != nil {
-					return fmt.Errorf("update challenge %s: %v", challengeConf.Name, err)
-				}
+	previousHints := challengeData.Hints
+	isNewChallenge := !isChallengeExist(challengeConf.Name, challenges)
+
+	before := *challengeData
+	challengeData = mergeChallengeData(&challengeConf, challengeData, game.Start())
+	if isConfigEdited(challengeCacheKey(config, challengeConf), challengeData) {
+		if err = updateChangedFields(before, challengeData); err != nil {
+			if !gzapi.IsNotFound(err) {
+				return fmt.Errorf("update challenge %s: %w", challengeConf.Name, err)
+			}
+			// The cached challenge is stale, refetch it and apply the config again
+			log.ErrorH2("Update failed %s, refetching challenge", err.Error())
+			challengeData, err = game.GetChallenge(challengeConf.Name)
+			if err != nil {
+				return fmt.Errorf("get challenge %s: %w", challengeConf.Name, err)
+			}
+			before = *challengeData
+			challengeData = mergeChallengeData(&challengeConf, challengeData, game.Start())
+			if err = updateChangedFields(before, challengeData); err != nil {
+				return fmt.Errorf("update challenge %s: %w", challengeConf.Name, err)
 			}
 		}
-		if challengeData == nil {
-			return fmt.Errorf("update challenge failed")
+		if err := verifyChallenge(challengeData); err != nil {
+			log.ErrorH2("%s", err.Error())
 		}
-		if err := setCache(challengeData.Category+"/"+challengeConf.Name+"/challenge", challengeData); err != nil {
+		if err := setRemoteCache(challengeCacheKey(config, challengeConf), challengeData); err != nil {
 			return err
 		}
+		if !isNewChallenge {
+			announceHints(config, game, challengeConf.Name, addedHints(previousHints, challengeData.Hints))
+		}
 	} else {
-		log.Info("Challenge %s is the same...", challengeConf.Name)
+		log.Info("%s", log.Msg("sync.challenge.unchanged", challengeConf.Name))
 	}
 	return nil
 }
 
-func handleChallengeAttachments(challengeConf ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI) error {
+// updateChangedFields sends only the fields the config changed since before,
+// the last synced or fetched state, so concurrent edits in the web UI to
+// other fields are not overwritten
+func updateChangedFields(before gzapi.Challenge, challengeData *gzapi.Challenge) error {
+	fields, err := gzapi.ChangedFields(before, *challengeData)
+	if err != nil {
+		return err
+	}
+	return challengeData.UpdateFields(fields)
+}
+
+// challengeCacheKey namespaces the cached challenge state by the game it was synced to
+func challengeCacheKey(config *Config, challengeConf ChallengeYaml) string {
+	return config.cachePrefix + challengeConf.Category + "/" + challengeConf.Name + "/challenge"
+}
+
+func handleChallengeAttachments(config *Config, challengeConf ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI) error {
 	if challengeConf.Provide != nil {
 		if strings.HasPrefix(*challengeConf.Provide, "http") {
-			log.Info("Create remote attachment for %s", challengeConf.Name)
+			log.Info("%s", log.Msg("sync.attachment.remote", challengeConf.Name))
 			if err := challengeData.CreateAttachment(gzapi.CreateAttachmentForm{
 				AttachmentType: "Remote",
 				RemoteUrl:      *challengeConf.Provide,
@@ -187,10 +208,10 @@ func handleChallengeAttachments(challengeConf ChallengeYaml, challengeData *gzap
 				return err
 			}
 		} else {
-			return handleLocalAttachment(challengeConf, challengeData, api)
+			return handleLocalAttachment(config, challengeConf, challengeData, api)
 		}
 	} else if challengeData.Attachment != nil {
-		log.Info("Delete attachment for %s", challengeConf.Name)
+		log.Info("%s", log.Msg("sync.attachment.delete", challengeConf.Name))
 		if err := challengeData.CreateAttachment(gzapi.CreateAttachmentForm{
 			AttachmentType: "None",
 		}); err != nil {
@@ -200,26 +221,64 @@ func handleChallengeAttachments(challengeConf ChallengeYaml, challengeData *gzap
 	return nil
 }
 
-func handleLocalAttachment(challengeConf ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI) error {
-	log.Info("Create local attachment for %s", challengeConf.Name)
-	zipFilename := NormalizeFileName(*challengeConf.Provide) + ".zip"
-	zipOutput := filepath.Join(challengeConf.Cwd, zipFilename)
-	if info, err := os.Stat(filepath.Join(challengeConf.Cwd, *challengeConf.Provide)); err != nil || info.IsDir() {
-		log.Info("Zip attachment for %s", challengeConf.Name)
-		zipInput := filepath.Join(challengeConf.Cwd, *challengeConf.Provide)
-		if err := zipSource(zipInput, zipOutput); err != nil {
+// handleLocalAttachment uploads the provided files unless their content is
+// the same as on the last sync and the platform still has the attachment
+func handleLocalAttachment(config *Config, challengeConf ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI) error {
+	attachmentType := "Local"
+	if config.CDN != nil {
+		attachmentType = "Remote"
+	}
+
+	source := filepath.Join(challengeConf.Cwd, *challengeConf.Provide)
+	if err := ensureNoLFSPointers(config, challengeConf, source); err != nil {
+		return err
+	}
+
+	cacheKey := challengeCacheKey(config, challengeConf)
+	contentHash, err := attachmentContentHash(source)
+	if err != nil {
+		return err
+	}
+	if entry, ok := cachedAttachment(cacheKey); ok &&
+		entry.ContentHash == contentHash && entry.AttachmentType == attachmentType &&
+		challengeData.Attachment != nil && challengeData.Attachment.Type == attachmentType {
+		log.Info("%s", log.Msg("sync.attachment.cached", challengeConf.Name))
+		return nil
+	}
+
+	if err := uploadLocalAttachment(config, challengeConf, challengeData, api); err != nil {
+		return err
+	}
+	return saveAttachmentCache(cacheKey, attachmentCacheEntry{
+		ContentHash:    contentHash,
+		AttachmentType: attachmentType,
+	})
+}
+
+func uploadLocalAttachment(config *Config, challengeConf ChallengeYaml, challengeData *gzapi.Challenge, api *gzapi.GZAPI) error {
+	log.Info("%s", log.Msg("sync.attachment.local", challengeConf.Name))
+	attachment := filepath.Join(challengeConf.Cwd, *challengeConf.Provide)
+	if info, err := os.Stat(attachment); err != nil || info.IsDir() {
+		log.Info("%s", log.Msg("sync.attachment.zip", challengeConf.Name))
+		zipFilename := NormalizeFileName(*challengeConf.Provide) + ".zip"
+		if attachment, err = zipChallengeSource(challengeConf, attachment, zipFilename); err != nil {
 			return err
 		}
-		challengeConf.Provide = &zipFilename
 	}
-	fileinfo, err := createAssetsIfNotExistOrDifferent(filepath.Join(challengeConf.Cwd, *challengeConf.Provide), api)
+	if err := checkAttachmentBudget(config, challengeConf, attachment); err != nil {
+		return err
+	}
+	if config.CDN != nil {
+		return mirrorAttachmentToCDN(config, challengeConf, challengeData, attachment)
+	}
+	fileinfo, err := createAssetsIfNotExistOrDifferent(config, attachment, api)
 	if err != nil {
 		return err
 	}
 	if challengeData.Attachment != nil && strings.Contains(challengeData.Attachment.Url, fileinfo.Hash) {
-		log.Info("Attachment for %s is the same...", challengeConf.Name)
+		log.Info("%s", log.Msg("sync.attachment.unchanged", challengeConf.Name))
 	} else {
-		log.Info("Update attachment for %s", challengeConf.Name)
+		log.Info("%s", log.Msg("sync.attachment.update", challengeConf.Name))
 		if err := challengeData.CreateAttachment(gzapi.CreateAttachmentForm{
 			AttachmentType: "Local",
 			FileHash:       fileinfo.Hash,
@@ -227,16 +286,15 @@ func handleLocalAttachment(challengeConf ChallengeYaml, challengeData *gzapi.Cha
 			return err
 		}
 	}
-	os.Remove(zipOutput)
 	return nil
 }
 
-func updateChallengeFlags(config *Config, challengeConf ChallengeYaml, challengeData *gzapi.Challenge) error {
+func updateChallengeFlags(game gzapi.GameHandle, challengeConf ChallengeYaml, challengeData *gzapi.Challenge) error {
 	for _, flag := range challengeData.Flags {
 		if !isExistInArray(flag.Flag, challengeConf.Flags) {
-			flag.GameId = config.Event.Id
+			flag.GameId = game.Id()
 			flag.ChallengeId = challengeData.Id
-			flag.CS = config.Event.CS
+			flag.CS = game.API()
 			if err := flag.Delete(); err != nil {
 				return err
 			}
@@ -270,16 +328,21 @@ func updateChallengeFlags(config *Config, challengeConf ChallengeYaml, challenge
 var shell = os.Getenv("SHELL")
 
 func runScript(challengeConf ChallengeYaml, script string) error {
-	if challengeConf.Scripts[script] == "" {
+	command := challengeScript(challengeConf, script)
+	if command == "" {
 		return nil
 	}
-	log.InfoH2("Running:\n%s", challengeConf.Scripts[script])
-	return runShell(challengeConf.Scripts[script], challengeConf.Cwd)
+	log.InfoH2("Running:\n%s", command)
+	return runChallengeShell(challengeConf, command, nil)
 }
 
-func runShell(script string, cwd string) error {
+// runShellWithEnv runs script in the host shell with extra environment variables
+func runShellWithEnv(script string, cwd string, env []string) error {
 	cmd := exec.Command(shell, "-c", script)
 	cmd.Dir = cwd
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -0,0 +1,208 @@
+package gzcli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// Hint is a hint of challenge.yml. A plain string is published right away,
+// {text, releaseAfter} only once releaseAfter has passed since the game start
+type Hint struct {
+	Text         string `yaml:"text"`
+	ReleaseAfter string `yaml:"releaseAfter,omitempty"`
+}
+
+func (h *Hint) UnmarshalYAML(unmarshal func(any) error) error {
+	var text string
+	if err := unmarshal(&text); err == nil {
+		*h = Hint{Text: text}
+		return nil
+	}
+	type plain Hint
+	return unmarshal((*plain)(h))
+}
+
+func (h Hint) MarshalYAML() (any, error) {
+	if h.ReleaseAfter == "" {
+		return h.Text, nil
+	}
+	type plain Hint
+	return plain(h), nil
+}
+
+func (h Hint) delay() (time.Duration, error) {
+	if h.ReleaseAfter == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(h.ReleaseAfter)
+	if err != nil {
+		return 0, fmt.Errorf("invalid releaseAfter of hint %q: %w", h.Text, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("releaseAfter of hint %q must not be negative", h.Text)
+	}
+	return d, nil
+}
+
+func plainHints(hints []string) []Hint {
+	var result []Hint
+	for _, hint := range hints {
+		result = append(result, Hint{Text: hint})
+	}
+	return result
+}
+
+func validateHints(hints []Hint) error {
+	for _, hint := range hints {
+		if _, err := hint.delay(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releasedHints returns the text of the hints due at now for a game that
+// started at start, in challenge.yml order. Timed hints are held back while
+// the start is unknown
+func releasedHints(hints []Hint, start, now time.Time) []string {
+	released := []string{}
+	for _, hint := range hints {
+		if hint.ReleaseAfter != "" && start.IsZero() {
+			continue
+		}
+		if d, err := hint.delay(); err == nil && !now.Before(start.Add(d)) {
+			released = append(released, hint.Text)
+		}
+	}
+	return released
+}
+
+func hasTimedHints(hints []Hint) bool {
+	for _, hint := range hints {
+		if hint.ReleaseAfter != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleaseHints publishes the timed hints that are due, then with watch set
+// keeps doing so every interval until ctx is cancelled. Every released hint
+// is announced like a synced one and recorded in the oplog
+func (gz *GZ) ReleaseHints(ctx context.Context, interval time.Duration, watch bool) error {
+	if !watch {
+		return gz.releaseDueHints()
+	}
+	if interval <= 0 {
+		return fmt.Errorf("interval %s must be positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := gz.releaseDueHints(); err != nil {
+			log.Error("Failed to release hints: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (gz *GZ) releaseDueHints() error {
+	config, err := lookupConfig(gz.api)
+	if err != nil {
+		return err
+	}
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return err
+	}
+	game, err := gz.api.GetGameById(config.Event.Id)
+	if err != nil {
+		return err
+	}
+	handle := game.Handle()
+
+	var timed []ChallengeYaml
+	for _, challengeConf := range challengesConf {
+		if hasTimedHints(challengeConf.Hints) {
+			timed = append(timed, challengeConf)
+		}
+	}
+	if len(timed) == 0 {
+		return nil
+	}
+	challenges, err := handle.GetChallenges()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, challengeConf := range timed {
+		if err := validateHints(challengeConf.Hints); err != nil {
+			log.Error("%s: %v", challengeConf.Name, err)
+			continue
+		}
+		var challenge *gzapi.Challenge
+		for i := range challenges {
+			if challenges[i].Title == challengeConf.Name {
+				challenge = &challenges[i]
+				break
+			}
+		}
+		if challenge == nil {
+			log.ErrorH2("%s is not synced yet, skipping its hints", challengeConf.Name)
+			continue
+		}
+
+		due := releasedHints(challengeConf.Hints, handle.Start(), now)
+		added := addedHints(challenge.Hints, due)
+		if len(added) == 0 {
+			continue
+		}
+		value, err := json.Marshal(due)
+		if err != nil {
+			return err
+		}
+		log.Info("Release %d hints of %s", len(added), challengeConf.Name)
+		if err := challenge.UpdateFields(map[string]json.RawMessage{"hints": value}); err != nil {
+			log.Error("Failed to release hints of %s: %v", challengeConf.Name, err)
+			continue
+		}
+		if err := cacheReleasedHints(config, challengeConf, due); err != nil {
+			return err
+		}
+		announceHints(config, handle, challengeConf.Name, added)
+		for _, hint := range added {
+			if err := recordOperation("hint.release", map[string]string{
+				"challenge": challengeConf.Name,
+				"hint":      hint,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// cacheReleasedHints writes hints into the cached state of a challenge, so
+// the next sync does not take the released hints as new and announce them
+// again. Without cached state sync reads the challenge from the platform
+func cacheReleasedHints(config *Config, challengeConf ChallengeYaml, hints []string) error {
+	key := challengeCacheKey(config, challengeConf)
+	var cached gzapi.Challenge
+	if err := GetCache(key, &cached); err != nil {
+		return nil
+	}
+	cached.Hints = hints
+	return setRemoteCache(key, &cached)
+}
@@ -0,0 +1,34 @@
+package gzcli
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestReleasedHints(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	hints := []Hint{
+		{Text: "always"},
+		{Text: "hour", ReleaseAfter: "1h"},
+		{Text: "day", ReleaseAfter: "24h"},
+		{Text: "invalid", ReleaseAfter: "soon"},
+	}
+	for _, tt := range []struct {
+		name  string
+		start time.Time
+		now   time.Time
+		want  []string
+	}{
+		{"unknown start", time.Time{}, start.Add(48 * time.Hour), []string{"always"}},
+		{"at start", start, start, []string{"always"}},
+		{"exactly due", start, start.Add(time.Hour), []string{"always", "hour"}},
+		{"all due", start, start.Add(48 * time.Hour), []string{"always", "hour", "day"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := releasedHints(hints, tt.start, tt.now); !slices.Equal(got, tt.want) {
+				t.Fatalf("released = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
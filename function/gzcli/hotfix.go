@@ -0,0 +1,164 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// Hotfix describes a fix shipped to a single challenge during an event
+type Hotfix struct {
+	Challenge string
+	Message   string
+	NoPull    bool
+	Timeout   time.Duration
+}
+
+// Hotfix pulls the repository, syncs only the fixed challenge, restarts it
+// when it has a restart script, waits until it is ready, announces the fix
+// and records it in the oplog
+func (gz *GZ) Hotfix(ctx context.Context, hotfix Hotfix) error {
+	if hotfix.Challenge == "" || hotfix.Message == "" {
+		return fmt.Errorf("a challenge and a message are required for a hotfix")
+	}
+
+	var pulled string
+	if !hotfix.NoPull {
+		log.Info("Pull the repository")
+		output, err := runGit("pull", "--ff-only")
+		if err != nil {
+			return err
+		}
+		log.InfoH2("%s", output)
+		pulled = output
+	}
+	revision, err := runGit("rev-parse", "--short", "HEAD")
+	if err != nil {
+		return err
+	}
+	if !hotfix.NoPull {
+		gz.events.publish(GitPulled{Time: time.Now(), Revision: revision, Output: pulled})
+	}
+
+	config, err := GetConfig(gz.api)
+	if err != nil {
+		return err
+	}
+	config.events = &gz.events
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return err
+	}
+	var challengeConf *ChallengeYaml
+	for i := range challengesConf {
+		if challengesConf[i].Name == hotfix.Challenge {
+			challengeConf = &challengesConf[i]
+			break
+		}
+	}
+	if challengeConf == nil {
+		return fmt.Errorf("challenge %q not found", hotfix.Challenge)
+	}
+	// depends_on may name any challenge, so dependencies are checked against
+	// the whole repository and only the fixed challenge itself in full
+	if err := validateDependencies(challengesConf); err != nil {
+		return err
+	}
+	if err := validateChallenge(*challengeConf); err != nil {
+		return err
+	}
+
+	games, err := gz.api.GetGames()
+	if err != nil {
+		return err
+	}
+	currentGame := findCurrentGame(games, config.Event.Title, gz.api)
+	if currentGame == nil {
+		return fmt.Errorf("game %q not found", config.Event.Title)
+	}
+	game := currentGame.Handle()
+
+	log.Info("Sync %s at %s", challengeConf.Name, revision)
+	if err := syncChallenges(config, game, []ChallengeYaml{*challengeConf}); err != nil {
+		return err
+	}
+	if challengeScript(*challengeConf, healthRestartScript) != "" {
+		log.Info("Restart %s", challengeConf.Name)
+		if err := runScript(*challengeConf, healthRestartScript); err != nil {
+			err = fmt.Errorf("restart %s: %w", challengeConf.Name, err)
+			gz.events.publish(DeployFailed{Time: time.Now(), Challenge: challengeConf.Name, Err: err})
+			return err
+		}
+	}
+	if err := waitChallengeReady(ctx, game, *challengeConf, hotfix.Timeout); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("Hotfix for %s: %s", challengeConf.Name, hotfix.Message)
+	log.Info("Announce hotfix for %s", challengeConf.Name)
+	if _, err := game.CreateNotice(gzapi.NoticeForm{Content: message}); err != nil {
+		log.ErrorH2("Failed to create notice for %s: %v", challengeConf.Name, err)
+	}
+	if config.Announce != nil && config.Announce.Webhook != "" {
+		if err := sendWebhook(config.Announce.Webhook, message); err != nil {
+			log.ErrorH2("Failed to send webhook for %s: %v", challengeConf.Name, err)
+		}
+	}
+
+	return recordOperation("hotfix", map[string]string{
+		"challenge": challengeConf.Name,
+		"message":   hotfix.Message,
+		"revision":  revision,
+	})
+}
+
+// waitChallengeReady waits until the challenge is enabled on the platform
+// and, when it has a healthcheck, until the healthcheck passes
+func waitChallengeReady(ctx context.Context, game gzapi.GameHandle, challengeConf ChallengeYaml, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	interval := 5 * time.Second
+	if challengeConf.Healthcheck != nil {
+		if d, err := challengeConf.Healthcheck.interval(); err == nil && d < interval {
+			interval = d
+		}
+	}
+
+	log.Info("Wait for %s to be ready", challengeConf.Name)
+	for {
+		lastErr := challengeReady(ctx, game, challengeConf)
+		if lastErr == nil {
+			log.InfoH2("%s is ready", challengeConf.Name)
+			return nil
+		}
+		log.InfoH2("%s is not ready yet: %v", challengeConf.Name, lastErr)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not ready after %s: %w", challengeConf.Name, timeout, lastErr)
+		case <-time.After(interval):
+		}
+	}
+}
+
+func challengeReady(ctx context.Context, game gzapi.GameHandle, challengeConf ChallengeYaml) error {
+	challengeData, err := game.GetChallenge(challengeConf.Name)
+	if err != nil {
+		return err
+	}
+	if challengeConf.Visible != nil && *challengeConf.Visible &&
+		(challengeData.IsEnabled == nil || !*challengeData.IsEnabled) {
+		return fmt.Errorf("challenge is not enabled on the platform")
+	}
+	if challengeConf.Healthcheck == nil {
+		return nil
+	}
+	if result := probe(ctx, challengeConf); !result.Healthy {
+		return fmt.Errorf("healthcheck failed: %s", result.Error)
+	}
+	return nil
+}
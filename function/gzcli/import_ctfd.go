@@ -0,0 +1,123 @@
+package gzcli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/dimasma0305/ctfify/function/scraper/ctfd"
+	"gopkg.in/yaml.v2"
+)
+
+// ImportCTFd downloads every challenge of a CTFd instance and writes them
+// into dir using the same layout that Sync reads from. Flags and locked hints
+// are only available when creds belong to a CTFd admin. CTFd does not record
+// authors, so every challenge is credited to author
+func ImportCTFd(url string, creds *ctfd.Creds, author, dir string) error {
+	if author == "" {
+		return fmt.Errorf("an author is required, CTFd does not record one")
+	}
+	scraper, err := ctfd.Init(url, creds)
+	if err != nil {
+		return err
+	}
+
+	challenges, err := scraper.GetChallenges()
+	if err != nil {
+		return err
+	}
+
+	for _, challenge := range challenges {
+		fullInfo, err := challenge.GetFullInfo()
+		if err != nil {
+			return fmt.Errorf("get challenge %s: %w", challenge.Name, err)
+		}
+		if err := importCTFdChallenge(fullInfo, author, dir); err != nil {
+			return fmt.Errorf("import challenge %s: %w", challenge.Name, err)
+		}
+	}
+	return nil
+}
+
+func importCTFdChallenge(challenge *ctfd.ChallengeFullInfo, author, dir string) error {
+	challengeConf := ChallengeYaml{
+		Name:        challenge.Name,
+		Author:      author,
+		Description: challenge.Description,
+		Value:       challenge.Value,
+		Type:        "StaticAttachment",
+		Category:    ctfdCategory(challenge.Category),
+	}
+	if challenge.Connection_Info != "" {
+		challengeConf.Description += "\n\n" + challenge.Connection_Info
+	}
+
+	challengeDir := filepath.Join(dir, challengeConf.Category, importDirName(challengeConf.Name))
+	challengeFile := filepath.Join(challengeDir, "challenge.yml")
+	if _, err := os.Stat(challengeFile); err == nil {
+		log.InfoH2("Challenge %s already exists, skipping", challengeConf.Name)
+		return nil
+	}
+
+	for _, sub := range []string{"dist", "src", "solver"} {
+		if err := os.MkdirAll(filepath.Join(challengeDir, sub), 0755); err != nil {
+			return err
+		}
+	}
+
+	flags, err := challenge.GetFlags()
+	if err != nil {
+		log.ErrorH2("Cannot read flags of %s, using a placeholder: %v", challengeConf.Name, err)
+	}
+	for _, flag := range flags {
+		if flag.Type != "static" {
+			log.ErrorH2("Skip %s flag of %s, only static flags can be imported", flag.Type, challengeConf.Name)
+			continue
+		}
+		challengeConf.Flags = append(challengeConf.Flags, flag.Content)
+	}
+	if len(challengeConf.Flags) == 0 {
+		challengeConf.Flags = []string{flagPlaceholder}
+	}
+
+	for _, hint := range challenge.Hints {
+		content, err := hint.GetContent()
+		if err != nil || content == "" {
+			log.ErrorH2("Cannot read hint %d of %s, it is locked", hint.Id, challengeConf.Name)
+			continue
+		}
+		challengeConf.Hints = append(challengeConf.Hints, Hint{Text: content})
+	}
+
+	if len(challenge.Files) > 0 {
+		log.InfoH2("Download %d attachments for %s", len(challenge.Files), challengeConf.Name)
+		if err := challenge.DownloadFilesToDir(filepath.Join(challengeDir, "dist")); err != nil {
+			return err
+		}
+		provide := "./dist"
+		if len(challenge.Files) == 1 {
+			provide += "/" + challenge.Files[0].FileName()
+		}
+		challengeConf.Provide = &provide
+	}
+
+	data, err := yaml.Marshal(challengeConf)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Import challenge %s into %s", challengeConf.Name, challengeDir)
+	return os.WriteFile(challengeFile, append([]byte(challengeSchemaHeader), data...), 0644)
+}
+
+// ctfdCategory maps a free-form CTFd category onto a gzcli category
+func ctfdCategory(category string) string {
+	for _, c := range CHALLENGE_CATEGORY {
+		if strings.EqualFold(c, strings.TrimSpace(category)) {
+			return c
+		}
+	}
+	return "Misc"
+}
@@ -0,0 +1,139 @@
+package gzcli
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	challengeSchemaHeader = "# yaml-language-server: $schema=https://raw.githubusercontent.com/dimasma0305/ctfify/refs/heads/master/function/template/templates/others/ctf-template/.gzctf/challenge.schema.yaml\n\n"
+	flagPlaceholder       = "flag{REPLACE_ME}"
+	gameHackingPrefix     = "[Game Hacking] "
+)
+
+var authorContentRegex = regexp.MustCompile(`^Author: \*\*(.*?)\*\*\n*`)
+
+// ImportGame pulls every challenge of an existing game and writes them into
+// dir using the same layout that Sync reads from
+func (gz *GZ) ImportGame(title string, dir string) error {
+	games, err := gz.api.GetGames()
+	if err != nil {
+		return err
+	}
+
+	game := findCurrentGame(games, title, gz.api)
+	if game == nil {
+		return fmt.Errorf("game %q not found", title)
+	}
+
+	challenges, err := game.GetChallenges()
+	if err != nil {
+		return err
+	}
+
+	for _, challenge := range challenges {
+		if err := importChallenge(challenge, dir); err != nil {
+			return fmt.Errorf("import challenge %s: %w", challenge.Title, err)
+		}
+	}
+	return nil
+}
+
+func importChallenge(challenge gzapi.Challenge, dir string) error {
+	challengeConf := challengeToYaml(challenge)
+
+	challengeDir := filepath.Join(dir, challengeConf.Category, importDirName(challengeConf.Name))
+	challengeFile := filepath.Join(challengeDir, "challenge.yml")
+	if _, err := os.Stat(challengeFile); err == nil {
+		log.InfoH2("Challenge %s already exists, skipping", challengeConf.Name)
+		return nil
+	}
+
+	for _, sub := range []string{"dist", "src", "solver"} {
+		if err := os.MkdirAll(filepath.Join(challengeDir, sub), 0755); err != nil {
+			return err
+		}
+	}
+
+	if challenge.Attachment != nil {
+		switch challenge.Attachment.Type {
+		case "Remote":
+			challengeConf.Provide = &challenge.Attachment.Url
+		case "Local":
+			fileName := path.Base(challenge.Attachment.Url)
+			log.InfoH2("Download attachment %s", fileName)
+			if err := challenge.Attachment.Download(filepath.Join(challengeDir, "dist", fileName)); err != nil {
+				return err
+			}
+			provide := "./dist/" + fileName
+			challengeConf.Provide = &provide
+		}
+	}
+
+	data, err := yaml.Marshal(challengeConf)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Import challenge %s into %s", challengeConf.Name, challengeDir)
+	return os.WriteFile(challengeFile, append([]byte(challengeSchemaHeader), data...), 0644)
+}
+
+// challengeToYaml is the reverse of mergeChallengeData
+func challengeToYaml(challenge gzapi.Challenge) ChallengeYaml {
+	challengeConf := ChallengeYaml{
+		Name:     challenge.Title,
+		Type:     challenge.Type,
+		Value:    challenge.OriginalScore,
+		Hints:    plainHints(challenge.Hints),
+		Visible:  challenge.IsEnabled,
+		Category: challenge.Category,
+	}
+
+	if match := authorContentRegex.FindStringSubmatch(challenge.Content); match != nil {
+		challengeConf.Author = match[1]
+		challengeConf.Description = strings.TrimPrefix(challenge.Content, match[0])
+	} else {
+		challengeConf.Description = challenge.Content
+	}
+
+	if strings.HasPrefix(challengeConf.Name, gameHackingPrefix) {
+		challengeConf.Name = strings.TrimPrefix(challengeConf.Name, gameHackingPrefix)
+		challengeConf.Category = "Game Hacking"
+	} else if !isExistInArray(challengeConf.Category, CHALLENGE_CATEGORY) {
+		challengeConf.Category = "Misc"
+	}
+
+	for _, flag := range challenge.Flags {
+		challengeConf.Flags = append(challengeConf.Flags, flag.Flag)
+	}
+	if len(challengeConf.Flags) == 0 && strings.HasPrefix(challenge.Type, "Static") {
+		challengeConf.Flags = []string{flagPlaceholder}
+	}
+
+	if strings.HasSuffix(challenge.Type, "Container") {
+		challengeConf.Container = Container{
+			FlagTemplate:         challenge.FlagTemplate,
+			ContainerImage:       challenge.ContainerImage,
+			MemoryLimit:          challenge.MemoryLimit,
+			CpuCount:             challenge.CpuCount,
+			StorageLimit:         challenge.StorageLimit,
+			ContainerExposePort:  challenge.ContainerExposePort,
+			EnableTrafficCapture: challenge.EnableTrafficCapture,
+		}
+	}
+
+	return challengeConf
+}
+
+func importDirName(name string) string {
+	return strings.ReplaceAll(NormalizeFileName(name), " ", "-")
+}
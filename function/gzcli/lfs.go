@@ -0,0 +1,89 @@
+package gzcli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// lfsPointerPrefix starts every Git LFS pointer file. Pointers are small, so
+// only files up to lfsPointerMaxSize are read
+const (
+	lfsPointerPrefix  = "version https://git-lfs.github.com/spec/v1"
+	lfsPointerMaxSize = 1024
+)
+
+func isLFSPointer(path string, info os.FileInfo) (bool, error) {
+	if info.IsDir() || info.Size() > lfsPointerMaxSize || info.Size() < int64(len(lfsPointerPrefix)) {
+		return false, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	head := make([]byte, len(lfsPointerPrefix))
+	if _, err := io.ReadFull(f, head); err != nil {
+		return false, err
+	}
+	return bytes.Equal(head, []byte(lfsPointerPrefix)), nil
+}
+
+// lfsPointers lists the Git LFS pointer files under root, which may be a
+// single file
+func lfsPointers(root string) ([]string, error) {
+	var pointers []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		pointer, err := isLFSPointer(path, info)
+		if pointer {
+			pointers = append(pointers, path)
+		}
+		return err
+	})
+	return pointers, err
+}
+
+// ensureNoLFSPointers fails when the attachment source of a challenge holds
+// Git LFS pointers instead of the files. With gitLfsPull in conf.yaml the
+// pointers are pulled first
+func ensureNoLFSPointers(config *Config, challengeConf ChallengeYaml, source string) error {
+	pointers, err := lfsPointers(source)
+	if err != nil || len(pointers) == 0 {
+		return err
+	}
+
+	if config.GitLfsPull {
+		log.Info("Pull %d Git LFS files for %s", len(pointers), challengeConf.Name)
+		include := make([]string, len(pointers))
+		for i, pointer := range pointers {
+			rel, err := filepath.Rel(getWorkDir(), pointer)
+			if err != nil {
+				return err
+			}
+			include[i] = filepath.ToSlash(rel)
+		}
+		if _, err := runGit("lfs", "pull", "--include", strings.Join(include, ",")); err != nil {
+			return err
+		}
+		if pointers, err = lfsPointers(source); err != nil || len(pointers) == 0 {
+			return err
+		}
+	}
+
+	for i, pointer := range pointers {
+		if rel, err := filepath.Rel(challengeConf.Cwd, pointer); err == nil {
+			pointers[i] = rel
+		}
+	}
+	return fmt.Errorf("attachment of %s contains Git LFS pointers instead of files, run git lfs pull or set gitLfsPull in %s: %s",
+		challengeConf.Name, CONFIG_FILE, strings.Join(pointers, ", "))
+}
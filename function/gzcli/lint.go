@@ -0,0 +1,339 @@
+package gzcli
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LintIssue is a problem found in a challenge.yml, or in the _defaults.yml
+// or extends file it inherits the offending key from. Line is 0 when unknown
+type LintIssue struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+}
+
+var yamlErrorLineRegex = regexp.MustCompile(`line (\d+)`)
+
+// Lint validates every challenge.yml against the full ChallengeYaml schema and
+// reports problems with the line they occur on
+func Lint() ([]LintIssue, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var host, flagFormat string
+	config := &Config{}
+	if conf, err := GetConfig(nil); err == nil {
+		config = conf
+		if u, err := url.Parse(config.Url); err == nil {
+			host = u.Hostname()
+		}
+		flagFormat = config.FlagFormat
+	}
+
+	var flagRegex *regexp.Regexp
+	if flagFormat != "" {
+		if flagRegex, err = regexp.Compile(flagFormat); err != nil {
+			return nil, fmt.Errorf("invalid flagFormat in %s: %w", CONFIG_FILE, err)
+		}
+	}
+
+	var issues []LintIssue
+	for _, category := range config.challengeCategories() {
+		categoryPath := filepath.Join(config.challengeRootDir(dir), category)
+		if _, err := os.Stat(categoryPath); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.Walk(categoryPath, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !challengeFileRegex.MatchString(info.Name()) {
+				return err
+			}
+			rel, _ := filepath.Rel(dir, path)
+			fileIssues, err := lintChallengeFile(categoryPath, path, host, flagRegex)
+			if err != nil {
+				return err
+			}
+			for _, issue := range fileIssues {
+				if issue.File == "" {
+					issue.File = rel
+				} else {
+					source, _ := filepath.Rel(dir, issue.File)
+					issue.File = source
+					issue.Message += fmt.Sprintf(" (inherited by %s)", rel)
+				}
+				issues = append(issues, issue)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("category %s: %w", category, err)
+		}
+	}
+	return issues, nil
+}
+
+func lintChallengeFile(categoryPath string, path string, host string, flagRegex *regexp.Regexp) ([]LintIssue, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []LintIssue
+	// origins holds the _defaults.yml or extends file of inherited nodes,
+	// issues on the other nodes are in challenge.yml itself
+	origins := map[*yaml.Node]string{}
+	report := func(at *yaml.Node, format string, elem ...any) {
+		issues = append(issues, LintIssue{File: origins[at], Line: at.Line, Message: fmt.Sprintf(format, elem...)})
+	}
+	reportLine := func(line int, format string, elem ...any) {
+		report(&yaml.Node{Line: line}, format, elem...)
+	}
+
+	// Render the same template variables Sync does so {{.slug}} and {{.host}}
+	// do not read as yaml flow mappings
+	rendered := content
+	if t, err := template.New("chall").Parse(string(content)); err == nil {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, map[string]string{"host": host, "slug": "slug"}); err == nil {
+			rendered = buf.Bytes()
+		}
+	} else {
+		reportLine(0, "template error: %v", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(rendered, &doc); err != nil {
+		line := 0
+		if match := yamlErrorLineRegex.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+		}
+		reportLine(line, "%v", err)
+		return issues, nil
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		reportLine(1, "challenge must be a yaml mapping")
+		return issues, nil
+	}
+	root := doc.Content[0]
+	if categoryDefaultsFile(categoryPath) != "" || mappingFields(root)[extendsKey] != nil {
+		// Lint the merged challenge, every node keeps the line of its file
+		merged, _, err := applyChallengeDefaults(categoryPath, path, root, origins)
+		if err != nil {
+			reportLine(0, "%v", err)
+			return issues, nil
+		}
+		root = merged
+	}
+
+	fields := mappingFields(root)
+	lintUnknownKeys(root, reflect.TypeOf(ChallengeYaml{}), "", report)
+	if container, ok := fields["container"]; ok && container.Kind == yaml.MappingNode {
+		lintUnknownKeys(container, reflect.TypeOf(Container{}), "container.", report)
+	}
+
+	var challenge ChallengeYaml
+	if err := root.Decode(&challenge); err != nil {
+		line := root.Line
+		if match := yamlErrorLineRegex.FindStringSubmatch(err.Error()); match != nil {
+			line, _ = strconv.Atoi(match[1])
+		}
+		reportLine(line, "%v", err)
+		return issues, nil
+	}
+
+	nodeOf := func(key string) *yaml.Node {
+		if node, ok := fields[key]; ok {
+			return node
+		}
+		return root
+	}
+
+	if challenge.Name == "" {
+		report(nodeOf("name"), "missing name")
+	}
+	if challenge.Author == "" {
+		report(nodeOf("author"), "missing author")
+	}
+	if _, valid := validTypes[challenge.Type]; !valid {
+		report(nodeOf("type"), "invalid type %q", challenge.Type)
+	}
+	if challenge.Value < 0 {
+		report(nodeOf("value"), "negative value")
+	}
+	if err := validateHints(challenge.Hints); err != nil {
+		report(nodeOf("hints"), "%v", err)
+	}
+
+	isContainer := strings.HasSuffix(challenge.Type, "Container")
+	switch {
+	case len(challenge.Flags) == 0 && strings.HasPrefix(challenge.Type, "Static"):
+		report(nodeOf("flags"), "missing flags for static challenge")
+	case challenge.Type == "DynamicContainer" && challenge.Container.FlagTemplate == "":
+		report(nodeOf("container"), "missing flag template for dynamic container")
+	}
+
+	if flagRegex != nil {
+		if flags, ok := fields["flags"]; ok && flags.Kind == yaml.SequenceNode {
+			for _, flag := range flags.Content {
+				if flag.Tag == "!secret" {
+					if !secretNameRegex.MatchString(flag.Value) {
+						report(flag, "invalid secret name %q", flag.Value)
+					}
+					continue
+				}
+				if strings.HasPrefix(flag.Value, secretStoreScheme) {
+					continue
+				}
+				if !flagRegex.MatchString(flag.Value) {
+					report(flag, "flag %q does not match flagFormat %s", flag.Value, flagRegex)
+				}
+			}
+		}
+	}
+
+	if isContainer {
+		containerFields := map[string]*yaml.Node{}
+		if container, ok := fields["container"]; ok && container.Kind == yaml.MappingNode {
+			containerFields = mappingFields(container)
+		}
+		containerNode := func(key string) *yaml.Node {
+			if node, ok := containerFields[key]; ok {
+				return node
+			}
+			return nodeOf("container")
+		}
+
+		if challenge.Container.ContainerImage == "" {
+			report(containerNode("containerImage"), "missing container image for container challenge")
+		}
+		// 0 or a missing limit deploys the default of mergeChallengeData
+		if challenge.Container.MemoryLimit < 0 {
+			report(containerNode("memoryLimit"), "memoryLimit must not be negative, omit it for the default of %d", defaultMemoryLimit)
+		}
+		if challenge.Container.CpuCount < 0 {
+			report(containerNode("cpuCount"), "cpuCount must not be negative, omit it for the default of %d", defaultCpuCount)
+		}
+		if challenge.Container.StorageLimit < 0 {
+			report(containerNode("storageLimit"), "storageLimit must not be negative, omit it for the default of %d", defaultStorageLimit)
+		}
+		if port := challenge.Container.ContainerExposePort; port < 1 || port > 65535 {
+			report(containerNode("containerExposePort"), "containerExposePort %d is not a valid port", port)
+		}
+	}
+
+	if challenge.Provide != nil && !strings.HasPrefix(*challenge.Provide, "http") {
+		source := filepath.Join(filepath.Dir(path), *challenge.Provide)
+		if _, err := os.Stat(source); err != nil {
+			report(nodeOf("provide"), "provide path %s does not exist", *challenge.Provide)
+		} else if pointers, err := lfsPointers(source); err == nil && len(pointers) > 0 {
+			for _, pointer := range pointers {
+				rel, _ := filepath.Rel(filepath.Dir(path), pointer)
+				report(nodeOf("provide"), "%s is a Git LFS pointer, run git lfs pull", rel)
+			}
+		}
+	}
+
+	if check := challenge.Healthcheck; check != nil {
+		healthFields := map[string]*yaml.Node{}
+		if node, ok := fields["healthcheck"]; ok && node.Kind == yaml.MappingNode {
+			healthFields = mappingFields(node)
+			lintUnknownKeys(node, reflect.TypeOf(Healthcheck{}), "healthcheck.", report)
+		}
+		healthNode := func(key string) *yaml.Node {
+			if node, ok := healthFields[key]; ok {
+				return node
+			}
+			return nodeOf("healthcheck")
+		}
+
+		probes := 0
+		for _, probe := range []string{check.Command, check.TCP, check.HTTP} {
+			if probe != "" {
+				probes++
+			}
+		}
+		if probes != 1 {
+			report(nodeOf("healthcheck"), "healthcheck needs exactly one of command, tcp or http")
+		}
+		if _, err := check.interval(); err != nil {
+			report(healthNode("interval"), "invalid healthcheck interval: %v", err)
+		}
+		if _, err := check.timeout(); err != nil {
+			report(healthNode("timeout"), "invalid healthcheck timeout: %v", err)
+		}
+		if check.Retries < 0 {
+			report(healthNode("retries"), "healthcheck retries must not be negative")
+		}
+	}
+
+	if solver := challenge.Solver; solver != nil {
+		solverNode := nodeOf("solver")
+		if node, ok := fields["solver"]; ok && node.Kind == yaml.MappingNode {
+			lintUnknownKeys(node, reflect.TypeOf(Solver{}), "solver.", report)
+			if timeout, ok := mappingFields(node)["timeout"]; ok {
+				solverNode = timeout
+			}
+		}
+		if _, err := solver.timeout(); err != nil {
+			report(solverNode, "invalid solver timeout: %v", err)
+		}
+	}
+
+	for name, script := range challenge.Scripts {
+		if strings.TrimSpace(script) == "" {
+			report(nodeOf("scripts"), "script %s is empty", name)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].File != issues[j].File {
+			return issues[i].File < issues[j].File
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues, nil
+}
+
+// mappingFields indexes the value nodes of a yaml mapping by key
+func mappingFields(node *yaml.Node) map[string]*yaml.Node {
+	fields := make(map[string]*yaml.Node, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fields[node.Content[i].Value] = node.Content[i+1]
+	}
+	return fields
+}
+
+// lintUnknownKeys reports keys of node that have no yaml tag in typ
+func lintUnknownKeys(node *yaml.Node, typ reflect.Type, prefix string, report func(*yaml.Node, string, ...any)) {
+	known := map[string]bool{}
+	for i := 0; i < typ.NumField(); i++ {
+		name := strings.Split(typ.Field(i).Tag.Get("yaml"), ",")[0]
+		if name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !known[key.Value] {
+			report(key, "unknown key %s%s", prefix, key.Value)
+		}
+	}
+}
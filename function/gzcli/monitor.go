@@ -0,0 +1,41 @@
+package gzcli
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// Monitor subscribes to the platform event stream of the current game and
+// passes every hub message to handler until ctx is cancelled
+func (gz *GZ) Monitor(ctx context.Context, handler func(gzapi.HubMessage)) error {
+	game, err := gz.currentGame()
+	if err != nil {
+		return err
+	}
+	log.Info("%s", log.Msg("monitor.subscribed", game.Title))
+	return game.Subscribe(ctx, handler)
+}
+
+// LogHubMessage prints game events and submissions in a readable form
+func LogHubMessage(message gzapi.HubMessage) {
+	for _, arg := range message.Arguments {
+		switch message.Target {
+		case gzapi.EventGameEvent:
+			var event gzapi.GameEvent
+			if err := json.Unmarshal(arg, &event); err == nil {
+				log.Info("%s", log.Msg("monitor.event", event.Time.Format("15:04:05"), event.Type, event.Team, event.User, strings.Join(event.Values, " ")))
+			}
+		case gzapi.EventSubmission:
+			var submission gzapi.Submission
+			if err := json.Unmarshal(arg, &submission); err == nil {
+				log.Info("%s", log.Msg("monitor.submission", submission.Time.Format("15:04:05"), submission.Status, submission.Team, submission.User, submission.Challenge))
+			}
+		default:
+			log.Info("%s %s", message.Target, string(arg))
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gzcli
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const oplogFile = "oplog.jsonl"
+
+var jsonlMu sync.Mutex
+
+// appendJSONL appends v as one JSON line to the log at path, creating the
+// log and its directory when needed
+func appendJSONL(path string, v any) error {
+	jsonlMu.Lock()
+	defer jsonlMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(v)
+}
+
+// Operation is one audited operator action
+type Operation struct {
+	Time     time.Time         `json:"time"`
+	Operator string            `json:"operator"`
+	Action   string            `json:"action"`
+	Details  map[string]string `json:"details"`
+}
+
+// recordOperation appends an operator action to the oplog
+func recordOperation(action string, details map[string]string) error {
+	operator := "unknown"
+	if u, err := user.Current(); err == nil {
+		operator = u.Username
+	}
+
+	return appendJSONL(filepath.Join(cacheDir, oplogFile), Operation{
+		Time:     time.Now(),
+		Operator: operator,
+		Action:   action,
+		Details:  details,
+	})
+}
+
+// ReadOplog returns every recorded operator action, oldest first
+func ReadOplog() ([]Operation, error) {
+	f, err := os.Open(filepath.Join(cacheDir, oplogFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var operations []Operation
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var op Operation
+		if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			continue
+		}
+		operations = append(operations, op)
+	}
+	return operations, scanner.Err()
+}
+
+const annotateAction = "annotate"
+
+// Annotate records an operator note, such as an outage at the venue, in the
+// oplog to give context to gaps in recorded data
+func Annotate(note string) error {
+	return recordOperation(annotateAction, map[string]string{"note": note})
+}
+
+// Annotations returns the operator notes recorded since the given time,
+// oldest first
+func Annotations(since time.Time) ([]Operation, error) {
+	operations, err := ReadOplog()
+	if err != nil {
+		return nil, err
+	}
+	var annotations []Operation
+	for _, op := range operations {
+		if op.Action == annotateAction && !op.Time.Before(since) {
+			annotations = append(annotations, op)
+		}
+	}
+	return annotations, nil
+}
@@ -0,0 +1,92 @@
+package gzcli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+)
+
+// PlatformSettings are the platform config fields gzcli platform set can
+// change. Nil fields are left as they are
+type PlatformSettings struct {
+	Title                     *string
+	Slogan                    *string
+	FooterInfo                *string
+	AllowRegister             *bool
+	ActiveOnRegister          *bool
+	UseCaptcha                *bool
+	EmailConfirmationRequired *bool
+	EmailDomainList           *string
+	DefaultLifetime           *int
+	ExtensionDuration         *int
+	RenewalWindow             *int
+	AutoDestroyOnLimitReached *bool
+}
+
+// patches groups the set fields by config section and json key
+func (s PlatformSettings) patches() map[string]map[string]any {
+	patches := map[string]map[string]any{}
+	set := func(section, key string, value any, ok bool) {
+		if !ok {
+			return
+		}
+		if patches[section] == nil {
+			patches[section] = map[string]any{}
+		}
+		patches[section][key] = value
+	}
+	set(gzapi.ConfigGlobalConfig, "title", deref(s.Title), s.Title != nil)
+	set(gzapi.ConfigGlobalConfig, "slogan", deref(s.Slogan), s.Slogan != nil)
+	set(gzapi.ConfigGlobalConfig, "footerInfo", deref(s.FooterInfo), s.FooterInfo != nil)
+	set(gzapi.ConfigAccountPolicy, "allowRegister", deref(s.AllowRegister), s.AllowRegister != nil)
+	set(gzapi.ConfigAccountPolicy, "activeOnRegister", deref(s.ActiveOnRegister), s.ActiveOnRegister != nil)
+	set(gzapi.ConfigAccountPolicy, "useCaptcha", deref(s.UseCaptcha), s.UseCaptcha != nil)
+	set(gzapi.ConfigAccountPolicy, "emailConfirmationRequired", deref(s.EmailConfirmationRequired), s.EmailConfirmationRequired != nil)
+	set(gzapi.ConfigAccountPolicy, "emailDomainList", deref(s.EmailDomainList), s.EmailDomainList != nil)
+	set(gzapi.ConfigContainerPolicy, "defaultLifetime", deref(s.DefaultLifetime), s.DefaultLifetime != nil)
+	set(gzapi.ConfigContainerPolicy, "extensionDuration", deref(s.ExtensionDuration), s.ExtensionDuration != nil)
+	set(gzapi.ConfigContainerPolicy, "renewalWindow", deref(s.RenewalWindow), s.RenewalWindow != nil)
+	set(gzapi.ConfigContainerPolicy, "autoDestroyOnLimitReached", deref(s.AutoDestroyOnLimitReached), s.AutoDestroyOnLimitReached != nil)
+	return patches
+}
+
+func deref[T any](p *T) T {
+	var zero T
+	if p == nil {
+		return zero
+	}
+	return *p
+}
+
+// Platform returns the global config of the platform
+func (gz *GZ) Platform() (*gzapi.AdminConfig, error) {
+	return gz.api.GetAdminConfig()
+}
+
+// SetPlatform changes the set fields of the platform config and records the
+// change in the oplog
+func (gz *GZ) SetPlatform(settings PlatformSettings) error {
+	patches := settings.patches()
+	if len(patches) == 0 {
+		return fmt.Errorf("nothing to change")
+	}
+
+	details := map[string]string{}
+	sections := make([]string, 0, len(patches))
+	for section := range patches {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	for _, section := range sections {
+		if err := gz.api.PatchAdminConfig(section, patches[section]); err != nil {
+			return fmt.Errorf("update %s: %w", section, err)
+		}
+		for key, value := range patches[section] {
+			details[key] = fmt.Sprint(value)
+		}
+	}
+	details["sections"] = strings.Join(sections, ",")
+	return recordOperation("platform.set", details)
+}
@@ -0,0 +1,50 @@
+package gzcli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PROFILES_DIR holds named overlays of conf.yaml, one file per GZCTF instance
+const PROFILES_DIR = "profiles"
+
+var profile string
+
+// reservedProfiles would give a profile the cache directory of something
+// else, .gzcli-backups for "backups"
+var reservedProfiles = []string{"backups"}
+
+func profilePath(dir string, name string) string {
+	return filepath.Join(dir, GZCTF_DIR, PROFILES_DIR, name+".yaml")
+}
+
+// SetProfile selects .gzctf/profiles/<name>.yaml, whose keys override
+// conf.yaml, and gives the profile its own .gzcli-<name> cache directory so
+// staging and production state never mix, backups go to
+// .gzcli-backups/<name>. It must run before Init
+func SetProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+	if strings.ContainsAny(name, `/\`) || name == "." || name == ".." {
+		return fmt.Errorf("invalid profile name %q", name)
+	}
+	if slices.Contains(reservedProfiles, name) {
+		return fmt.Errorf("profile name %q is reserved", name)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(profilePath(dir, name)); err != nil {
+		return fmt.Errorf("profile %s not found: %w", name, err)
+	}
+
+	profile = name
+	cacheDir = filepath.Join(filepath.Dir(cacheDir), ".gzcli-"+name)
+	return nil
+}
@@ -0,0 +1,187 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+)
+
+const smtpDialTimeout = 5 * time.Second
+
+// ReadinessCheck is one item of the event readiness checklist
+type ReadinessCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ReadinessReport is the go/no-go checklist produced before doors open
+type ReadinessReport struct {
+	Time   time.Time        `json:"time"`
+	Event  string           `json:"event"`
+	Ready  bool             `json:"ready"`
+	Checks []ReadinessCheck `json:"checks"`
+}
+
+func (r *ReadinessReport) run(name string, check func() (string, error)) {
+	detail, err := check()
+	r.add(name, err, detail)
+}
+
+func (r *ReadinessReport) add(name string, err error, detail string) {
+	check := ReadinessCheck{Name: name, Passed: err == nil, Detail: detail}
+	if err != nil {
+		check.Detail = err.Error()
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+// Readiness runs every pre-event check and reports which ones pass: lint,
+// sync state, healthchecks, poster, team approvals, SMTP and scoreboard
+func (gz *GZ) Readiness(ctx context.Context) (*ReadinessReport, error) {
+	config, err := lookupConfig(gz.api)
+	if err != nil {
+		return nil, err
+	}
+	report := &ReadinessReport{Time: time.Now(), Event: config.Event.Title}
+
+	report.run("challenges lint clean", checkLint)
+	report.run("healthchecks green", func() (string, error) {
+		return checkHealth(ctx)
+	})
+
+	var game *gzapi.Game
+	if games, err := gz.api.GetGames(); err != nil {
+		report.add("game exists", err, "")
+	} else if game = findCurrentGame(games, config.Event.Title, gz.api); game == nil {
+		report.add("game exists", fmt.Errorf("game %q not found, run gzcli --sync", config.Event.Title), "")
+	} else {
+		report.add("game exists", nil, fmt.Sprintf("id %d", game.Id))
+		report.run("challenges synced", func() (string, error) {
+			return checkSynced(config, game)
+		})
+		report.run("poster uploaded", func() (string, error) {
+			return checkPoster(config, game)
+		})
+		report.run("teams approved", func() (string, error) {
+			return checkTeamsApproved(game)
+		})
+		report.run("scoreboard reachable", func() (string, error) {
+			scoreboard, err := game.GetScoreboard()
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%d teams ranked", len(scoreboard.Items)), nil
+		})
+	}
+	report.run("SMTP reachable", checkSmtp)
+
+	report.Ready = true
+	for _, check := range report.Checks {
+		report.Ready = report.Ready && check.Passed
+	}
+	return report, nil
+}
+
+func checkLint() (string, error) {
+	issues, err := Lint()
+	if err != nil {
+		return "", err
+	}
+	if len(issues) > 0 {
+		return "", fmt.Errorf("%d problems, run gzcli --lint", len(issues))
+	}
+	return "", nil
+}
+
+func checkHealth(ctx context.Context) (string, error) {
+	results, err := RunHealthchecks(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "no healthchecks configured", nil
+	}
+	var unhealthy []string
+	for _, result := range results {
+		if !result.Healthy {
+			unhealthy = append(unhealthy, result.Challenge)
+		}
+	}
+	if len(unhealthy) > 0 {
+		return "", fmt.Errorf("unhealthy: %s", strings.Join(unhealthy, ", "))
+	}
+	return fmt.Sprintf("%d healthy", len(results)), nil
+}
+
+func checkSynced(config *Config, game *gzapi.Game) (string, error) {
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return "", err
+	}
+	challenges, err := game.GetChallenges()
+	if err != nil {
+		return "", err
+	}
+
+	var missing []string
+	for _, challengeConf := range challengesConf {
+		if !isChallengeExist(challengeConf.Name, challenges) {
+			missing = append(missing, challengeConf.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing on platform: %s", strings.Join(missing, ", "))
+	}
+	return fmt.Sprintf("%d challenges", len(challengesConf)), nil
+}
+
+func checkPoster(config *Config, game *gzapi.Game) (string, error) {
+	if config.Event.Poster == "" {
+		return "no poster configured", nil
+	}
+	if game.Poster == "" {
+		return "", fmt.Errorf("poster %s is not uploaded, run gzcli --sync --update-game", config.Event.Poster)
+	}
+	return "", nil
+}
+
+func checkTeamsApproved(game *gzapi.Game) (string, error) {
+	participations, err := game.GetParticipations()
+	if err != nil {
+		return "", err
+	}
+	pending := 0
+	for _, p := range participations {
+		if p.Status == gzapi.ParticipationPending {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return "", fmt.Errorf("%d of %d teams still pending", pending, len(participations))
+	}
+	return fmt.Sprintf("%d teams", len(participations)), nil
+}
+
+func checkSmtp() (string, error) {
+	if _, err := os.Stat(filepath.Join(GZCTF_DIR, "appsettings.json")); os.IsNotExist(err) {
+		return "no appsettings.json, emails are not sent", nil
+	}
+	smtp, err := getSmtpSettings()
+	if err != nil {
+		return "", err
+	}
+	address := net.JoinHostPort(smtp.Host, fmt.Sprint(smtp.Port))
+	conn, err := net.DialTimeout("tcp", address, smtpDialTimeout)
+	if err != nil {
+		return "", err
+	}
+	conn.Close()
+	return address, nil
+}
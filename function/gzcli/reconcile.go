@@ -0,0 +1,186 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// Reconcile actions
+const (
+	ReconcileStart   = "start"
+	ReconcileRestart = "restart"
+	ReconcileStop    = "stop"
+	ReconcileSync    = "sync"
+)
+
+// ReconcileSummary is the outcome of one reconcile pass
+type ReconcileSummary struct {
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Synced     bool              `json:"synced"`
+	SyncError  string            `json:"syncError,omitempty"`
+	Challenges []ReconcileResult `json:"challenges"`
+	Failed     int               `json:"failed"`
+}
+
+// ReconcileResult is what a reconcile pass did for one changed challenge
+type ReconcileResult struct {
+	Name       string     `json:"name"`
+	Category   string     `json:"category"`
+	Status     string     `json:"status"`
+	UpdateType UpdateType `json:"updateType"`
+	Action     string     `json:"action"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// Reconcile pulls the repository, syncs the challenges changed by the pull,
+// starts added challenges, restarts the ones that need a redeploy and stops
+// the containers of removed ones. Removed challenges are left on the
+// platform. Failures of single challenges are reported in the summary
+// instead of stopping the pass
+func (gz *GZ) Reconcile(ctx context.Context) (*ReconcileSummary, error) {
+	from, err := runGit("rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Pull the repository")
+	output, err := runGit("pull", "--ff-only")
+	if err != nil {
+		return nil, err
+	}
+	log.InfoH2("%s", output)
+	to, err := runGit("rev-parse", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	gz.events.publish(GitPulled{Time: time.Now(), Revision: to, Output: output})
+
+	summary := &ReconcileSummary{From: from, To: to, Challenges: []ReconcileResult{}}
+	diffs, err := DiffBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(diffs) == 0 {
+		log.Info("Nothing changed since %s", from)
+		return summary, nil
+	}
+
+	needsSync := false
+	for _, diff := range diffs {
+		if diff.Status != DiffRemoved && diff.UpdateType != UpdateNone {
+			needsSync = true
+		}
+	}
+	if needsSync {
+		log.Info("Sync challenges changed between %s and %s", from, to)
+		if err := gz.Sync(); err != nil {
+			summary.SyncError = err.Error()
+			log.Error("Sync failed: %v", err)
+		} else {
+			summary.Synced = true
+		}
+	}
+
+	config, err := GetConfig(gz.api)
+	if err != nil {
+		return nil, err
+	}
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDependencies(challengesConf); err != nil {
+		return nil, err
+	}
+	byName := make(map[string]ChallengeYaml, len(challengesConf))
+	for _, c := range challengesConf {
+		byName[c.Name] = c
+	}
+
+	var deploy []ChallengeYaml
+	actions := map[string]*ReconcileResult{}
+	for _, diff := range diffs {
+		result := ReconcileResult{
+			Name:       diff.Name,
+			Category:   diff.Category,
+			Status:     diff.Status,
+			UpdateType: diff.UpdateType,
+			Action:     reconcileAction(diff),
+		}
+		switch result.Action {
+		case ReconcileStop:
+			if err := stopRemovedChallenge(diff); err != nil {
+				result.Error = err.Error()
+			}
+		case ReconcileStart, ReconcileRestart:
+			challengeConf, ok := byName[diff.Name]
+			switch {
+			case !ok:
+				result.Error = fmt.Sprintf("challenge %q not found", diff.Name)
+			case summary.SyncError != "":
+				result.Error = "skipped, sync failed"
+			default:
+				deploy = append(deploy, challengeConf)
+			}
+		}
+		summary.Challenges = append(summary.Challenges, result)
+	}
+	for i := range summary.Challenges {
+		actions[summary.Challenges[i].Name] = &summary.Challenges[i]
+	}
+
+	pool := newSlotPool(maxParallelScripts, config.SyncPolicy)
+	runInDependencyOrder(ctx, deploy, pool, false, func(c ChallengeYaml) error {
+		log.Info("%s %s", actions[c.Name].Action, c.Name)
+		if err := runScript(c, actions[c.Name].Action); err != nil {
+			err = fmt.Errorf("%s %s: %w", actions[c.Name].Action, c.Name, err)
+			gz.events.publish(DeployFailed{Time: time.Now(), Challenge: c.Name, Err: err})
+			return err
+		}
+		return nil
+	}, func(c ChallengeYaml, err error) {
+		if err != nil {
+			actions[c.Name].Error = err.Error()
+		}
+	})
+
+	for _, result := range summary.Challenges {
+		if result.Error != "" {
+			summary.Failed++
+			log.Error("%s: %s", result.Name, result.Error)
+		}
+	}
+	if summary.SyncError != "" {
+		summary.Failed++
+	}
+	return summary, recordOperation("reconcile", map[string]string{
+		"from":   from,
+		"to":     to,
+		"failed": fmt.Sprint(summary.Failed),
+	})
+}
+
+// reconcileAction decides what a reconcile pass does for a changed challenge
+func reconcileAction(diff ChallengeDiff) string {
+	switch {
+	case diff.Status == DiffRemoved:
+		return ReconcileStop
+	case diff.Status == DiffAdded:
+		return ReconcileStart
+	case diff.UpdateType == UpdateFullRedeploy:
+		return ReconcileRestart
+	}
+	return ReconcileSync
+}
+
+// stopRemovedChallenge takes down the compose project of a challenge whose
+// directory is gone. Its own stop script went with the directory, so only
+// compose deployments can be stopped
+func stopRemovedChallenge(diff ChallengeDiff) error {
+	slug := generateSlug(ChallengeYaml{Name: diff.Name, Category: diff.Category})
+	log.Info("Stop removed challenge %s", diff.Name)
+	return runShellWithEnv(fmt.Sprintf("docker compose -p %s down --volumes", slug), getWorkDir(), nil)
+}
@@ -0,0 +1,23 @@
+package gzcli
+
+import "testing"
+
+func TestReconcileAction(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		diff ChallengeDiff
+		want string
+	}{
+		{"removed", ChallengeDiff{Status: DiffRemoved, UpdateType: UpdateFullRedeploy}, ReconcileStop},
+		{"added", ChallengeDiff{Status: DiffAdded, UpdateType: UpdateFullRedeploy}, ReconcileStart},
+		{"redeploy", ChallengeDiff{Status: DiffModified, UpdateType: UpdateFullRedeploy}, ReconcileRestart},
+		{"attachment", ChallengeDiff{Status: DiffModified, UpdateType: UpdateAttachment}, ReconcileSync},
+		{"metadata", ChallengeDiff{Status: DiffModified, UpdateType: UpdateMetadata}, ReconcileSync},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reconcileAction(tt.diff); got != tt.want {
+				t.Fatalf("action = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package gzcli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"slices"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// TeamRegistration is the outcome of joining one team to a game
+type TeamRegistration struct {
+	Team     string
+	Username string
+	Division string
+	Status   string
+	Error    string
+}
+
+// Team registration statuses
+const (
+	RegistrationJoined  = "joined"
+	RegistrationAlready = "already registered"
+	RegistrationFailed  = "failed"
+)
+
+// RegisterTeamsForm describes a --register-teams run. Division overrides the
+// division of the CSV rows when set, Game defaults to the event of conf.yaml
+type RegisterTeamsForm struct {
+	CSV        string
+	Game       string
+	Division   string
+	InviteCode string
+	SendEmail  bool
+}
+
+// RegisterTeams creates the teams of a CSV and asks each of them to join the
+// game, logged in as the team captain
+func (gz *GZ) RegisterTeams(form RegisterTeamsForm) ([]TeamRegistration, error) {
+	config, err := GetConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	if form.Game == "" {
+		form.Game = config.Event.Title
+	}
+
+	// Rejected rows must not stop the valid teams from registering
+	if err := gz.CreateTeams(form.CSV, form.SendEmail); err != nil {
+		log.Error("%s", err.Error())
+	}
+
+	game, err := gz.api.GetGameByTitle(form.Game)
+	if err != nil {
+		return nil, fmt.Errorf("game %q: %w", form.Game, err)
+	}
+	// The game list leaves out the organizations the divisions are checked
+	// against
+	if game, err = gz.api.GetGameById(game.Id); err != nil {
+		return nil, fmt.Errorf("game %q: %w", form.Game, err)
+	}
+	if form.Division != "" {
+		if err := validateDivision(game.Organizations, form.Division); err != nil {
+			return nil, fmt.Errorf("game %q: %w", game.Title, err)
+		}
+	}
+	participations, err := game.GetParticipations()
+	if err != nil {
+		return nil, err
+	}
+	registered := make(map[string]bool, len(participations))
+	for _, participation := range participations {
+		registered[participation.Team.Name] = true
+	}
+
+	emails, err := csvEmails(form.CSV)
+	if err != nil {
+		return nil, err
+	}
+	teamsCreds, err := loadTeamsCreds()
+	if err != nil {
+		return nil, fmt.Errorf("no team credentials, create teams first: %w", err)
+	}
+
+	var registrations []TeamRegistration
+	for _, creds := range teamsCreds {
+		// Teams created by earlier CSVs are left alone
+		if !emails[creds.Email] {
+			continue
+		}
+		registration := TeamRegistration{
+			Team:     creds.TeamName,
+			Username: creds.Username,
+			Division: creds.Division,
+		}
+		if form.Division != "" {
+			registration.Division = form.Division
+		}
+
+		if registered[creds.TeamName] {
+			registration.Status = RegistrationAlready
+		} else if err := validateDivision(game.Organizations, registration.Division); err != nil {
+			registration.Status = RegistrationFailed
+			registration.Error = err.Error()
+			log.ErrorH2("%s", log.Msg("team.register.failed", creds.TeamName, err))
+		} else if err := joinGame(config, game, creds, registration.Division, form.InviteCode); err != nil {
+			registration.Status = RegistrationFailed
+			registration.Error = err.Error()
+			log.ErrorH2("%s", log.Msg("team.register.failed", creds.TeamName, err))
+		} else {
+			registration.Status = RegistrationJoined
+			log.InfoH2("%s", log.Msg("team.registered", creds.TeamName, game.Title))
+		}
+		registrations = append(registrations, registration)
+	}
+
+	joined := 0
+	for _, registration := range registrations {
+		if registration.Status == RegistrationJoined {
+			joined++
+		}
+	}
+	return registrations, recordOperation("teams.register", map[string]string{
+		"game":   game.Title,
+		"joined": fmt.Sprint(joined),
+		"teams":  fmt.Sprint(len(registrations)),
+	})
+}
+
+// csvEmails returns the emails of the rows of a team CSV
+func csvEmails(source string) (map[string]bool, error) {
+	data, err := getData(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CSV data: %w", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV data: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV is empty")
+	}
+	column := slices.Index(records[0], "Email")
+	if column < 0 {
+		return nil, fmt.Errorf("missing required header: Email")
+	}
+	emails := map[string]bool{}
+	for _, row := range records[1:] {
+		emails[row[column]] = true
+	}
+	return emails, nil
+}
+
+// joinGame logs in as the team captain and requests participation for the
+// team in game
+func joinGame(config *Config, game *gzapi.Game, creds *TeamCreds, division, inviteCode string) error {
+	api, err := gzapi.Init(config.Url, &gzapi.Creds{
+		Username: creds.Username,
+		Password: creds.Password,
+	}, config.TLS)
+	if err != nil {
+		return fmt.Errorf("login as %s: %w", creds.Username, err)
+	}
+	teams, err := api.MyTeams()
+	if err != nil {
+		return err
+	}
+
+	var team *gzapi.Team
+	for _, t := range teams {
+		if t.Name == creds.TeamName {
+			team = t
+			break
+		}
+	}
+	if team == nil {
+		return fmt.Errorf("%s is not in team %s", creds.Username, creds.TeamName)
+	}
+
+	captainGame := *game
+	captainGame.CS = api
+	return captainGame.JoinGame(&gzapi.GameJoinForm{
+		TeamId:       team.Id,
+		Organization: division,
+		InviteCode:   inviteCode,
+	})
+}
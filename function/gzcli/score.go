@@ -0,0 +1,73 @@
+package gzcli
+
+import (
+	"fmt"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// ScoreAdjustment changes the standing of a team in the current game.
+// GZCTF does not expose point edits or per-submission invalidation, so
+// corrections go through the participation status: Suspended removes the
+// team and its solves from the scoreboard, Accepted restores them
+type ScoreAdjustment struct {
+	Team   string
+	Status string
+	Reason string
+}
+
+var validParticipationStatus = map[string]struct{}{
+	gzapi.ParticipationAccepted:  {},
+	gzapi.ParticipationRejected:  {},
+	gzapi.ParticipationSuspended: {},
+	gzapi.ParticipationPending:   {},
+}
+
+func (gz *GZ) findParticipation(team string) (*gzapi.Participation, error) {
+	game, err := gz.currentGame()
+	if err != nil {
+		return nil, err
+	}
+
+	participations, err := game.GetParticipations()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range participations {
+		if p.Team.Name == team {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("team %q is not participating in %s", team, game.Title)
+}
+
+// AdjustScore applies a score adjustment and records it in the oplog
+func (gz *GZ) AdjustScore(adjustment ScoreAdjustment) error {
+	if _, ok := validParticipationStatus[adjustment.Status]; !ok {
+		return fmt.Errorf("invalid status %q", adjustment.Status)
+	}
+	if adjustment.Reason == "" {
+		return fmt.Errorf("a reason is required for score adjustments")
+	}
+
+	participation, err := gz.findParticipation(adjustment.Team)
+	if err != nil {
+		return err
+	}
+
+	previous := participation.Status
+	log.Info("Change status of %s from %s to %s", adjustment.Team, previous, adjustment.Status)
+	if err := participation.UpdateStatus(adjustment.Status); err != nil {
+		return err
+	}
+
+	return recordOperation("score.adjust", map[string]string{
+		"team":   adjustment.Team,
+		"from":   previous,
+		"to":     adjustment.Status,
+		"reason": adjustment.Reason,
+		"teamId": fmt.Sprint(participation.Team.Id),
+		"partId": fmt.Sprint(participation.Id),
+	})
+}
@@ -0,0 +1,177 @@
+package gzcli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+const (
+	scoreboardHistoryFile = "scoreboard.jsonl"
+	// a snapshot of a large scoreboard easily exceeds the default scanner buffer
+	maxSnapshotSize = 16 * 1024 * 1024
+)
+
+// ScoreboardSnapshot is the standing of every team at one point in time
+type ScoreboardSnapshot struct {
+	Time  time.Time              `json:"time"`
+	Items []gzapi.ScoreboardItem `json:"items"`
+}
+
+// ScorePoint is the score of a team at one point in time
+type ScorePoint struct {
+	Time  int64 `json:"time"`
+	Score int   `json:"score"`
+}
+
+// ScoreProgression is the score history of one team, in the CTFTime
+// scoreboard feed style
+type ScoreProgression struct {
+	Team   string       `json:"team"`
+	Scores []ScorePoint `json:"scores"`
+}
+
+// RecordScoreboard polls the scoreboard of the current game every interval
+// until ctx is cancelled and appends a snapshot whenever the standings change
+func (gz *GZ) RecordScoreboard(ctx context.Context, interval time.Duration) error {
+	game, err := gz.currentGame()
+	if err != nil {
+		return err
+	}
+
+	var previous []gzapi.ScoreboardItem
+	if history, err := ReadScoreboardHistory(); err == nil && len(history) > 0 {
+		previous = history[len(history)-1].Items
+	}
+
+	log.Info("Recording scoreboard of %s every %s", game.Title, interval)
+	return pollScoreboard(ctx, game, interval, func(scoreboard *gzapi.Scoreboard) error {
+		if reflect.DeepEqual(previous, scoreboard.Items) {
+			return nil
+		}
+		if err := appendScoreboardSnapshot(ScoreboardSnapshot{Time: time.Now(), Items: scoreboard.Items}); err != nil {
+			return err
+		}
+		log.InfoH2("Recorded snapshot with %d teams", len(scoreboard.Items))
+		previous = scoreboard.Items
+		return nil
+	})
+}
+
+// pollScoreboard calls fn with the scoreboard of game right away and then
+// every interval until ctx is cancelled or fn fails. Scoreboard errors are
+// logged and retried on the next tick
+func pollScoreboard(ctx context.Context, game *gzapi.Game, interval time.Duration, fn func(*gzapi.Scoreboard) error) error {
+	if interval <= 0 {
+		return fmt.Errorf("interval %s must be positive", interval)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		scoreboard, err := game.GetScoreboard()
+		if err != nil {
+			log.Error("scoreboard error: %v", err)
+		} else if err := fn(scoreboard); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func appendScoreboardSnapshot(snapshot ScoreboardSnapshot) error {
+	return appendJSONL(filepath.Join(cacheDir, scoreboardHistoryFile), snapshot)
+}
+
+// ReadScoreboardHistory returns every recorded scoreboard snapshot, oldest first
+func ReadScoreboardHistory() ([]ScoreboardSnapshot, error) {
+	f, err := os.Open(filepath.Join(cacheDir, scoreboardHistoryFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var history []ScoreboardSnapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, maxSnapshotSize)
+	for scanner.Scan() {
+		var snapshot ScoreboardSnapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			continue
+		}
+		history = append(history, snapshot)
+	}
+	return history, scanner.Err()
+}
+
+// ScoreboardProgression turns the recorded history into one score series per
+// team, ordered by the final ranking
+func ScoreboardProgression(history []ScoreboardSnapshot) []ScoreProgression {
+	if len(history) == 0 {
+		return nil
+	}
+
+	index := map[string]int{}
+	var progression []ScoreProgression
+	for _, item := range history[len(history)-1].Items {
+		index[item.Name] = len(progression)
+		progression = append(progression, ScoreProgression{Team: item.Name})
+	}
+
+	for _, snapshot := range history {
+		for _, item := range snapshot.Items {
+			i, ok := index[item.Name]
+			if !ok {
+				// the team left the scoreboard before the last snapshot
+				index[item.Name] = len(progression)
+				i = len(progression)
+				progression = append(progression, ScoreProgression{Team: item.Name})
+			}
+			progression[i].Scores = append(progression[i].Scores, ScorePoint{
+				Time:  snapshot.Time.Unix(),
+				Score: item.Score,
+			})
+		}
+	}
+	return progression
+}
+
+// WriteScoreboardCSV writes one row per team and snapshot
+func WriteScoreboardCSV(w io.Writer, history []ScoreboardSnapshot) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"time", "rank", "team", "score"}); err != nil {
+		return err
+	}
+	for _, snapshot := range history {
+		for _, item := range snapshot.Items {
+			if err := writer.Write([]string{
+				snapshot.Time.Format(time.RFC3339),
+				fmt.Sprint(item.Rank),
+				item.Name,
+				fmt.Sprint(item.Score),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,79 @@
+package gzcli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const defaultScriptShell = "sh"
+
+// ScriptRunner runs challenge scripts inside a disposable container instead
+// of the host shell. The challenge directory is mounted at its own host path
+// so docker compose files and bind mounts resolve the same way as on the host
+type ScriptRunner struct {
+	Image   string   `yaml:"image"`
+	Mounts  []string `yaml:"mounts,omitempty"`
+	Network string   `yaml:"network,omitempty"`
+	Shell   string   `yaml:"shell,omitempty"`
+}
+
+// args returns the docker run arguments that execute script in cwd
+func (r *ScriptRunner) args(script string, cwd string, env []string) ([]string, error) {
+	if r.Image == "" {
+		return nil, fmt.Errorf("scriptRunner.image is required")
+	}
+	cwd, err := filepath.Abs(cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"run", "--rm", "-v", cwd + ":" + cwd, "-w", cwd}
+	if r.Network != "" {
+		args = append(args, "--network", r.Network)
+	}
+	for _, mount := range r.Mounts {
+		args = append(args, "-v", resolveMount(mount))
+	}
+	for _, e := range env {
+		args = append(args, "-e", e)
+	}
+
+	shell := r.Shell
+	if shell == "" {
+		shell = defaultScriptShell
+	}
+	return append(args, "--entrypoint", shell, r.Image, "-c", script), nil
+}
+
+// resolveMount makes the host side of a src:dst[:opts] mount absolute,
+// relative to the repository root. Named volumes are left as they are
+func resolveMount(mount string) string {
+	src, rest, ok := strings.Cut(mount, ":")
+	if !ok || !(strings.HasPrefix(src, ".") || strings.Contains(src, "/")) || filepath.IsAbs(src) {
+		return mount
+	}
+	return filepath.Join(getWorkDir(), src) + ":" + rest
+}
+
+func (r *ScriptRunner) run(script string, cwd string, env []string) error {
+	args, err := r.args(script, cwd, env)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("docker", args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// runChallengeShell runs a script of a challenge with the script runner of
+// conf.yaml, or with the host shell when none is configured
+func runChallengeShell(challengeConf ChallengeYaml, script string, env []string) error {
+	if challengeConf.runner != nil {
+		return challengeConf.runner.run(script, challengeConf.Cwd, env)
+	}
+	return runShellWithEnv(script, challengeConf.Cwd, env)
+}
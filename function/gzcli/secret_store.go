@@ -0,0 +1,309 @@
+package gzcli
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	secretStoreFile = "secrets.enc"
+	// SecretsKeyEnv holds the passphrase of .gzctf/secrets.enc
+	SecretsKeyEnv = "CTFIFY_SECRETS_KEY"
+	// secretEnvPrefix is the prefix of the environment variables a
+	// !secret name falls back to, e.g. CTFIFY_SECRET_FLAG_PROD
+	secretEnvPrefix = "CTFIFY_SECRET_"
+)
+
+var secretNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// expandSecretTags rewrites every scalar tagged `!secret name`, in block or
+// flow style and before comments alike, into a "secret://name" string. The
+// YAML decoder would otherwise drop the unknown tag and keep the bare name
+func expandSecretTags(b []byte) ([]byte, error) {
+	if !bytes.Contains(b, []byte("!secret")) {
+		return b, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+	if err := expandSecretNodes(&doc); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(&doc)
+}
+
+func expandSecretNodes(node *yaml.Node) error {
+	if node.Tag == "!secret" {
+		if node.Kind != yaml.ScalarNode || !secretNameRegex.MatchString(node.Value) {
+			return fmt.Errorf("line %d: !secret needs a name of letters, digits, '.', '-' and '_'", node.Line)
+		}
+		node.Tag = "!!str"
+		node.Style = yaml.DoubleQuotedStyle
+		node.Value = secretStoreScheme + node.Value
+	}
+	for _, child := range node.Content {
+		if err := expandSecretNodes(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func secretStorePath() string {
+	return filepath.Join(getWorkDir(), GZCTF_DIR, secretStoreFile)
+}
+
+// sealedMagic starts data sealed with a scrypt derived key, followed by the
+// salt and the nonce. Data without it was sealed with the SHA-256 of the
+// passphrase by earlier versions and is only opened
+const sealedMagic = "ctfify-scrypt1"
+
+const (
+	sealedSaltSize = 16
+	scryptN        = 1 << 15
+	scryptR        = 8
+	scryptP        = 1
+)
+
+var (
+	derivedKeysMu sync.Mutex
+	derivedKeys   = map[string][]byte{}
+)
+
+// secretsPassphrase returns CTFIFY_SECRETS_KEY, failing when it is not set
+func secretsPassphrase() (string, error) {
+	passphrase := os.Getenv(SecretsKeyEnv)
+	if passphrase == "" {
+		return "", fmt.Errorf("%s is not set", SecretsKeyEnv)
+	}
+	return passphrase, nil
+}
+
+// secretCipher derives the AES-256-GCM cipher for salt from the passphrase
+// in CTFIFY_SECRETS_KEY with scrypt. Derived keys are kept for the process,
+// as scrypt is slow by design
+func secretCipher(salt []byte) (cipher.AEAD, error) {
+	passphrase, err := secretsPassphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	derivedKeysMu.Lock()
+	id := passphrase + "\x00" + string(salt)
+	key, ok := derivedKeys[id]
+	if !ok {
+		key, err = scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+		if err == nil {
+			derivedKeys[id] = key
+		}
+	}
+	derivedKeysMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return newGCM(key)
+}
+
+// legacySecretCipher is the cipher of data sealed before sealedMagic
+func legacySecretCipher() (cipher.AEAD, error) {
+	passphrase, err := secretsPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	key := sha256.Sum256([]byte(passphrase))
+	return newGCM(key[:])
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealSecret encrypts plaintext with a key derived from CTFIFY_SECRETS_KEY
+// and a fresh salt, stored in front of the nonce
+func sealSecret(plaintext []byte) ([]byte, error) {
+	salt := make([]byte, sealedSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := secretCipher(salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := append([]byte(sealedMagic), salt...)
+	sealed = append(sealed, nonce...)
+	return aead.Seal(sealed, nonce, plaintext, nil), nil
+}
+
+// openSecret decrypts data sealed by sealSecret, or by the SHA-256 keyed
+// format of earlier versions, naming it name in errors
+func openSecret(data []byte, name string) ([]byte, error) {
+	var aead cipher.AEAD
+	var err error
+	if rest, ok := bytes.CutPrefix(data, []byte(sealedMagic)); ok {
+		if len(rest) < sealedSaltSize {
+			return nil, fmt.Errorf("%s is corrupted", name)
+		}
+		aead, err = secretCipher(rest[:sealedSaltSize])
+		data = rest[sealedSaltSize:]
+	} else {
+		aead, err = legacySecretCipher()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("%s is corrupted", name)
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt %s: wrong %s or corrupted file", name, SecretsKeyEnv)
+	}
+	return plaintext, nil
+}
+
+// loadSecretStore decrypts .gzctf/secrets.enc. A missing store is empty
+func loadSecretStore() (map[string]string, error) {
+	data, err := os.ReadFile(secretStorePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := openSecret(data, secretStoreFile)
+	if err != nil {
+		return nil, err
+	}
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("%s: %w", secretStoreFile, err)
+	}
+	return secrets, nil
+}
+
+func saveSecretStore(secrets map[string]string) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	sealed, err := sealSecret(plaintext)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(secretStorePath()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(secretStorePath(), sealed, 0600)
+}
+
+// lookupStoredSecret resolves a !secret name from the encrypted store and
+// then from CTFIFY_SECRET_<NAME>
+func lookupStoredSecret(name string) (string, error) {
+	env := secretEnvPrefix + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(name))
+	if _, err := os.Stat(secretStorePath()); err == nil {
+		secrets, err := loadSecretStore()
+		if err != nil {
+			if value, ok := os.LookupEnv(env); ok {
+				return value, nil
+			}
+			return "", err
+		}
+		if value, ok := secrets[name]; ok {
+			return value, nil
+		}
+	}
+	if value, ok := os.LookupEnv(env); ok {
+		return value, nil
+	}
+	return "", fmt.Errorf("secret %s is neither in %s nor in %s", name, secretStoreFile, env)
+}
+
+// SetSecret stores value under name in .gzctf/secrets.enc
+func SetSecret(name, value string) error {
+	if !secretNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q, use letters, digits, '.', '-' and '_'", name)
+	}
+	secrets, err := loadSecretStore()
+	if err != nil {
+		return err
+	}
+	secrets[name] = value
+	return saveSecretStore(secrets)
+}
+
+// GetSecret returns the value of a secret the way challenge.yml and
+// conf.yaml resolve it
+func GetSecret(name string) (string, error) {
+	return lookupStoredSecret(name)
+}
+
+// DeleteSecret removes a secret from .gzctf/secrets.enc
+func DeleteSecret(name string) error {
+	secrets, err := loadSecretStore()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[name]; !ok {
+		return fmt.Errorf("secret %s not found", name)
+	}
+	delete(secrets, name)
+	return saveSecretStore(secrets)
+}
+
+// ListSecrets returns the names of the secrets in .gzctf/secrets.enc
+func ListSecrets() ([]string, error) {
+	secrets, err := loadSecretStore()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// resolveChallengeSecrets resolves the secret references in the flags of a
+// challenge. It runs at sync time only, so linting and diffing never need
+// the key
+func resolveChallengeSecrets(challengeConf *ChallengeYaml) error {
+	flags := make([]string, len(challengeConf.Flags))
+	for i, flag := range challengeConf.Flags {
+		secret, err := resolveSecret(flag)
+		if err != nil {
+			return fmt.Errorf("resolve flags[%d]: %w", i, err)
+		}
+		flags[i] = secret
+	}
+	challengeConf.Flags = flags
+	return resolveSecrets(map[string]*string{
+		"container.flagTemplate": &challengeConf.Container.FlagTemplate,
+	})
+}
@@ -0,0 +1,85 @@
+package gzcli
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+func TestExpandSecretTags(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		yaml string
+		want []string
+	}{
+		{"block", "flags:\n  - !secret flag_prod\n", []string{"secret://flag_prod"}},
+		{"flow", "flags: [!secret flag_prod, plain]\n", []string{"secret://flag_prod", "plain"}},
+		{"comment", "flags:\n  - !secret flag_prod # production flag\n", []string{"secret://flag_prod"}},
+		{"untagged", "flags: [flag_prod]\n", []string{"flag_prod"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				Flags []string `yaml:"flags"`
+			}
+			if err := ParseYamlFromBytes([]byte(tt.yaml), &got); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Join(got.Flags, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("flags = %q, want %q", got.Flags, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandSecretTagsRejectsInvalidNames(t *testing.T) {
+	for _, doc := range []string{"flag: !secret [a, b]\n", "flag: !secret 'a b'\n"} {
+		if _, err := expandSecretTags([]byte(doc)); err == nil {
+			t.Fatalf("%q: expected an error", doc)
+		}
+	}
+}
+
+func TestSealSecretRoundTrip(t *testing.T) {
+	t.Setenv(SecretsKeyEnv, "correct horse battery staple")
+	plaintext := []byte(`{"flag_prod":"CTF{x}"}`)
+
+	sealed, err := sealSecret(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(sealed, []byte(sealedMagic)) {
+		t.Fatalf("sealed data does not start with %q", sealedMagic)
+	}
+	got, err := openSecret(sealed, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatalf("opened %q, want %q", got, plaintext)
+	}
+
+	t.Setenv(SecretsKeyEnv, "wrong")
+	if _, err := openSecret(sealed, "test"); err == nil {
+		t.Fatal("opened with the wrong passphrase")
+	}
+}
+
+func TestOpenLegacySecret(t *testing.T) {
+	t.Setenv(SecretsKeyEnv, "legacy")
+	aead, err := legacySecretCipher()
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	rand.Read(nonce)
+	sealed := aead.Seal(nonce, nonce, []byte("old"), nil)
+
+	got, err := openSecret(sealed, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "old" {
+		t.Fatalf("opened %q, want %q", got, "old")
+	}
+}
@@ -0,0 +1,107 @@
+package gzcli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Secret reference schemes accepted in credential fields of conf.yaml and
+// appsettings.json. Any other value is used as is
+const (
+	secretEnvScheme   = "env://"
+	secretFileScheme  = "file://"
+	secretVaultScheme = "vault://"
+	secretOpScheme    = "op://"
+	secretStoreScheme = "secret://"
+)
+
+// resolveSecret turns a secret reference into its value:
+//
+//	env://NAME             environment variable NAME
+//	file://path            content of path without the trailing newline
+//	vault://path#field     `vault kv get -field=field path`
+//	op://vault/item/field  `op read op://vault/item/field`
+//	secret://name          name in .gzctf/secrets.enc or CTFIFY_SECRET_NAME,
+//	                       also written as the YAML tag `!secret name`
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, secretEnvScheme):
+		name := strings.TrimPrefix(value, secretEnvScheme)
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, secretFileScheme):
+		data, err := os.ReadFile(strings.TrimPrefix(value, secretFileScheme))
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	case strings.HasPrefix(value, secretVaultScheme):
+		path, field, ok := strings.Cut(strings.TrimPrefix(value, secretVaultScheme), "#")
+		if !ok || path == "" || field == "" {
+			return "", fmt.Errorf("vault reference must look like vault://path#field")
+		}
+		return secretCommand("vault", "kv", "get", "-field="+field, path)
+	case strings.HasPrefix(value, secretOpScheme):
+		return secretCommand("op", "read", value)
+	case strings.HasPrefix(value, secretStoreScheme):
+		return lookupStoredSecret(strings.TrimPrefix(value, secretStoreScheme))
+	}
+	return value, nil
+}
+
+func secretCommand(name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+// resolveSecrets resolves every field in place and names the first field
+// that cannot be resolved
+func resolveSecrets(fields map[string]*string) error {
+	for name, field := range fields {
+		secret, err := resolveSecret(*field)
+		if err != nil {
+			return fmt.Errorf("resolve %s: %w", name, err)
+		}
+		*field = secret
+	}
+	return nil
+}
+
+// resolveConfigSecrets resolves the secret references in the credential
+// fields of conf.yaml
+func resolveConfigSecrets(config *Config) error {
+	fields := map[string]*string{
+		"creds.username": &config.Creds.Username,
+		"creds.password": &config.Creds.Password,
+	}
+	if config.Announce != nil {
+		fields["announce.webhook"] = &config.Announce.Webhook
+	}
+	if config.Email != nil {
+		fields["email.sendgridApiKey"] = &config.Email.SendGridApiKey
+	}
+	if err := resolveSecrets(fields); err != nil {
+		return err
+	}
+
+	if config.CDN != nil {
+		for name, value := range config.CDN.Headers {
+			secret, err := resolveSecret(value)
+			if err != nil {
+				return fmt.Errorf("resolve cdn.headers.%s: %w", name, err)
+			}
+			config.CDN.Headers[name] = secret
+		}
+	}
+	return nil
+}
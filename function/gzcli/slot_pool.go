@@ -0,0 +1,100 @@
+package gzcli
+
+import (
+	"context"
+	"sync"
+)
+
+// SyncPolicy sets how the challenges of one category are scheduled by sync
+// and --run-script. Challenges of a higher priority category take the next
+// free worker first, and Workers caps how many of the category run at once
+// so heavy rebuilds cannot hold every worker
+type SyncPolicy struct {
+	Priority int `yaml:"priority,omitempty"`
+	Workers  int `yaml:"workers,omitempty"`
+}
+
+// slotPool hands out a bounded number of worker slots, highest category
+// priority first and in arrival order within a priority
+type slotPool struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	free    int
+	running map[string]int
+	policy  map[string]SyncPolicy
+	waiting []*slotWaiter
+}
+
+type slotWaiter struct {
+	category string
+	priority int
+}
+
+func newSlotPool(workers int, policy map[string]SyncPolicy) *slotPool {
+	p := &slotPool{free: workers, running: map[string]int{}, policy: policy}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// eligible reports whether a challenge of category fits in the free slots
+// and the category limit
+func (p *slotPool) eligible(category string) bool {
+	limit := p.policy[category].Workers
+	return p.free > 0 && (limit <= 0 || p.running[category] < limit)
+}
+
+// next returns the eligible waiter that goes first
+func (p *slotPool) next() *slotWaiter {
+	var best *slotWaiter
+	for _, w := range p.waiting {
+		if p.eligible(w.category) && (best == nil || w.priority > best.priority) {
+			best = w
+		}
+	}
+	return best
+}
+
+// acquire blocks until a slot for category is free or ctx is done
+func (p *slotPool) acquire(ctx context.Context, category string) error {
+	stop := context.AfterFunc(ctx, func() {
+		p.mu.Lock()
+		p.cond.Broadcast()
+		p.mu.Unlock()
+	})
+	defer stop()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	w := &slotWaiter{category: category, priority: p.policy[category].Priority}
+	p.waiting = append(p.waiting, w)
+	defer func() {
+		for i, waiting := range p.waiting {
+			if waiting == w {
+				p.waiting = append(p.waiting[:i], p.waiting[i+1:]...)
+				break
+			}
+		}
+	}()
+
+	for p.next() != w {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p.cond.Wait()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.free--
+	p.running[category]++
+	p.cond.Broadcast()
+	return nil
+}
+
+func (p *slotPool) release(category string) {
+	p.mu.Lock()
+	p.free++
+	p.running[category]--
+	p.cond.Broadcast()
+	p.mu.Unlock()
+}
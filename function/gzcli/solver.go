@@ -0,0 +1,213 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+const (
+	solverDir            = "solver"
+	defaultSolverImage   = "pwntools/pwntools:stable"
+	defaultSolverTimeout = 2 * time.Minute
+	solverHost           = "localhost"
+)
+
+// solverCommands are the default commands for a solver/ directory without
+// an explicit solver.command, in lookup order
+var solverCommands = []struct{ file, command string }{
+	{"solve.py", "python3 solve.py"},
+	{"solve.sh", "sh solve.sh"},
+	{"solve", "./solve"},
+}
+
+// Solver overrides how --test-challenges runs the solver/ directory of a
+// challenge
+type Solver struct {
+	Image   string `yaml:"image,omitempty"`
+	Command string `yaml:"command,omitempty"`
+	Timeout string `yaml:"timeout,omitempty"`
+	Port    int    `yaml:"port,omitempty"`
+}
+
+// SolverResult is the outcome of running the solver of one challenge
+type SolverResult struct {
+	Challenge  string
+	Passed     bool
+	Flag       string
+	DurationMs int64
+	Error      string
+}
+
+func (s *Solver) timeout() (time.Duration, error) {
+	if s == nil {
+		return defaultSolverTimeout, nil
+	}
+	return parseHealthDuration(s.Timeout, defaultSolverTimeout)
+}
+
+// solverCommand returns the command that runs the solver of a challenge, or
+// "" when it has none
+func solverCommand(challengeConf ChallengeYaml) string {
+	if challengeConf.Solver != nil && challengeConf.Solver.Command != "" {
+		return challengeConf.Solver.Command
+	}
+	for _, solver := range solverCommands {
+		if _, err := os.Stat(filepath.Join(challengeConf.Cwd, solverDir, solver.file)); err == nil {
+			return solver.command
+		}
+	}
+	return ""
+}
+
+// flagMatcher returns the pattern a solver output must contain: one of the
+// static flags, the flag template of a dynamic container, or flagFormat
+func flagMatcher(challengeConf ChallengeYaml, flagFormat string) (*regexp.Regexp, error) {
+	var alternatives []string
+	for _, flag := range challengeConf.Flags {
+		alternatives = append(alternatives, regexp.QuoteMeta(flag))
+	}
+	if template := challengeConf.Container.FlagTemplate; template != "" {
+		alternatives = append(alternatives, flagTemplatePattern(template))
+	}
+	if len(alternatives) == 0 && flagFormat != "" {
+		alternatives = append(alternatives, flagFormat)
+	}
+	if len(alternatives) == 0 {
+		return nil, fmt.Errorf("no flags, flag template or flagFormat to check against")
+	}
+	return regexp.Compile(strings.Join(alternatives, "|"))
+}
+
+// flagTemplatePattern turns a GZCTF flag template into a regular expression
+// matching the flags generated from it
+func flagTemplatePattern(template string) string {
+	leet := strings.HasPrefix(template, "[LEET]")
+	template = strings.TrimPrefix(strings.TrimPrefix(template, "[LEET]"), "[CLEAN]")
+
+	pattern := regexp.QuoteMeta(template)
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta("[GUID]"), `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+	pattern = strings.ReplaceAll(pattern, regexp.QuoteMeta("[TEAM_HASH]"), `[0-9a-fA-F]+`)
+	if leet {
+		// Leet substitutes letters, so only the text around the braces is kept
+		if open, close := strings.Index(pattern, `\{`), strings.LastIndex(pattern, `\}`); open >= 0 && close > open {
+			pattern = pattern[:open] + `\{.+?\}` + pattern[close+2:]
+		}
+	}
+	return pattern
+}
+
+// testChallenge runs the solver of a challenge against its local deployment
+func testChallenge(ctx context.Context, challengeConf ChallengeYaml, flagFormat string) SolverResult {
+	port := challengeConf.Container.ContainerExposePort
+	if challengeConf.Solver != nil && challengeConf.Solver.Port != 0 {
+		port = challengeConf.Solver.Port
+	}
+	return runSolver(ctx, challengeConf, flagFormat, solverHost, port)
+}
+
+// runSolver runs the solver of a challenge in a container on the host
+// network against host:port and checks its output for a flag
+func runSolver(ctx context.Context, challengeConf ChallengeYaml, flagFormat string, host string, port int) SolverResult {
+	result := SolverResult{Challenge: challengeConf.Name}
+	start := time.Now()
+	defer func() {
+		result.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	matcher, err := flagMatcher(challengeConf, flagFormat)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	timeout, err := challengeConf.Solver.timeout()
+	if err != nil {
+		result.Error = fmt.Sprintf("invalid solver timeout: %v", err)
+		return result
+	}
+	command := solverCommand(challengeConf)
+	if command == "" {
+		result.Error = "no solver found"
+		return result
+	}
+
+	dir, err := filepath.Abs(filepath.Join(challengeConf.Cwd, solverDir))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	image := defaultSolverImage
+	if challengeConf.Solver != nil && challengeConf.Solver.Image != "" {
+		image = challengeConf.Solver.Image
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "docker", "run", "--rm", "--network", "host",
+		"-v", dir+":/solver", "-w", "/solver",
+		"-e", "TARGET_HOST="+host,
+		"-e", "TARGET_PORT="+strconv.Itoa(port),
+		"--entrypoint", "sh",
+		image, "-c", command)
+	output, err := cmd.CombinedOutput()
+	if flag := matcher.Find(output); flag != nil {
+		result.Passed = true
+		result.Flag = string(flag)
+		return result
+	}
+
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		result.Error = fmt.Sprintf("solver timed out after %s", timeout)
+	case err != nil:
+		result.Error = fmt.Sprintf("%v: %s", err, lastLine(output))
+	default:
+		result.Error = "no flag in solver output: " + lastLine(output)
+	}
+	return result
+}
+
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// TestChallenges runs the solver of every challenge against its local
+// deployment. Challenges without a solver/ directory are skipped
+func TestChallenges(ctx context.Context) ([]SolverResult, error) {
+	config, err := GetConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SolverResult
+	for _, challengeConf := range challengesConf {
+		if _, err := os.Stat(filepath.Join(challengeConf.Cwd, solverDir)); err != nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+		log.Info("Testing %s...", challengeConf.Name)
+		result := testChallenge(ctx, challengeConf, config.FlagFormat)
+		if result.Passed {
+			log.InfoH2("%s solved", challengeConf.Name)
+		} else {
+			log.ErrorH2("%s failed: %s", challengeConf.Name, result.Error)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
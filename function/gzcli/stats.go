@@ -0,0 +1,146 @@
+package gzcli
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+)
+
+const statsBuckets = 10
+
+// Stats is the solve and score statistics of the current game
+type Stats struct {
+	Game         string           `json:"game"`
+	Time         time.Time        `json:"time"`
+	Teams        int              `json:"teams"`
+	Challenges   []ChallengeStats `json:"challenges"`
+	Distribution []ScoreBucket    `json:"distribution"`
+}
+
+// ChallengeStats is the current score and solves of one challenge
+type ChallengeStats struct {
+	Name           string     `json:"name"`
+	Category       string     `json:"category"`
+	Score          int        `json:"score"`
+	Solves         int        `json:"solves"`
+	SolveRate      float64    `json:"solveRate"`
+	FirstBlood     string     `json:"firstBlood,omitempty"`
+	FirstBloodTime *time.Time `json:"firstBloodTime,omitempty"`
+}
+
+// ScoreBucket is the number of teams whose score is in [Min, Max]
+type ScoreBucket struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Teams int `json:"teams"`
+}
+
+// Stats returns the solve and score statistics of the current game
+func (gz *GZ) Stats() (*Stats, error) {
+	game, err := gz.currentGame()
+	if err != nil {
+		return nil, err
+	}
+	scoreboard, err := game.GetScoreboard()
+	if err != nil {
+		return nil, fmt.Errorf("scoreboard error: %w", err)
+	}
+	return scoreboardStats(game.Title, scoreboard), nil
+}
+
+// WatchStats calls fn with fresh statistics every interval until ctx is
+// cancelled
+func (gz *GZ) WatchStats(ctx context.Context, interval time.Duration, fn func(*Stats)) error {
+	game, err := gz.currentGame()
+	if err != nil {
+		return err
+	}
+	return pollScoreboard(ctx, game, interval, func(scoreboard *gzapi.Scoreboard) error {
+		fn(scoreboardStats(game.Title, scoreboard))
+		return nil
+	})
+}
+
+func scoreboardStats(title string, scoreboard *gzapi.Scoreboard) *Stats {
+	stats := &Stats{Game: title, Time: time.Now(), Teams: len(scoreboard.Items)}
+
+	index := map[int]int{}
+	for category, items := range scoreboard.Challenges {
+		for _, item := range items {
+			index[item.Id] = len(stats.Challenges)
+			stats.Challenges = append(stats.Challenges, ChallengeStats{Name: item.Title, Category: category, Score: item.Score})
+		}
+	}
+
+	maxScore := 0
+	for _, item := range scoreboard.Items {
+		maxScore = max(maxScore, item.Score)
+		for _, solve := range item.SolvedChallenges {
+			i, ok := index[solve.Id]
+			if !ok {
+				continue
+			}
+			challenge := &stats.Challenges[i]
+			challenge.Solves++
+			if challenge.FirstBloodTime == nil || solve.Time.Before(*challenge.FirstBloodTime) {
+				t := solve.Time.Time
+				challenge.FirstBlood, challenge.FirstBloodTime = item.Name, &t
+			}
+		}
+	}
+	for i := range stats.Challenges {
+		if stats.Teams > 0 {
+			stats.Challenges[i].SolveRate = float64(stats.Challenges[i].Solves) / float64(stats.Teams)
+		}
+	}
+	sort.Slice(stats.Challenges, func(i, j int) bool {
+		a, b := stats.Challenges[i], stats.Challenges[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Name < b.Name
+	})
+
+	if stats.Teams > 0 {
+		width := maxScore/statsBuckets + 1
+		for i := 0; i < statsBuckets; i++ {
+			stats.Distribution = append(stats.Distribution, ScoreBucket{Min: i * width, Max: (i+1)*width - 1})
+		}
+		for _, item := range scoreboard.Items {
+			stats.Distribution[min(item.Score/width, statsBuckets-1)].Teams++
+		}
+	}
+	return stats
+}
+
+// WriteStatsCSV writes one row per challenge
+func WriteStatsCSV(w io.Writer, stats *Stats) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"category", "challenge", "score", "solves", "solve_rate", "first_blood", "first_blood_time"}); err != nil {
+		return err
+	}
+	for _, challenge := range stats.Challenges {
+		firstBloodTime := ""
+		if challenge.FirstBloodTime != nil {
+			firstBloodTime = challenge.FirstBloodTime.Format(time.RFC3339)
+		}
+		if err := writer.Write([]string{
+			challenge.Category,
+			challenge.Name,
+			fmt.Sprint(challenge.Score),
+			fmt.Sprint(challenge.Solves),
+			fmt.Sprintf("%.3f", challenge.SolveRate),
+			challenge.FirstBlood,
+			firstBloodTime,
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
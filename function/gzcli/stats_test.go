@@ -0,0 +1,61 @@
+package gzcli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+)
+
+func TestScoreboardStats(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	solve := func(id int, after time.Duration) gzapi.ScoreboardSolve {
+		return gzapi.ScoreboardSolve{Id: id, Time: gzapi.CustomTime{Time: start.Add(after)}}
+	}
+	scoreboard := &gzapi.Scoreboard{
+		Challenges: map[string][]gzapi.ScoreboardChallenge{
+			"Web": {{Id: 1, Title: "b", Score: 100}, {Id: 2, Title: "a", Score: 200}},
+			"Pwn": {{Id: 3, Title: "c", Score: 300}},
+		},
+		Items: []gzapi.ScoreboardItem{
+			{Name: "late", Score: 100, SolvedChallenges: []gzapi.ScoreboardSolve{solve(1, time.Hour)}},
+			{Name: "early", Score: 300, SolvedChallenges: []gzapi.ScoreboardSolve{solve(1, time.Minute), solve(2, time.Hour), solve(99, 0)}},
+			{Name: "none"},
+		},
+	}
+	stats := scoreboardStats("game", scoreboard)
+
+	if stats.Teams != 3 {
+		t.Fatalf("teams = %d, want 3", stats.Teams)
+	}
+	for i, tt := range []struct {
+		name       string
+		category   string
+		solves     int
+		firstBlood string
+	}{
+		{"c", "Pwn", 0, ""},
+		{"a", "Web", 1, "early"},
+		{"b", "Web", 2, "early"},
+	} {
+		got := stats.Challenges[i]
+		if got.Name != tt.name || got.Category != tt.category || got.Solves != tt.solves || got.FirstBlood != tt.firstBlood {
+			t.Errorf("challenge %d = %+v, want %s/%s with %d solves and first blood %q", i, got, tt.category, tt.name, tt.solves, tt.firstBlood)
+		}
+		if want := float64(tt.solves) / 3; got.SolveRate != want {
+			t.Errorf("solve rate of %s = %v, want %v", tt.name, got.SolveRate, want)
+		}
+	}
+
+	teams := 0
+	for _, bucket := range stats.Distribution {
+		teams += bucket.Teams
+	}
+	if len(stats.Distribution) != statsBuckets || teams != 3 || stats.Distribution[statsBuckets-1].Teams != 1 {
+		t.Errorf("distribution = %+v, want %d buckets holding 3 teams, the top one in the last", stats.Distribution, statsBuckets)
+	}
+
+	if empty := scoreboardStats("game", &gzapi.Scoreboard{}); empty.Teams != 0 || empty.Distribution != nil {
+		t.Errorf("empty scoreboard stats = %+v", empty)
+	}
+}
@@ -0,0 +1,187 @@
+package gzcli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// STRUCTURE_DIR holds the files every challenge directory should have.
+// Subdirectories named after a lower case category, such as .structure/web,
+// add or override files for the challenges of that category
+const (
+	STRUCTURE_DIR     = ".structure"
+	structureTemplate = ".tmpl"
+)
+
+// StructureResult lists the structure files of a challenge that were
+// created, or that are missing in check mode
+type StructureResult struct {
+	Challenge string
+	Dir       string
+	Created   []string
+	Missing   []string
+}
+
+// structureFiles maps the relative path of every structure file that applies
+// to category to its source
+func structureFiles(category string) (map[string]string, error) {
+	categories := map[string]bool{}
+	for _, c := range CHALLENGE_CATEGORY {
+		categories[strings.ToLower(c)] = true
+	}
+
+	files := map[string]string{}
+	collect := func(root string, skipCategories bool) error {
+		return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, path)
+			if err != nil || rel == "." {
+				return err
+			}
+			if info.IsDir() {
+				if skipCategories && filepath.Dir(rel) == "." && categories[strings.ToLower(rel)] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			files[strings.TrimSuffix(rel, structureTemplate)] = path
+			return nil
+		})
+	}
+
+	if err := collect(STRUCTURE_DIR, true); err != nil {
+		return nil, err
+	}
+	override := filepath.Join(STRUCTURE_DIR, strings.ToLower(category))
+	if info, err := os.Stat(override); err == nil && info.IsDir() {
+		if err := collect(override, false); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// structureVars are the variables .tmpl structure files are rendered with
+func structureVars(challengeConf ChallengeYaml) map[string]any {
+	return map[string]any{
+		"name":     challengeConf.Name,
+		"slug":     generateSlug(challengeConf),
+		"category": challengeConf.Category,
+		"author":   challengeConf.Author,
+		"port":     challengeConf.Container.ContainerExposePort,
+		"host":     hostCache.host,
+	}
+}
+
+// writeStructureFile copies src to dst, rendering it first when it is a
+// .tmpl file
+func writeStructureFile(src, dst string, vars map[string]any) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(src, structureTemplate) {
+		t, err := template.New(filepath.Base(src)).Option("missingkey=error").Parse(string(content))
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, vars); err != nil {
+			return err
+		}
+		content = buf.Bytes()
+	}
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, bytes.NewReader(content))
+	return err
+}
+
+// Structure copies the structure files into every challenge directory that
+// lacks them. Existing files are only replaced with force. With check
+// nothing is written and the missing files are reported instead
+func Structure(check, force bool) ([]StructureResult, error) {
+	if _, err := os.Stat(STRUCTURE_DIR); err != nil {
+		return nil, fmt.Errorf("no %s directory: %w", STRUCTURE_DIR, err)
+	}
+	config, err := GetConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []StructureResult
+	for _, challengeConf := range challengesConf {
+		rel, err := filepath.Rel(dir, challengeConf.Cwd)
+		if err != nil {
+			return nil, err
+		}
+		// The category directory, not challengeConf.Category, picks the
+		// override since Game Hacking challenges are synced as Reverse
+		inRoot, err := filepath.Rel(config.challengeRootDir(dir), challengeConf.Cwd)
+		if err != nil {
+			return nil, err
+		}
+		category := strings.SplitN(filepath.ToSlash(inRoot), "/", 2)[0]
+		files, err := structureFiles(category)
+		if err != nil {
+			return nil, err
+		}
+
+		result := StructureResult{Challenge: challengeConf.Name, Dir: rel}
+		vars := structureVars(challengeConf)
+		for _, name := range sortedKeys(files) {
+			dst := filepath.Join(challengeConf.Cwd, name)
+			_, statErr := os.Stat(dst)
+			exists := statErr == nil
+			switch {
+			case check && !exists:
+				result.Missing = append(result.Missing, name)
+			case check, exists && !force:
+			default:
+				if err := writeStructureFile(files[name], dst, vars); err != nil {
+					return nil, fmt.Errorf("%s: %s: %w", challengeConf.Name, name, err)
+				}
+				result.Created = append(result.Created, name)
+			}
+		}
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Dir < results[j].Dir })
+	return results, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
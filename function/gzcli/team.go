@@ -0,0 +1,172 @@
+package gzcli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+const teamsCredsCacheKey = "teams_creds"
+
+// ListTeamCreds returns the credentials of every generated team
+func ListTeamCreds() ([]*TeamCreds, error) {
+	teamsCreds, err := loadTeamsCreds()
+	if err != nil {
+		return nil, fmt.Errorf("no generated teams found: %w", err)
+	}
+	return teamsCreds, nil
+}
+
+func findTeamCreds(teamsCreds []*TeamCreds, email string) (*TeamCreds, int, error) {
+	for i, creds := range teamsCreds {
+		if strings.EqualFold(creds.Email, email) {
+			return creds, i, nil
+		}
+	}
+	return nil, -1, fmt.Errorf("no generated team for %s", email)
+}
+
+// CreateTeam creates a single team and user like a row of the CSV import and
+// adds the credentials to the cache
+func (gz *GZ) CreateTeam(realName string, email string, teamName string, sendEmail bool) (*TeamCreds, error) {
+	config, err := GetConfig(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	teamsCreds, err := loadTeamsCreds()
+	if err != nil && !isMissingTeamsCreds(err) {
+		return nil, err
+	}
+	if _, _, err := findTeamCreds(teamsCreds, email); err == nil {
+		return nil, fmt.Errorf("%s already has a generated team", email)
+	}
+
+	creds, err := gz.CreteTeamAndUser(&TeamCreds{
+		Username: realName,
+		Email:    email,
+		TeamName: teamName,
+	}, config, map[string]struct{}{}, map[string]struct{}{}, teamsCreds, sendEmail)
+	if err != nil {
+		return nil, err
+	}
+	return creds, saveTeamsCreds(append(teamsCreds, creds))
+}
+
+// DeleteTeam removes the team and user generated for email from the
+// platform and the cache
+func (gz *GZ) DeleteTeam(email string) error {
+	teamsCreds, err := ListTeamCreds()
+	if err != nil {
+		return err
+	}
+	creds, i, err := findTeamCreds(teamsCreds, email)
+	if err != nil {
+		return err
+	}
+
+	teams, err := gz.api.Teams()
+	if err != nil {
+		return err
+	}
+	for _, team := range teams {
+		if team.Name == creds.TeamName {
+			log.Info("%s", log.Msg("team.delete", team.Name))
+			if err := team.Delete(); err != nil {
+				return err
+			}
+		}
+	}
+
+	user, err := gz.FindUser(creds.Username)
+	if err != nil {
+		return err
+	}
+	log.Info("%s", log.Msg("user.delete", user.UserName))
+	if err := user.Delete(); err != nil {
+		return err
+	}
+
+	return saveTeamsCreds(append(teamsCreds[:i], teamsCreds[i+1:]...))
+}
+
+// ResetTeamPassword sets a new random password for the user generated for
+// email and optionally mails it
+func (gz *GZ) ResetTeamPassword(email string, sendEmail bool) (*TeamCreds, error) {
+	teamsCreds, err := ListTeamCreds()
+	if err != nil {
+		return nil, err
+	}
+	creds, _, err := findTeamCreds(teamsCreds, email)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := gz.FindUser(creds.Username)
+	if err != nil {
+		return nil, err
+	}
+	password, err := user.ResetPassword()
+	if err != nil {
+		return nil, err
+	}
+	creds.Password = password
+	if err := saveTeamsCreds(teamsCreds); err != nil {
+		return nil, err
+	}
+
+	if sendEmail {
+		return creds, ResendTeamEmail(email)
+	}
+	return creds, nil
+}
+
+// ResendTeamEmail mails the cached credentials of email again
+func ResendTeamEmail(email string) error {
+	config, err := GetConfig(nil)
+	if err != nil {
+		return err
+	}
+	teamsCreds, err := ListTeamCreds()
+	if err != nil {
+		return err
+	}
+	creds, _, err := findTeamCreds(teamsCreds, email)
+	if err != nil {
+		return err
+	}
+
+	response, err := sendEmail(config, creds.Username, creds)
+	if recordErr := recordEmail(creds, response, err); recordErr != nil {
+		log.ErrorH2("%s", log.Msg("email.record.failed", creds.Email, recordErr))
+	}
+	if err != nil {
+		return err
+	}
+	log.InfoH2("%s", log.Msg("email.sent", creds.Email, response))
+	creds.IsEmailAlreadySent = config.Email.delivers()
+	return saveTeamsCreds(teamsCreds)
+}
+
+// ExportTeamCreds writes the final credentials of every generated team as CSV
+func ExportTeamCreds(w io.Writer) error {
+	teamsCreds, err := ListTeamCreds()
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"Email", "Username", "Password", "TeamName", "Division", "Institution", "EmailSent"})
+	for _, creds := range teamsCreds {
+		cw.Write([]string{
+			creds.Email, creds.Username, creds.Password, creds.TeamName,
+			creds.Division, creds.Institution, strconv.FormatBool(creds.IsEmailAlreadySent),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,223 @@
+package gzcli
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/cache"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// teamsCredsSealedKey holds the team credentials encrypted with
+// CTFIFY_SECRETS_KEY. It replaces the plaintext teams_creds entry once the
+// key is set
+const teamsCredsSealedKey = "teams_creds_sealed"
+
+type sealedTeamsCreds struct {
+	Data string `yaml:"data"`
+}
+
+var plaintextCredsWarning sync.Once
+
+// loadTeamsCreds reads the generated team credentials, decrypting them when
+// they are sealed. It fails with cache.ErrNotFound when there are none and
+// cache.ErrExpired once credsRetention has passed
+func loadTeamsCreds() ([]*TeamCreds, error) {
+	var sealed sealedTeamsCreds
+	err := getLocalCache(teamsCredsSealedKey, &sealed)
+	if errors.Is(err, cache.ErrNotFound) {
+		var teamsCreds []*TeamCreds
+		return teamsCreds, getLocalCache(teamsCredsCacheKey, &teamsCreds)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := secretsPassphrase(); err != nil {
+		return nil, fmt.Errorf("team credentials are encrypted: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", teamsCredsSealedKey, err)
+	}
+	plaintext, err := openSecret(data, teamsCredsSealedKey)
+	if err != nil {
+		return nil, err
+	}
+	var teamsCreds []*TeamCreds
+	if err := json.Unmarshal(plaintext, &teamsCreds); err != nil {
+		return nil, fmt.Errorf("%s: %w", teamsCredsSealedKey, err)
+	}
+	return teamsCreds, nil
+}
+
+// isMissingTeamsCreds reports whether err of loadTeamsCreds only means that
+// no credentials are stored
+func isMissingTeamsCreds(err error) bool {
+	return errors.Is(err, cache.ErrNotFound) || errors.Is(err, cache.ErrExpired)
+}
+
+// saveTeamsCreds stores the generated team credentials, sealed when
+// CTFIFY_SECRETS_KEY is set, until credsRetention after the event end
+func saveTeamsCreds(teamsCreds []*TeamCreds) error {
+	config, err := getLocalConfig()
+	if err != nil {
+		return err
+	}
+	ttl, err := teamsCredsTTL(config, time.Now())
+	if err != nil {
+		return err
+	}
+	store := cacheStore()
+
+	if _, err := secretsPassphrase(); err != nil {
+		if _, statErr := store.Stat(teamsCredsSealedKey); statErr == nil {
+			return fmt.Errorf("team credentials are encrypted: %w", err)
+		}
+		plaintextCredsWarning.Do(func() {
+			log.ErrorH2("%s is not set, team credentials are stored in plaintext", SecretsKeyEnv)
+		})
+		return store.Set(teamsCredsCacheKey, teamsCreds, ttl, "")
+	}
+
+	plaintext, err := json.Marshal(teamsCreds)
+	if err != nil {
+		return err
+	}
+	data, err := sealSecret(plaintext)
+	if err != nil {
+		return err
+	}
+	if err := store.Set(teamsCredsSealedKey, sealedTeamsCreds{Data: base64.StdEncoding.EncodeToString(data)}, ttl, ""); err != nil {
+		return err
+	}
+	if err := store.Delete(teamsCredsCacheKey); err != nil && !errors.Is(err, cache.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// teamsCredsTTL returns how long team credentials saved at now are kept,
+// credsRetention after the event end, or 0 to keep them
+func teamsCredsTTL(config *Config, now time.Time) (time.Duration, error) {
+	if config.CredsRetention == "" || config.Event.End.IsZero() {
+		return 0, nil
+	}
+	retention, err := time.ParseDuration(config.CredsRetention)
+	if err != nil {
+		return 0, fmt.Errorf("invalid credsRetention: %w", err)
+	}
+	// Credentials saved after the retention are dropped on the next read
+	return max(config.Event.End.Add(retention).Sub(now), time.Nanosecond), nil
+}
+
+// RotateTeamPasswords sets a new random password for every generated team
+// captain and optionally mails it. The credentials are saved after every
+// captain, so an interrupted run keeps the passwords already changed
+func (gz *GZ) RotateTeamPasswords(sendEmail bool) ([]*TeamCreds, error) {
+	teamsCreds, err := ListTeamCreds()
+	if err != nil {
+		return nil, err
+	}
+	users, err := gz.api.Users()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]int, len(users))
+	for i, user := range users {
+		byName[user.UserName] = i
+	}
+
+	var rotated []*TeamCreds
+	var problems []string
+	for _, creds := range teamsCreds {
+		i, ok := byName[creds.Username]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: user %s not found", creds.Email, creds.Username))
+			continue
+		}
+		password, err := users[i].ResetPassword()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", creds.Email, err))
+			continue
+		}
+		creds.Password = password
+		creds.IsEmailAlreadySent = false
+		if err := saveTeamsCreds(teamsCreds); err != nil {
+			return rotated, err
+		}
+		rotated = append(rotated, creds)
+		log.InfoH2("%s", log.Msg("team.password.rotated", creds.Username))
+	}
+
+	// Mail once every password is saved, as ResendTeamEmail saves the
+	// credentials it loads itself
+	for _, creds := range rotated {
+		if !sendEmail {
+			break
+		}
+		if err := ResendTeamEmail(creds.Email); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: email: %v", creds.Email, err))
+		}
+	}
+
+	if err := recordOperation("teams.rotate", map[string]string{
+		"rotated": fmt.Sprint(len(rotated)),
+		"failed":  fmt.Sprint(len(problems)),
+	}); err != nil {
+		return rotated, err
+	}
+	if len(problems) > 0 {
+		return rotated, fmt.Errorf("%d teams failed:\n  - %s", len(problems), strings.Join(problems, "\n  - "))
+	}
+	return rotated, nil
+}
+
+// PurgeTeamCreds deletes the stored team credentials, from the cache and
+// from every backup archive. Before the event has ended it refuses unless
+// force is set
+func PurgeTeamCreds(force bool) error {
+	config, err := getLocalConfig()
+	if err != nil {
+		return err
+	}
+	if !force {
+		if config.Event.End.IsZero() {
+			return fmt.Errorf("the event has no end time, use --force to purge anyway")
+		}
+		if time.Now().Before(config.Event.End.Time) {
+			return fmt.Errorf("the event ends %s, use --force to purge before", config.Event.End.Local().Format(time.RFC1123))
+		}
+	}
+
+	keys := []string{teamsCredsSealedKey, teamsCredsCacheKey}
+	purged := 0
+	for _, key := range keys {
+		err := DeleteCache(key)
+		switch {
+		case err == nil:
+			purged++
+		case !errors.Is(err, cache.ErrNotFound):
+			return err
+		}
+	}
+	stripped, err := stripBackups(keys...)
+	if err != nil {
+		return err
+	}
+	if stripped > 0 {
+		log.Info("%s", log.Msg("team.creds.stripped", stripped))
+	}
+	if purged == 0 && stripped == 0 {
+		return fmt.Errorf("no generated teams found")
+	}
+	return recordOperation("teams.purge", map[string]string{
+		"forced":  fmt.Sprint(force),
+		"backups": fmt.Sprint(stripped),
+	})
+}
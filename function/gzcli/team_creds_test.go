@@ -0,0 +1,37 @@
+package gzcli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+)
+
+func TestTeamsCredsTTL(t *testing.T) {
+	end := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name      string
+		retention string
+		end       time.Time
+		now       time.Time
+		want      time.Duration
+		wantErr   bool
+	}{
+		{"no retention", "", end, end, 0, false},
+		{"no event end", "24h", time.Time{}, end, 0, false},
+		{"before end", "24h", end, end.Add(-time.Hour), 25 * time.Hour, false},
+		{"after retention", "24h", end, end.Add(48 * time.Hour), time.Nanosecond, false},
+		{"invalid", "a week", end, end, 0, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{CredsRetention: tt.retention, Event: gzapi.Game{End: gzapi.CustomTime{Time: tt.end}}}
+			got, err := teamsCredsTTL(config, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("ttl = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package gzcli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TeamRules restricts which CSV rows may register. Divisions are keyed by
+// the division of the row and are synced to the game as its organizations
+type TeamRules struct {
+	MaxTeamSize int                     `yaml:"maxTeamSize"`
+	Divisions   map[string]DivisionRule `yaml:"divisions"`
+}
+
+type DivisionRule struct {
+	MaxTeamSize        int  `yaml:"maxTeamSize"`
+	RequireInstitution bool `yaml:"requireInstitution"`
+}
+
+// Optional CSV columns checked against TeamRules
+const (
+	csvMemberCount = "MemberCount"
+	csvDivision    = "Division"
+	csvEligibility = "Eligibility"
+	csvInstitution = "Institution"
+)
+
+// csvColumn returns the value of an optional column, or "" when absent
+func csvColumn(row []string, colIndices map[string]int, column string) string {
+	i, ok := colIndices[column]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// rowDivision returns the division of a CSV row, read from the Division
+// column and then from the older Eligibility column
+func rowDivision(row []string, colIndices map[string]int) string {
+	if division := csvColumn(row, colIndices, csvDivision); division != "" {
+		return division
+	}
+	return csvColumn(row, colIndices, csvEligibility)
+}
+
+// gameDivisions returns the organizations of the event followed by the
+// divisions of the team rules it does not list yet
+func gameDivisions(config *Config) []string {
+	var divisions []string
+	divisions = append(divisions, config.Event.Organizations...)
+	if config.TeamRules == nil {
+		return divisions
+	}
+	seen := make(map[string]bool, len(divisions))
+	for _, division := range divisions {
+		seen[division] = true
+	}
+	var extra []string
+	for name := range config.TeamRules.Divisions {
+		if !seen[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	return append(divisions, extra...)
+}
+
+// validateDivision checks that division is one of the divisions of a game,
+// which then requires every team to have one
+func validateDivision(divisions []string, division string) error {
+	if len(divisions) == 0 {
+		return nil
+	}
+	for _, d := range divisions {
+		if d == division {
+			return nil
+		}
+	}
+	if division == "" {
+		return fmt.Errorf("a division is required, one of: %s", strings.Join(divisions, ", "))
+	}
+	return fmt.Errorf("division %q is not one of: %s", division, strings.Join(divisions, ", "))
+}
+
+// validateTeamRow checks a CSV row against the configured team rules
+func validateTeamRow(rules *TeamRules, row []string, colIndices map[string]int) error {
+	if rules == nil {
+		return nil
+	}
+
+	eligibility := rowDivision(row, colIndices)
+	institution := csvColumn(row, colIndices, csvInstitution)
+	memberCount := csvColumn(row, colIndices, csvMemberCount)
+
+	maxTeamSize := rules.MaxTeamSize
+	if len(rules.Divisions) > 0 {
+		division, ok := rules.Divisions[eligibility]
+		if !ok {
+			allowed := make([]string, 0, len(rules.Divisions))
+			for name := range rules.Divisions {
+				allowed = append(allowed, name)
+			}
+			sort.Strings(allowed)
+			return fmt.Errorf("division %q is not one of: %s", eligibility, strings.Join(allowed, ", "))
+		}
+		if division.MaxTeamSize > 0 {
+			maxTeamSize = division.MaxTeamSize
+		}
+		if division.RequireInstitution && institution == "" {
+			return fmt.Errorf("%s is required for division %s", csvInstitution, eligibility)
+		}
+	}
+
+	if memberCount == "" {
+		return nil
+	}
+	count, err := strconv.Atoi(memberCount)
+	if err != nil || count < 1 {
+		return fmt.Errorf("%s %q is not a positive number", csvMemberCount, memberCount)
+	}
+	if maxTeamSize > 0 && count > maxTeamSize {
+		return fmt.Errorf("%s %d exceeds the maximum team size of %d", csvMemberCount, count, maxTeamSize)
+	}
+	return nil
+}
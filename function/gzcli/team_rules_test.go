@@ -0,0 +1,32 @@
+package gzcli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDivision(t *testing.T) {
+	divisions := []string{"student", "open"}
+	for _, tt := range []struct {
+		name      string
+		divisions []string
+		division  string
+		wantErr   string
+	}{
+		{"no divisions", nil, "", ""},
+		{"no divisions ignores value", nil, "anything", ""},
+		{"known", divisions, "open", ""},
+		{"missing", divisions, "", "a division is required"},
+		{"unknown", divisions, "pro", `division "pro" is not one of: student, open`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDivision(tt.divisions, tt.division)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
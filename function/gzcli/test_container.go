@@ -0,0 +1,129 @@
+package gzcli
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// TestContainer starts the platform test container of a container
+// challenge, or reuses the running one, and returns where it is reachable
+func (gz *GZ) TestContainer(name string) (*gzapi.ContainerInfo, error) {
+	challenge, _, err := gz.containerChallenge(name)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.TestContainer != nil {
+		log.Info("Test container of %s is already %s", name, challenge.TestContainer.Status)
+		return challenge.TestContainer, nil
+	}
+	log.Info("Start test container of %s", name)
+	return challenge.CreateTestContainer()
+}
+
+// SolveTestContainer starts the test container of a challenge, runs its
+// solver/ against it and stops the container again
+func (gz *GZ) SolveTestContainer(ctx context.Context, name string) (*gzapi.ContainerInfo, *SolverResult, error) {
+	challenge, config, err := gz.containerChallenge(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	challengesConf, err := GetChallengesYaml(config)
+	if err != nil {
+		return nil, nil, err
+	}
+	var challengeConf *ChallengeYaml
+	for i := range challengesConf {
+		if challengesConf[i].Name == name {
+			challengeConf = &challengesConf[i]
+			break
+		}
+	}
+	if challengeConf == nil {
+		return nil, nil, fmt.Errorf("challenge %q not found in the repository", name)
+	}
+	if err := resolveChallengeSecrets(challengeConf); err != nil {
+		return nil, nil, err
+	}
+	return solveInTestContainer(ctx, challenge, *challengeConf, config.FlagFormat)
+}
+
+// solveInTestContainer runs the solver of challengeConf against the test
+// container of challenge, starting it first and stopping it afterwards when
+// it was not running yet
+func solveInTestContainer(ctx context.Context, challenge *gzapi.Challenge, challengeConf ChallengeYaml, flagFormat string) (*gzapi.ContainerInfo, *SolverResult, error) {
+	name := challengeConf.Name
+	info := challenge.TestContainer
+	if info == nil {
+		log.Info("Start test container of %s", name)
+		var err error
+		if info, err = challenge.CreateTestContainer(); err != nil {
+			return nil, nil, err
+		}
+		defer func() {
+			log.Info("Stop test container of %s", name)
+			if err := challenge.DestroyTestContainer(); err != nil {
+				log.Error("Failed to stop test container of %s: %v", name, err)
+			}
+		}()
+	}
+
+	host, port, err := splitEntry(info.Entry)
+	if err != nil {
+		return info, nil, err
+	}
+	log.Info("Run solver of %s against %s", name, info.Entry)
+	result := runSolver(ctx, challengeConf, flagFormat, host, port)
+	return info, &result, nil
+}
+
+// StopTestContainer stops the test container of a challenge
+func (gz *GZ) StopTestContainer(name string) error {
+	challenge, _, err := gz.containerChallenge(name)
+	if err != nil {
+		return err
+	}
+	if challenge.TestContainer == nil {
+		return fmt.Errorf("%s has no test container", name)
+	}
+	return challenge.DestroyTestContainer()
+}
+
+// containerChallenge looks up a container challenge of the current game
+func (gz *GZ) containerChallenge(name string) (*gzapi.Challenge, *Config, error) {
+	config, err := lookupConfig(gz.api)
+	if err != nil {
+		return nil, nil, err
+	}
+	game, err := gz.api.GetGameById(config.Event.Id)
+	if err != nil {
+		return nil, nil, err
+	}
+	challenge, err := game.GetChallenge(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get challenge %s: %w", name, err)
+	}
+	if !strings.HasSuffix(challenge.Type, "Container") {
+		return nil, nil, fmt.Errorf("%s is a %s challenge, test containers need a container challenge", name, challenge.Type)
+	}
+	return challenge, config, nil
+}
+
+// splitEntry splits the host:port entry of a container. Proxied containers
+// only have a guid, which a solver cannot connect to
+func splitEntry(entry string) (string, int, error) {
+	host, port, err := net.SplitHostPort(entry)
+	if err != nil {
+		return "", 0, fmt.Errorf("entry %q is not host:port, the platform proxies containers (PlatformProxy) so the solver cannot reach it", entry)
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return "", 0, fmt.Errorf("entry %q has an invalid port", entry)
+	}
+	return host, n, nil
+}
@@ -0,0 +1,59 @@
+package gzcli
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// UpdateRule maps changed files matching Pattern to the update they need.
+// A pattern ending in / matches everything below that directory, a pattern
+// without / is matched against the file name, any other pattern against the
+// path relative to the challenge directory
+type UpdateRule struct {
+	Pattern string     `yaml:"pattern"`
+	Update  UpdateType `yaml:"update"`
+}
+
+// defaultUpdateRules are used after the rules of challenge.yml and conf.yaml
+var defaultUpdateRules = []UpdateRule{
+	{Pattern: "src/", Update: UpdateFullRedeploy},
+	{Pattern: "Dockerfile", Update: UpdateFullRedeploy},
+	{Pattern: "docker-compose*", Update: UpdateFullRedeploy},
+	{Pattern: "dist/", Update: UpdateAttachment},
+}
+
+func (r UpdateRule) match(file string) bool {
+	if dir, ok := strings.CutSuffix(r.Pattern, "/"); ok {
+		return strings.HasPrefix(file, dir+"/")
+	}
+	name := file
+	if !strings.Contains(r.Pattern, "/") {
+		name = path.Base(file)
+	}
+	ok, _ := path.Match(r.Pattern, name)
+	return ok
+}
+
+// validateUpdateRules checks the patterns and update types of rules
+func validateUpdateRules(rules []UpdateRule) error {
+	for _, rule := range rules {
+		if _, ok := updateTypeRank[rule.Update]; !ok {
+			return fmt.Errorf("update rule %q: unknown update %q, expected none, metadata, attachment or redeploy", rule.Pattern, rule.Update)
+		}
+		if _, err := path.Match(strings.TrimSuffix(rule.Pattern, "/"), ""); rule.Pattern == "" || err != nil {
+			return fmt.Errorf("update rule %q: invalid pattern", rule.Pattern)
+		}
+	}
+	return nil
+}
+
+// matchUpdateRules returns the update of the first rule matching file
+func matchUpdateRules(rules []UpdateRule, file string) (UpdateType, bool) {
+	for _, rule := range rules {
+		if rule.match(file) {
+			return rule.Update, true
+		}
+	}
+	return UpdateNone, false
+}
@@ -0,0 +1,123 @@
+package gzcli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+)
+
+// UserFilter narrows the accounts listed by gzcli user list and removed by
+// --delete-all-user and gzcli user delete
+type UserFilter struct {
+	ExcludeAdmins  bool
+	OnlyUnverified bool
+	Role           string
+	Search         string
+}
+
+var userRoles = []string{gzapi.RoleAdmin, gzapi.RoleMonitor, gzapi.RoleUser, gzapi.RoleBanned}
+
+func (f UserFilter) match(user *gzapi.User) bool {
+	if f.ExcludeAdmins && user.Role == gzapi.RoleAdmin {
+		return false
+	}
+	if f.OnlyUnverified && user.EmailConfirmed {
+		return false
+	}
+	if f.Role != "" && !strings.EqualFold(user.Role, f.Role) {
+		return false
+	}
+	return true
+}
+
+// ListUsers returns the accounts matched by filter. A search is done by the
+// platform, the other fields are applied locally
+func (gz *GZ) ListUsers(filter UserFilter) ([]*gzapi.User, error) {
+	if filter.Role != "" {
+		role, err := normalizeRole(filter.Role)
+		if err != nil {
+			return nil, err
+		}
+		filter.Role = role
+	}
+
+	var users []*gzapi.User
+	var err error
+	if filter.Search != "" {
+		users, err = gz.api.SearchUsers(filter.Search)
+	} else {
+		users, err = gz.api.Users()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []*gzapi.User
+	for _, user := range users {
+		if filter.match(user) {
+			matched = append(matched, user)
+		}
+	}
+	return matched, nil
+}
+
+// FindUser returns the account whose username or email is exactly ident
+func (gz *GZ) FindUser(ident string) (*gzapi.User, error) {
+	users, err := gz.api.SearchUsers(ident)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.UserName, ident) || strings.EqualFold(user.Email, ident) {
+			return user, nil
+		}
+	}
+	return nil, fmt.Errorf("no user with username or email %s", ident)
+}
+
+// SetUserRole changes the role of an account. Banning is the Banned role
+func (gz *GZ) SetUserRole(ident string, role string) (*gzapi.User, error) {
+	role, err := normalizeRole(role)
+	if err != nil {
+		return nil, err
+	}
+	user, err := gz.FindUser(ident)
+	if err != nil {
+		return nil, err
+	}
+	if err := user.Update(&gzapi.UserUpdateForm{Role: &role}); err != nil {
+		return nil, err
+	}
+	previous := user.Role
+	user.Role = role
+	return user, recordOperation("user.role", map[string]string{
+		"user": user.UserName,
+		"from": previous,
+		"to":   role,
+	})
+}
+
+// ActivateUser marks the email of an account as confirmed so it can log in
+// without the confirmation mail
+func (gz *GZ) ActivateUser(ident string) (*gzapi.User, error) {
+	user, err := gz.FindUser(ident)
+	if err != nil {
+		return nil, err
+	}
+	confirmed := true
+	if err := user.Update(&gzapi.UserUpdateForm{EmailConfirmed: &confirmed}); err != nil {
+		return nil, err
+	}
+	user.EmailConfirmed = true
+	return user, recordOperation("user.activate", map[string]string{"user": user.UserName})
+}
+
+func normalizeRole(role string) (string, error) {
+	for _, r := range userRoles {
+		if strings.EqualFold(r, role) {
+			return r, nil
+		}
+	}
+	return "", fmt.Errorf("unknown role %q, expected one of: %s", role, strings.Join(userRoles, ", "))
+}
@@ -1,17 +1,11 @@
 package gzcli
 
 import (
-	"archive/zip"
-	"bufio"
 	"bytes"
-	"compress/flate"
 	"crypto/sha256"
 	"fmt"
-	"io"
 	"os"
-	"path/filepath"
 	"regexp"
-	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -48,6 +42,10 @@ func NormalizeFileName(name string) string {
 }
 
 func ParseYamlFromBytes(b []byte, data any) error {
+	b, err := expandSecretTags(b)
+	if err != nil {
+		return fmt.Errorf("error unmarshal yaml: %w", err)
+	}
 	if err := yaml.Unmarshal(b, data); err != nil {
 		return fmt.Errorf("error unmarshal yaml: %w", err)
 	}
@@ -148,128 +146,9 @@ func isFlagExist(flag string, flags []gzapi.Flag) bool {
 	return exists
 }
 
-func zipSource(source, target string) error {
-	// Create output file with buffered writer
-	f, err := os.Create(target)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	buffered := bufio.NewWriterSize(f, 1<<20) // 1MB buffer
-	defer buffered.Flush()
-
-	// Create zip writer with optimized compression
-	writer := zip.NewWriter(buffered)
-	defer writer.Close()
-
-	// Set faster compression level
-	writer.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
-		return flate.NewWriter(w, flate.BestSpeed)
-	})
-
-	// Pre-allocate buffer pool
-	bufPool := sync.Pool{
-		New: func() interface{} { return make([]byte, 32<<10) }, // 32KB buffers
-	}
-
-	// Collect files first to enable parallel processing
-	var filePaths []string
-	filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return err
-		}
-		filePaths = append(filePaths, path)
-		return nil
-	})
-
-	// Process files in parallel but write sequentially
-	type result struct {
-		path string
-		data []byte
-		err  error
-	}
-	resultChan := make(chan result, len(filePaths))
-
-	// Worker pool for parallel reading
-	sem := make(chan struct{}, runtime.NumCPU())
-	var wg sync.WaitGroup
-
-	for _, path := range filePaths {
-		wg.Add(1)
-		go func(p string) {
-			defer wg.Done()
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			// Read file content
-			data, err := os.ReadFile(p)
-			resultChan <- result{p, data, err}
-		}(path)
-	}
-
-	go func() {
-		wg.Wait()
-		close(resultChan)
-	}()
-
-	// Write results in original order while maintaining directory structure
-	writtenFiles := make(map[string]struct{})
-	for res := range resultChan {
-		if res.err != nil {
-			return res.err
-		}
-
-		relPath, err := filepath.Rel(source, res.path)
-		if err != nil {
-			return err
-		}
-
-		// Ensure directory entries exist
-		dirPath := filepath.Dir(relPath)
-		if dirPath != "." {
-			if _, exists := writtenFiles[dirPath]; !exists {
-				header := &zip.FileHeader{
-					Name:     dirPath + "/",
-					Method:   zip.Deflate,
-					Modified: time.Now(),
-				}
-				if _, err := writer.CreateHeader(header); err != nil {
-					return err
-				}
-				writtenFiles[dirPath] = struct{}{}
-			}
-		}
-
-		// Create file header
-		header := &zip.FileHeader{
-			Name:     relPath,
-			Method:   zip.Deflate,
-			Modified: time.Now(),
-		}
-		header.SetMode(0644)
-
-		// Use buffer from pool
-		buf := bufPool.Get().([]byte)
-		defer bufPool.Put(&buf)
-
-		// Write to zip
-		w, err := writer.CreateHeader(header)
-		if err != nil {
-			return err
-		}
-
-		if _, err := io.CopyBuffer(w, bytes.NewReader(res.data), buf); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func isConfigEdited(challengeConf *ChallengeYaml, challengeData *gzapi.Challenge) bool {
+func isConfigEdited(cacheKey string, challengeData *gzapi.Challenge) bool {
 	var cacheChallenge gzapi.Challenge
-	if err := GetCache(challengeConf.Category+"/"+challengeConf.Name+"/challenge", &cacheChallenge); err != nil {
+	if err := GetCache(cacheKey, &cacheChallenge); err != nil {
 		return true
 	}
 
@@ -279,19 +158,33 @@ func isConfigEdited(challengeConf *ChallengeYaml, challengeData *gzapi.Challenge
 	return !cmp.Equal(*challengeData, cacheChallenge)
 }
 
-func mergeChallengeData(challengeConf *ChallengeYaml, challengeData *gzapi.Challenge) *gzapi.Challenge {
-	// Set defaults using bitwise OR to avoid branching
-	challengeData.MemoryLimit |= 128
-	challengeData.CpuCount |= 1
-	challengeData.StorageLimit |= 128
+// Container limits used when challenge.yml leaves them out or sets them to 0
+const (
+	defaultMemoryLimit  = 128
+	defaultCpuCount     = 1
+	defaultStorageLimit = 128
+)
+
+// orDefault returns value, or def when value is 0
+func orDefault(value, def int) int {
+	if value == 0 {
+		return def
+	}
+	return value
+}
+
+func mergeChallengeData(challengeConf *ChallengeYaml, challengeData *gzapi.Challenge, start time.Time) *gzapi.Challenge {
+	challengeData.MemoryLimit = orDefault(challengeConf.Container.MemoryLimit, defaultMemoryLimit)
+	challengeData.CpuCount = orDefault(challengeConf.Container.CpuCount, defaultCpuCount)
+	challengeData.StorageLimit = orDefault(challengeConf.Container.StorageLimit, defaultStorageLimit)
 
 	challengeData.Title = challengeConf.Name
 	challengeData.Category = challengeConf.Category
 	challengeData.Content = fmt.Sprintf("Author: **%s**\n\n%s", challengeConf.Author, challengeConf.Description)
 	challengeData.Type = challengeConf.Type
-	challengeData.Hints = challengeConf.Hints
+	challengeData.Hints = releasedHints(challengeConf.Hints, start, time.Now())
 	challengeData.FlagTemplate = challengeConf.Container.FlagTemplate
-	challengeData.ContainerImage = challengeConf.Container.ContainerImage
+	challengeData.ContainerImage = pinnedImage(challengeConf.Container.ContainerImage)
 	challengeData.ContainerExposePort = challengeConf.Container.ContainerExposePort
 	challengeData.EnableTrafficCapture = challengeConf.Container.EnableTrafficCapture
 	challengeData.OriginalScore = challengeConf.Value
@@ -304,3 +197,44 @@ func mergeChallengeData(challengeConf *ChallengeYaml, challengeData *gzapi.Chall
 
 	return challengeData
 }
+
+var sizeUnits = map[string]int64{
+	"":   1,
+	"B":  1,
+	"KB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+	"TB": 1 << 40,
+}
+
+// ParseSize converts a human size such as "50MB" or "2GB" into bytes
+func ParseSize(size string) (int64, error) {
+	size = strings.ToUpper(strings.TrimSpace(size))
+	i := strings.IndexFunc(size, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i == -1 {
+		i = len(size)
+	}
+
+	unit, ok := sizeUnits[strings.TrimSpace(size[i:])]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit in %q", size)
+	}
+
+	var value float64
+	if _, err := fmt.Sscanf(size[:i], "%g", &value); err != nil {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	return int64(value * float64(unit)), nil
+}
+
+// FormatSize renders a byte count using the largest fitting unit
+func FormatSize(size int64) string {
+	for _, unit := range []string{"TB", "GB", "MB", "KB"} {
+		if size >= sizeUnits[unit] {
+			return fmt.Sprintf("%.1f%s", float64(size)/float64(sizeUnits[unit]), unit)
+		}
+	}
+	return fmt.Sprintf("%dB", size)
+}
@@ -0,0 +1,29 @@
+package gzcli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+)
+
+func TestMergeChallengeDataLimits(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		container Container
+		want      [3]int
+	}{
+		{"defaults", Container{}, [3]int{defaultMemoryLimit, defaultCpuCount, defaultStorageLimit}},
+		{"set", Container{MemoryLimit: 256, CpuCount: 2, StorageLimit: 512}, [3]int{256, 2, 512}},
+		{"partial", Container{MemoryLimit: 64}, [3]int{64, defaultCpuCount, defaultStorageLimit}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// Limits left on the platform must not leak into the result
+			data := &gzapi.Challenge{MemoryLimit: 1024, CpuCount: 8, StorageLimit: 1024}
+			mergeChallengeData(&ChallengeYaml{Container: tt.container}, data, time.Time{})
+			if got := [3]int{data.MemoryLimit, data.CpuCount, data.StorageLimit}; got != tt.want {
+				t.Fatalf("limits = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
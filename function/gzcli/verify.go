@@ -0,0 +1,52 @@
+package gzcli
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/gzcli/gzapi"
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// verifiedFields are the challenge fields sync sets through mergeChallengeData
+var verifiedFields = []string{
+	"Title", "Category", "Content", "Type", "Hints", "FlagTemplate",
+	"ContainerImage", "MemoryLimit", "CpuCount", "StorageLimit",
+	"ContainerExposePort", "EnableTrafficCapture", "OriginalScore", "MinScoreRate",
+}
+
+// diffChallenge lists the verified fields where live differs from intended
+func diffChallenge(intended, live *gzapi.Challenge) []string {
+	intendedValue := reflect.ValueOf(*intended)
+	liveValue := reflect.ValueOf(*live)
+
+	var diffs []string
+	for _, field := range verifiedFields {
+		want := intendedValue.FieldByName(field).Interface()
+		got := liveValue.FieldByName(field).Interface()
+		if field == "Hints" && len(want.([]string)) == 0 && len(got.([]string)) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(want, got) {
+			diffs = append(diffs, fmt.Sprintf("%s: intended %#v, live %#v", field, want, got))
+		}
+	}
+	return diffs
+}
+
+// verifyChallenge re-fetches a synced challenge and fails with the fields
+// the platform normalized or silently rejected
+func verifyChallenge(intended *gzapi.Challenge) error {
+	live, err := intended.Refresh()
+	if err != nil {
+		return fmt.Errorf("verify challenge %s: %w", intended.Title, err)
+	}
+
+	diffs := diffChallenge(intended, live)
+	if len(diffs) > 0 {
+		return fmt.Errorf("platform state of %s differs from the intended state:\n  - %s", intended.Title, strings.Join(diffs, "\n  - "))
+	}
+	log.InfoH2("Verified %s against the platform", intended.Title)
+	return nil
+}
@@ -0,0 +1,114 @@
+package gzcli
+
+import (
+	"archive/zip"
+	"bufio"
+	"compress/flate"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/log"
+)
+
+// zipCacheDir holds the zipped attachments and their manifests inside the
+// cache directory. Backups skip it since it is rebuilt from the sources
+const zipCacheDir = "zip"
+
+// zipEpoch is the modification time of every zip entry, so the same files
+// always zip to the same bytes and the same attachment hash
+var zipEpoch = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// zipSource zips the directory source into target, streaming every file
+// in walk order
+func zipSource(source, target string) error {
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buffered := bufio.NewWriterSize(f, 1<<20)
+	writer := zip.NewWriter(buffered)
+	writer.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, flate.BestSpeed)
+	})
+
+	buf := make([]byte, 32<<10)
+	err = filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		header := &zip.FileHeader{
+			Name:     filepath.ToSlash(rel),
+			Method:   zip.Deflate,
+			Modified: zipEpoch,
+		}
+		if info.IsDir() {
+			header.Name += "/"
+			_, err := writer.CreateHeader(header)
+			return err
+		}
+		header.SetMode(0644)
+
+		w, err := writer.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.CopyBuffer(w, src, buf)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return buffered.Flush()
+}
+
+// zipChallengeSource zips the directory source of a challenge into the zip
+// cache as name. The archive is only rebuilt when the hash of a file changed
+// since the last build, otherwise the cached archive is returned
+func zipChallengeSource(challengeConf ChallengeYaml, source, name string) (string, error) {
+	slug := generateSlug(challengeConf)
+	manifestKey := filepath.Join(zipCacheDir, slug, "manifest")
+	target := filepath.Join(cacheDir, zipCacheDir, slug, name)
+
+	manifest, err := hashFiles(source)
+	if err != nil {
+		return "", err
+	}
+	var cached fileManifest
+	if err := GetCache(manifestKey, &cached); err == nil && maps.Equal(cached, manifest) {
+		if _, err := os.Stat(target); err == nil {
+			log.InfoH3("Attachment of %s is unchanged, reusing %s", challengeConf.Name, name)
+			return target, nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", err
+	}
+	tmp := target + ".tmp"
+	if err := zipSource(source, tmp); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return "", err
+	}
+	return target, setCache(manifestKey, manifest)
+}
@@ -0,0 +1,126 @@
+package log
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// catalog maps the id of a message to its format. It covers the operator
+// facing output of challenge sync, team management, emails, monitor and
+// downloads, LoadCatalog rewords them so output read by log aggregators,
+// status screens and emails can be made consistent without touching the
+// code. Other output is not in the catalog
+var catalog = map[string]string{
+	"download.success": "success downloading: %s (%s)",
+
+	"monitor.subscribed": "Subscribed to events of %s",
+	"monitor.event":      "[%s] %s %s/%s %s",
+	"monitor.submission": "[%s] %s %s/%s on %s",
+
+	"sync.synced":               "[%d/%d] Synced %s",
+	"sync.failed":               "[%d/%d] Failed to sync %s: %v",
+	"sync.game.create":          "Create new game",
+	"sync.game.update":          "Updated %s game",
+	"sync.challenge.create":     "Create challenge %s",
+	"sync.challenge.update":     "Update challenge %s",
+	"sync.challenge.unchanged":  "Challenge %s is the same...",
+	"sync.attachment.remote":    "Create remote attachment for %s",
+	"sync.attachment.local":     "Create local attachment for %s",
+	"sync.attachment.zip":       "Zip attachment for %s",
+	"sync.attachment.update":    "Update attachment for %s",
+	"sync.attachment.delete":    "Delete attachment for %s",
+	"sync.attachment.cached":    "Attachment for %s is unchanged, skipping upload",
+	"sync.attachment.unchanged": "Attachment for %s is the same...",
+
+	"team.create":           "Creating user %s with team %s",
+	"team.created":          "Team %s already created",
+	"team.exists":           "Team %s already exist",
+	"team.create.failed":    "Failed to create team %s: %v",
+	"team.registered":       "Registered %s to %s",
+	"team.register.failed":  "Failed to register %s: %v",
+	"team.password.rotated": "Rotated the password of %s",
+	"team.creds.stripped":   "Removed team credentials from %d backups",
+	"team.delete":           "Delete team %s",
+	"user.delete":           "Delete user %s",
+
+	"email.subject":       "Your Team Credentials",
+	"email.sent":          "Email to %s %s",
+	"email.failed":        "Failed to send email to %s: %v",
+	"email.already.sent":  "Email to %s already sended before",
+	"email.record.failed": "Failed to record email to %s: %v",
+}
+
+// verbRegex matches a fmt verb with its flags, width and precision
+var verbRegex = regexp.MustCompile(`%[-+# 0]*(?:\d+|\*)?(?:\.(?:\d+|\*)?)?(?:\[\d+\])?([a-zA-Z%])`)
+
+// Msg formats the catalog message id with elem
+func Msg(id string, elem ...any) string {
+	format, ok := catalog[id]
+	if !ok {
+		return id
+	}
+	return fmt.Sprintf(format, elem...)
+}
+
+// LoadCatalog overrides messages with the id: format pairs of a YAML file.
+// An override must keep the format verbs of the message it replaces in the
+// same order, since the arguments passed to it do not change. %v may stand
+// for any verb
+func LoadCatalog(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("message catalog: %w", err)
+	}
+	var overrides map[string]string
+	if err := yaml.Unmarshal(content, &overrides); err != nil {
+		return fmt.Errorf("message catalog %s: %w", path, err)
+	}
+
+	var problems []string
+	for id, format := range overrides {
+		current, ok := catalog[id]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown message %s", id))
+			continue
+		}
+		if !sameVerbs(verbs(current), verbs(format)) {
+			problems = append(problems, fmt.Sprintf("%s needs the format verbs %s like %q", id, strings.Join(verbs(current), " "), current))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("message catalog %s: %s", path, strings.Join(problems, ", "))
+	}
+	for id, format := range overrides {
+		catalog[id] = format
+	}
+	return nil
+}
+
+// verbs returns the verbs of format in order, like %s or %d
+func verbs(format string) []string {
+	var result []string
+	for _, match := range verbRegex.FindAllStringSubmatch(format, -1) {
+		if match[1] != "%" {
+			result = append(result, "%"+match[1])
+		}
+	}
+	return result
+}
+
+func sameVerbs(want, got []string) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if got[i] != want[i] && got[i] != "%v" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,46 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		yaml    string
+		wantErr string
+		want    string
+	}{
+		{"reword", `sync.synced: "sync %d of %d: %s"`, "", "sync 1 of 2: web"},
+		{"any verb", `sync.synced: "%v/%v %v"`, "", "1/2 web"},
+		{"escaped percent", `sync.synced: "%d/%d 100%% %s"`, "", "1/2 100% web"},
+		{"verb kind", `sync.synced: "%s/%d %s"`, "needs the format verbs %d %d %s", ""},
+		{"verb count", `sync.synced: "%d %s"`, "needs the format verbs", ""},
+		{"unknown", `nope: "x"`, "unknown message nope", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := catalog["sync.synced"]
+			defer func() { catalog["sync.synced"] = saved }()
+
+			path := filepath.Join(t.TempDir(), "messages.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0600); err != nil {
+				t.Fatal(err)
+			}
+			err := LoadCatalog(path)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+			if tt.want != "" {
+				if got := Msg("sync.synced", 1, 2, "web"); got != tt.want {
+					t.Fatalf("message = %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -8,6 +8,39 @@ import (
 	"github.com/fatih/color"
 )
 
+// plain drops colors and replaces the "[x]" markers with level names so the
+// output stays parseable by terminals and log aggregators without ANSI support
+var plain bool
+
+func init() {
+	if os.Getenv("CTFIFY_PLAIN") != "" {
+		SetPlain(true)
+	}
+	if path := os.Getenv("CTFIFY_MESSAGES"); path != "" {
+		if err := LoadCatalog(path); err != nil {
+			Error("%v", err)
+		}
+	}
+}
+
+// SetPlain toggles plain output mode
+func SetPlain(enabled bool) {
+	plain = enabled
+	if enabled {
+		color.NoColor = true
+	}
+}
+
+// prefix returns the marker printed in front of a message of the given level
+// and indentation depth
+func prefix(level string, depth int, colorize func(string, ...interface{}) string) string {
+	indent := strings.Repeat("  ", depth)
+	if plain {
+		return indent + level + ": "
+	}
+	return colorize(indent + "[x] ")
+}
+
 func Fatal(args ...interface{}) {
 	var message string
 
@@ -37,31 +70,31 @@ func Fatal(args ...interface{}) {
 	// Format and print the error message
 	lines := strings.Split(strings.TrimSpace(message), "\n")
 	for _, line := range lines {
-		fmt.Fprintln(os.Stderr, color.RedString("[x] ")+line)
+		fmt.Fprintln(os.Stderr, prefix("FATAL", 0, color.RedString)+line)
 	}
 	os.Exit(1)
 }
 
 func Error(str string, elem ...any) {
-	fmt.Fprintln(os.Stderr, color.RedString("[x] ")+fmt.Sprintf(str, elem...))
+	fmt.Fprintln(os.Stderr, prefix("ERROR", 0, color.RedString)+fmt.Sprintf(str, elem...))
 }
 
 func ErrorH2(format string, elem ...any) {
-	fmt.Fprintln(os.Stderr, color.RedString("  [x] ")+fmt.Sprintf(format, elem...))
+	fmt.Fprintln(os.Stderr, prefix("ERROR", 1, color.RedString)+fmt.Sprintf(format, elem...))
 }
 
 func Info(format string, elem ...any) {
-	fmt.Println(color.BlueString("[x] ") + fmt.Sprintf(format, elem...))
+	fmt.Println(prefix("INFO", 0, color.BlueString) + fmt.Sprintf(format, elem...))
 }
 
 func InfoH2(format string, elem ...any) {
-	fmt.Println(color.GreenString("  [x] ") + fmt.Sprintf(format, elem...))
+	fmt.Println(prefix("INFO", 1, color.GreenString) + fmt.Sprintf(format, elem...))
 }
 
 func InfoH3(format string, elem ...any) {
-	fmt.Println(color.YellowString("    [x] ") + fmt.Sprintf(format, elem...))
+	fmt.Println(prefix("INFO", 2, color.YellowString) + fmt.Sprintf(format, elem...))
 }
 
 func SuccessDownload(challName string, challCategory string) {
-	Info("success downloading: %s (%s)", challName, challCategory)
+	Info("%s", Msg("download.success", challName, challCategory))
 }
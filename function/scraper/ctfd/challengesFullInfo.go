@@ -16,6 +16,7 @@ type ChallengeFullInfo struct {
 	Solves          int
 	SolvedByMe      bool
 	Files           []fileUrl
+	Hints           []Hint
 }
 
 // Same as WriteTemplatesToDir but use default directory `template`
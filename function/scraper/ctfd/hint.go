@@ -0,0 +1,51 @@
+package ctfd
+
+import (
+	"strconv"
+
+	"github.com/dimasma0305/ctfify/function/utils"
+)
+
+type Hint struct {
+	Id      int
+	Cost    int
+	Content string
+}
+
+type Flag struct {
+	Id      int
+	Type    string
+	Content string
+}
+
+// GetContent returns the hint content. CTFd only reveals it to admins or
+// after the hint is unlocked, this never unlocks a hint
+func (h *Hint) GetContent() (string, error) {
+	if h.Content != "" {
+		return h.Content, nil
+	}
+	var data Hint
+	res, err := scraper.client.R().Get(utils.UrlJoinPath(scraper.hintsUrl, strconv.Itoa(h.Id)))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+	if err := getData(res.Bytes(), &data); err != nil {
+		return "", err
+	}
+	return data.Content, nil
+}
+
+// GetFlags returns the flags of the challenge, only available to admins
+func (cfi *ChallengeFullInfo) GetFlags() ([]Flag, error) {
+	var data []Flag
+	res, err := scraper.client.R().Get(utils.UrlJoinPath(scraper.challengesUrl, strconv.Itoa(cfi.Id), "flags"))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if err := getData(res.Bytes(), &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,201 @@
+package selfupdate
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/imroc/req/v3"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	repo          = "dimasma0305/ctfify"
+	checksumsFile = "checksums.txt"
+)
+
+// Release channels
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+)
+
+type Asset struct {
+	Name string `json:"name"`
+	Url  string `json:"browser_download_url"`
+}
+
+type Release struct {
+	TagName    string  `json:"tag_name"`
+	Draft      bool    `json:"draft"`
+	Prerelease bool    `json:"prerelease"`
+	Assets     []Asset `json:"assets"`
+}
+
+var client = req.C().SetUserAgent("ctfify-self-update")
+
+// AssetName is the release binary for this platform, as built by make release
+func AssetName() string {
+	name := fmt.Sprintf("ctfify_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Latest returns the release of channel with the highest semantic version,
+// whatever order GitHub lists them in. The beta channel includes
+// pre-releases
+func Latest(channel string) (*Release, error) {
+	if channel != ChannelStable && channel != ChannelBeta {
+		return nil, fmt.Errorf("unknown channel %q, expected %s or %s", channel, ChannelStable, ChannelBeta)
+	}
+	var releases []Release
+	resp, err := client.R().SetSuccessResult(&releases).Get("https://api.github.com/repos/" + repo + "/releases")
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccessState() {
+		return nil, fmt.Errorf("listing releases failed: %s", resp.Status)
+	}
+	var latest *Release
+	for i := range releases {
+		release := &releases[i]
+		if release.Draft || (release.Prerelease && channel != ChannelBeta) || !semver.IsValid(canonical(release.TagName)) {
+			continue
+		}
+		if latest == nil || release.Newer(latest.TagName) {
+			latest = release
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no %s release found", channel)
+	}
+	return latest, nil
+}
+
+// Newer reports whether the release is a higher semantic version than
+// version. Any release is newer than a version that is not semantic, like
+// the dev builds
+func (r *Release) Newer(version string) bool {
+	if !semver.IsValid(canonical(version)) {
+		return true
+	}
+	return semver.Compare(canonical(r.TagName), canonical(version)) > 0
+}
+
+// canonical adds the v prefix semver expects to tags like 1.2.3
+func canonical(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+	return "v" + version
+}
+
+func (r *Release) asset(name string) (*Asset, error) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("release %s has no %s", r.TagName, name)
+}
+
+// Apply downloads the binary of this platform from release, checks it
+// against the checksums.txt of the release and replaces the running
+// executable with it. The checksum comes from the same release, so it
+// catches corrupted downloads but not a tampered release
+func Apply(release *Release) error {
+	binary, err := release.asset(AssetName())
+	if err != nil {
+		return err
+	}
+	checksums, err := release.asset(checksumsFile)
+	if err != nil {
+		return err
+	}
+	want, err := expectedChecksum(checksums.Url, binary.Name)
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+
+	// download next to the executable so the final rename stays on one filesystem
+	tmp, err := os.CreateTemp(filepath.Dir(exe), ".ctfify-update-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	got, err := download(binary.Url, tmp)
+	tmp.Close()
+	if err != nil {
+		return err
+	}
+	if got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, expected %s", binary.Name, got, want)
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+
+	// a running executable cannot be overwritten on Windows, but it can be renamed
+	old := exe + ".old"
+	os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		os.Rename(old, exe)
+		return err
+	}
+	os.Remove(old)
+	return nil
+}
+
+// download writes url into dst and returns the hex sha256 of the content
+func download(url string, dst io.Writer) (string, error) {
+	resp, err := client.R().DisableAutoReadResponse().Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if !resp.IsSuccessState() {
+		return "", fmt.Errorf("download %s failed: %s", url, resp.Status)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(dst, hash), resp.Body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// expectedChecksum reads the sha256sum style checksums file at url and
+// returns the checksum of name
+func expectedChecksum(url string, name string) (string, error) {
+	var buf strings.Builder
+	if _, err := download(url, &buf); err != nil {
+		return "", err
+	}
+	scanner := bufio.NewScanner(strings.NewReader(buf.String()))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s has no checksum for %s", checksumsFile, name)
+}
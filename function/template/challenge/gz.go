@@ -0,0 +1,84 @@
+package challenge
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dimasma0305/ctfify/function/template"
+)
+
+// GZChallengeInfo fills the templates of a gzcli challenge scaffold
+type GZChallengeInfo struct {
+	Name      string
+	Author    string
+	Category  string
+	Type      string
+	Port      int
+	Container bool
+}
+
+// GZVariantType is the manifest type of the `ctfify add --gz-challenge`
+// scaffolds
+const GZVariantType = "gz-challenge"
+
+// GZVariants returns the categories `ctfify add --gz-challenge` can
+// scaffold, as listed in templates/manifest.yaml
+func GZVariants() ([]template.TemplateInfo, error) {
+	return template.List(GZVariantType, "")
+}
+
+// GZTypes are the challenge types a scaffold can be generated for
+var GZTypes = []string{"StaticAttachment", "DynamicContainer"}
+
+// GZChallenge writes a ready to sync challenge directory named after the
+// challenge into destination: challenge.yml for the chosen type, the service
+// sources with Dockerfile and docker-compose.yml, dist/ and solver/
+func GZChallenge(destination string, variant string, info GZChallengeInfo) error {
+	variants, err := GZVariants()
+	if err != nil {
+		return err
+	}
+	var v *template.TemplateInfo
+	names := make([]string, 0, len(variants))
+	for i := range variants {
+		if variants[i].Name == variant {
+			v = &variants[i]
+		}
+		names = append(names, variants[i].Name)
+	}
+	if v == nil {
+		sort.Strings(names)
+		return fmt.Errorf("unknown variant %q, use one of %s", variant, strings.Join(names, ", "))
+	}
+	if info.Name == "" {
+		return fmt.Errorf("a challenge name is required")
+	}
+	if info.Author == "" {
+		return fmt.Errorf("a challenge author is required")
+	}
+	if info.Type == "" {
+		info.Type = GZTypes[0]
+	}
+	validType := false
+	for _, t := range GZTypes {
+		validType = validType || t == info.Type
+	}
+	if !validType {
+		return fmt.Errorf("unknown type %q, use one of %s", info.Type, strings.Join(GZTypes, ", "))
+	}
+
+	info.Category = v.Category
+	info.Port = v.Port
+	info.Container = strings.HasSuffix(info.Type, "Container")
+
+	dir := filepath.Join(destination, strings.ReplaceAll(info.Name, " ", "-"))
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	template.TemplateToDestination("templates/challenges/gz/base", info, dir)
+	template.TemplateToDestination(v.Path, info, dir)
+	return nil
+}
@@ -0,0 +1,50 @@
+package template
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const manifestFile = "templates/manifest.yaml"
+
+// TemplateInfo describes an embedded template in templates/manifest.yaml
+type TemplateInfo struct {
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"`
+	Path        string   `yaml:"path"`
+	Description string   `yaml:"description"`
+	Variables   []string `yaml:"variables,omitempty"`
+	Category    string   `yaml:"category,omitempty"`
+	Port        int      `yaml:"port,omitempty"`
+}
+
+// List returns the templates of the given type (all when empty) whose name or
+// description contains search, case insensitively
+func List(kind string, search string) ([]TemplateInfo, error) {
+	data, err := File.ReadFile(manifestFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest []TemplateInfo
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", manifestFile, err)
+	}
+
+	search = strings.ToLower(search)
+	var templates []TemplateInfo
+	for _, info := range manifest {
+		if kind != "" && info.Type != kind {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(info.Name), search) &&
+			!strings.Contains(strings.ToLower(info.Description), search) {
+			continue
+		}
+		templates = append(templates, info)
+	}
+	return templates, nil
+}
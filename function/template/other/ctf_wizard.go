@@ -0,0 +1,244 @@
+package other
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dimasma0305/ctfify/function/log"
+	"github.com/dimasma0305/ctfify/function/template"
+)
+
+const ctfTemplateDir = "templates/others/ctf-template"
+
+// CTFTemplateCategories are the category folders shipped with the CTF template
+var CTFTemplateCategories = []string{
+	"Blockchain", "Crypto", "Forensics", "Misc", "Mobile",
+	"OSINT", "Pwn", "Reverse", "Web",
+}
+
+var containerProviders = []string{"Docker", "Kubernetes"}
+
+// CTFInfo holds every value the CTF template is rendered with
+type CTFInfo struct {
+	XorKey         string
+	PublicEntry    string
+	DiscordWebhook string
+	Url            string
+	Username       string
+	Password       string
+
+	Title             string
+	Start             string
+	End               string
+	Categories        []string
+	ContainerProvider string
+
+	EnableEmail   bool
+	SenderAddress string
+	SmtpHost      string
+	SmtpPort      int
+	SmtpUsername  string
+	SmtpPassword  string
+}
+
+// DefaultCTFInfo returns the values used when a question is left empty
+func DefaultCTFInfo() *CTFInfo {
+	start := time.Now().AddDate(0, 0, 7).Truncate(time.Hour).UTC()
+	return &CTFInfo{
+		XorKey:            randomize(16),
+		Username:          "admin",
+		Password:          "ADMIN" + randomize(16) + "ADMIN",
+		Title:             "Example CTF",
+		Start:             start.Format(time.RFC3339),
+		End:               start.Add(48 * time.Hour).Format(time.RFC3339),
+		Categories:        CTFTemplateCategories,
+		ContainerProvider: "Docker",
+		SenderAddress:     "example@gmail.com",
+		SmtpHost:          "smtp.gmail.com",
+		SmtpPort:          587,
+		SmtpUsername:      "example@gmail.com",
+		SmtpPassword:      "example",
+	}
+}
+
+// Validate checks the values that cannot be fixed by the template
+func (info *CTFInfo) Validate() error {
+	start, err := time.Parse(time.RFC3339, info.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start time: %w", err)
+	}
+	end, err := time.Parse(time.RFC3339, info.End)
+	if err != nil {
+		return fmt.Errorf("invalid end time: %w", err)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("end time must be after start time")
+	}
+	if len(info.Categories) == 0 {
+		return fmt.Errorf("at least one category is required")
+	}
+	if !containsFold(containerProviders, info.ContainerProvider) {
+		return fmt.Errorf("container provider must be one of %s", strings.Join(containerProviders, ", "))
+	}
+	if info.EnableEmail && (info.SmtpHost == "" || info.SmtpPort <= 0) {
+		return fmt.Errorf("SMTP host and port are required when email is enabled")
+	}
+	return nil
+}
+
+// Ask prompts for every setting, offering the current value as the default
+func (info *CTFInfo) Ask(in io.Reader, out io.Writer) {
+	p := &prompter{in: bufio.NewReader(in), out: out}
+
+	info.Url = p.ask("GZCTF URL", info.Url)
+	info.PublicEntry = p.ask("Public entry for challenge containers", info.PublicEntry)
+	info.DiscordWebhook = p.ask("Discord webhook", info.DiscordWebhook)
+	info.Title = p.ask("Event title", info.Title)
+	info.Start = p.askTime("Start time (RFC3339)", info.Start)
+	info.End = p.askTime("End time (RFC3339)", info.End)
+	info.Categories = splitList(p.ask("Categories (comma separated)", strings.Join(info.Categories, ",")))
+	for {
+		info.ContainerProvider = p.ask("Container provider ("+strings.Join(containerProviders, "/")+")", info.ContainerProvider)
+		if containsFold(containerProviders, info.ContainerProvider) || p.eof {
+			break
+		}
+		fmt.Fprintf(out, "Unknown container provider %q\n", info.ContainerProvider)
+	}
+
+	info.EnableEmail = p.askBool("Send credential emails over SMTP", info.EnableEmail)
+	if info.EnableEmail {
+		info.SenderAddress = p.ask("Sender address", info.SenderAddress)
+		info.SmtpHost = p.ask("SMTP host", info.SmtpHost)
+		for {
+			port, err := strconv.Atoi(p.ask("SMTP port", strconv.Itoa(info.SmtpPort)))
+			if err == nil && port > 0 {
+				info.SmtpPort = port
+				break
+			}
+			if p.eof {
+				break
+			}
+			fmt.Fprintln(out, "The port must be a positive number")
+		}
+		info.SmtpUsername = p.ask("SMTP username", info.SmtpUsername)
+		info.SmtpPassword = p.ask("SMTP password", info.SmtpPassword)
+	}
+}
+
+// GenerateCTF renders the CTF template into destination and creates one
+// folder for each selected category
+func GenerateCTF(destination string, info *CTFInfo) error {
+	if err := info.Validate(); err != nil {
+		return err
+	}
+	for i, provider := range containerProviders {
+		if strings.EqualFold(provider, info.ContainerProvider) {
+			info.ContainerProvider = containerProviders[i]
+		}
+	}
+
+	template.TemplateToDestination(ctfTemplateDir, info, destination)
+
+	for _, category := range CTFTemplateCategories {
+		if containsFold(info.Categories, category) {
+			continue
+		}
+		// only drop folders that still hold nothing but the placeholder
+		dir := filepath.Join(destination, category)
+		os.Remove(filepath.Join(dir, ".gitkeep"))
+		if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+			log.ErrorH2("Keep %s: %v", dir, err)
+		}
+	}
+	for _, category := range info.Categories {
+		dir := filepath.Join(destination, category)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, ".gitkeep"), nil, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CTFTemplate asks for the event settings on the terminal and renders the CTF template
+func CTFTemplate(destination string, _ any) {
+	info := DefaultCTFInfo()
+	info.Ask(os.Stdin, os.Stdout)
+	if err := GenerateCTF(destination, info); err != nil {
+		log.ErrorH2("%s", err)
+	}
+}
+
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	eof bool
+}
+
+func (p *prompter) ask(label, def string) string {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", label)
+	}
+	answer, err := p.in.ReadString('\n')
+	if err != nil {
+		// no more input, keep the defaults instead of asking forever
+		p.eof = true
+	}
+	if answer = strings.TrimSpace(answer); answer != "" {
+		return answer
+	}
+	return def
+}
+
+func (p *prompter) askTime(label, def string) string {
+	for {
+		answer := p.ask(label, def)
+		if _, err := time.Parse(time.RFC3339, answer); err == nil || p.eof {
+			return answer
+		}
+		fmt.Fprintf(p.out, "%q is not an RFC3339 time, e.g. 2025-01-01T12:00:00Z\n", answer)
+	}
+}
+
+func (p *prompter) askBool(label string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	switch strings.ToLower(p.ask(label+" ("+hint+")", "")) {
+	case "y", "yes":
+		return true
+	case "n", "no":
+		return false
+	}
+	return def
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
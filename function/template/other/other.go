@@ -3,7 +3,6 @@ package other
 import (
 	"crypto/rand"
 	"encoding/hex"
-	"fmt"
 
 	"github.com/dimasma0305/ctfify/function/template"
 )
@@ -24,15 +23,6 @@ func JavaExploitationPlus(destination string, info any) {
 	template.TemplateToDestination("templates/others/java-exploit-plus", info, destination)
 }
 
-type CTFInfo struct {
-	XorKey         string
-	PublicEntry    string
-	DiscordWebhook string
-	Url            string
-	Username       string
-	Password       string
-}
-
 func randomize(n int) string {
 	b := make([]byte, n)
 	_, err := rand.Read(b)
@@ -41,25 +31,3 @@ func randomize(n int) string {
 	}
 	return hex.EncodeToString(b)
 }
-
-func getUserInput(str string) string {
-	var input string
-	fmt.Print(str)
-	fmt.Scanln(&input)
-	return input
-}
-
-func CTFTemplate(destination string, info any) {
-	url := getUserInput("URL: ")
-	publicEntry := getUserInput("Public Entry: ")
-	discordWebhook := getUserInput("Discord Webhook: ")
-	ctfInfo := &CTFInfo{
-		XorKey:         randomize(16),
-		Username:       "admin",
-		Password:       "ADMIN" + randomize(16) + "ADMIN",
-		Url:            url,
-		PublicEntry:    publicEntry,
-		DiscordWebhook: discordWebhook,
-	}
-	template.TemplateToDestination("templates/others/ctf-template", ctfInfo, destination)
-}
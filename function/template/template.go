@@ -3,6 +3,7 @@ package template
 import (
 	"bytes"
 	"embed"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -19,6 +20,24 @@ var (
 	File embed.FS
 )
 
+// funcs quote the values of a template for the file format they land in,
+// e.g. "Password": {{json .SmtpPassword}} or title: {{yaml .Title}}
+var funcs = template.FuncMap{
+	"json": quoteJSON,
+	// A JSON string is a valid double quoted YAML scalar
+	"yaml": quoteJSON,
+}
+
+func quoteJSON(value any) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
 // TemplateToDestination reads a template from the embedded file system and writes it to the destination.
 // If it's a folder, it recursively writes its contents to the destination. If it's a file, it writes that file to the destination.
 func TemplateToDestination(file string, info interface{}, destination string) {
@@ -66,7 +85,7 @@ func processFile(file string, info interface{}, destination string) error {
 	var outputBuffer bytes.Buffer
 
 	// Parse the template
-	tmpl, err := template.ParseFS(File, file)
+	tmpl, err := template.New(filepath.Base(file)).Funcs(funcs).ParseFS(File, file)
 	if err != nil {
 		log.Error("error parsing the template: %s", err.Error())
 		log.Error("try to copy raw file")
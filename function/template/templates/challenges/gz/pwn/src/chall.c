@@ -0,0 +1,19 @@
+#include <stdio.h>
+#include <stdlib.h>
+#include <unistd.h>
+
+void win(void) {
+    system("cat /flag.txt");
+}
+
+int main(void) {
+    char name[64];
+
+    setvbuf(stdin, NULL, _IONBF, 0);
+    setvbuf(stdout, NULL, _IONBF, 0);
+
+    printf("What is your name? ");
+    read(0, name, 0x100);
+    printf("Hello %s\n", name);
+    return 0;
+}